@@ -0,0 +1,70 @@
+// canonicalize 命令行工具，按国家/地区和类型词典一次性回填已有视频的 country_json/tags_json
+// 用法：
+//
+//	go run ./cmd/canonicalize                          # 回填并重新统计筛选项
+//	go run ./cmd/canonicalize -dry-run                 # 预演：只统计需要更新的视频和词典中没有的取值，不修改数据
+//	go run ./cmd/canonicalize -dry-run -output a.json  # 报告写入文件
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"video-service/internal/service"
+	"video-service/pkg/infrastructure/cache"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/database"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "预演模式：只统计需要更新的视频，不修改数据")
+	output := flag.String("output", "", "报告输出文件路径（默认输出到标准输出）")
+	flag.Parse()
+
+	// 初始化配置、日志和数据库
+	config.InitConfig()
+	logger.InitLogger()
+	database.InitMySQL()
+	if database.DB == nil {
+		zap.L().Fatal("数据库未连接，请检查 mysql.dsn 配置")
+	}
+	// 回填后清除视频缓存和首页推荐位缓存（未配置Redis时跳过）
+	cache.InitRedis()
+
+	report, err := service.NewTaxonomyBackfillService().Run(*dryRun)
+	if err != nil {
+		zap.L().Fatal("规范化回填失败", zap.Error(err))
+	}
+
+	// 筛选项按规范代码重新统计
+	if !*dryRun {
+		if err := service.NewFilterService().Refresh(context.Background()); err != nil {
+			zap.L().Error("统计筛选项失败", zap.Error(err))
+		}
+		if report.Updated > 0 {
+			if err := service.NewHomeService().Invalidate(context.Background()); err != nil {
+				zap.L().Error("清除首页推荐位缓存失败", zap.Error(err))
+			}
+		}
+	}
+
+	// 输出报告
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		zap.L().Fatal("序列化报告失败", zap.Error(err))
+	}
+	if *output == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		zap.L().Fatal("写入报告失败", zap.String("output", *output), zap.Error(err))
+	}
+	zap.L().Info("报告已写入", zap.String("output", *output))
+}
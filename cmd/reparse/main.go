@@ -0,0 +1,84 @@
+// reparse 命令行工具，使用归档的豆瓣详情页离线重新解析并更新视频（不请求豆瓣）
+// 解析逻辑修复后，用于修正已有视频的数据；需要先配置 archive.storage 并由同步归档页面
+// 用法：
+//
+//	go run ./cmd/reparse                       # 重新解析全部归档页面
+//	go run ./cmd/reparse -type movie           # 只处理指定类型的视频（movie/tv/anime/tvshow/doc）
+//	go run ./cmd/reparse -output report.json   # 报告写入文件
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"video-service/internal/service"
+	"video-service/pkg/infrastructure/blobstore"
+	"video-service/pkg/infrastructure/cache"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/database"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	videoType := flag.String("type", "", "只处理指定类型的视频（默认全部）")
+	output := flag.String("output", "", "报告输出文件路径（默认输出到标准输出）")
+	flag.Parse()
+
+	// 初始化配置、日志、数据库、Redis和对象存储（archive.storage 为 blob 时归档保存在对象存储中）
+	config.InitConfig()
+	logger.InitLogger()
+	database.InitMySQL()
+	if database.DB == nil {
+		zap.L().Fatal("数据库未连接，请检查 mysql.dsn 配置")
+	}
+	cache.InitRedis()
+	blobstore.InitBlobStore()
+
+	svc, err := service.NewReparseService()
+	if err != nil {
+		zap.L().Fatal("初始化离线重新解析失败", zap.Error(err))
+	}
+
+	startedAt := time.Now()
+	report, err := svc.Run(*videoType)
+	if err != nil {
+		zap.L().Fatal("离线重新解析失败", zap.Error(err))
+	}
+
+	// 国家/地区和类型可能变化，重新统计筛选项
+	if report.Updated > 0 {
+		if err := service.NewFilterService().Refresh(context.Background()); err != nil {
+			zap.L().Error("统计筛选项失败", zap.Error(err))
+		}
+		// 标题和别名可能变化，更新搜索建议索引（未配置Redis时跳过）
+		if suggest := service.NewSuggestService(); suggest != nil {
+			if err := suggest.IndexUpdatedSince(startedAt); err != nil {
+				zap.L().Error("更新搜索建议索引失败", zap.Error(err))
+			}
+		}
+		// 首页推荐位中的视频信息可能变化
+		if err := service.NewHomeService().Invalidate(context.Background()); err != nil {
+			zap.L().Error("清除首页推荐位缓存失败", zap.Error(err))
+		}
+	}
+
+	// 输出报告
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		zap.L().Fatal("序列化报告失败", zap.Error(err))
+	}
+	if *output == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		zap.L().Fatal("写入报告失败", zap.String("output", *output), zap.Error(err))
+	}
+	zap.L().Info("报告已写入", zap.String("output", *output))
+}
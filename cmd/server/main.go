@@ -2,13 +2,18 @@
 package main
 
 import (
+	"context"
+
 	"video-service/internal/router"
+	"video-service/internal/service"
+	"video-service/pkg/infrastructure/blobstore"
 	"video-service/pkg/infrastructure/cache"
 	"video-service/pkg/infrastructure/config"
 	"video-service/pkg/infrastructure/database"
 	"video-service/pkg/infrastructure/logger"
 	"video-service/pkg/infrastructure/metrics"
 	"video-service/pkg/infrastructure/scheduler"
+	"video-service/pkg/infrastructure/tracing"
 
 	"go.uber.org/zap"
 )
@@ -17,11 +22,13 @@ import (
 // 按照以下顺序初始化各个组件：
 // 1. 配置管理（支持本地配置文件和Etcd远程配置）
 // 2. 日志系统（文件和控制台双输出）
-// 3. 数据库连接（MySQL，包含自动迁移）
-// 4. 缓存连接（Redis）
-// 5. 监控指标（Prometheus）
-// 6. 定时任务调度器
-// 7. HTTP路由和服务启动
+// 3. 分布式追踪（OpenTelemetry）
+// 4. 数据库连接（MySQL，包含自动迁移）
+// 5. 缓存连接（Redis）
+// 6. 对象存储（本地目录或S3兼容存储）
+// 7. 监控指标（Prometheus）
+// 8. 定时任务调度器
+// 9. HTTP路由和服务启动
 func main() {
 	// 初始化配置管理，支持从配置文件和环境变量读取，并可从Etcd获取敏感信息
 	config.InitConfig()
@@ -30,15 +37,32 @@ func main() {
 	logger.InitLogger()
 	log := zap.L()
 
+	// 初始化分布式追踪（W3C traceparent传播，按配置导出到OTLP或标准输出）
+	tracing.InitTracing()
+	defer tracing.Shutdown(context.Background())
+
 	// 初始化MySQL数据库连接，并执行自动迁移创建表结构
 	database.InitMySQL()
 
 	// 初始化Redis缓存连接
 	cache.InitRedis()
 
+	// 初始化对象存储（本地目录或S3兼容存储，用于封面镜像）
+	blobstore.InitBlobStore()
+
+	// 继续投递服务重启前未完成的Webhook
+	if database.DB != nil {
+		service.NewWebhookService().ResumePending()
+	}
+
 	// 初始化Prometheus监控指标
 	metrics.InitMetrics()
 
+	// 目录状态指标（视频、剧集的分组统计，后台定时刷新并缓存）
+	if database.DB != nil {
+		service.StartCatalogCollector()
+	}
+
 	// 启动定时任务调度器
 	scheduler.InitCron()
 	// 确保程序退出时停止定时任务
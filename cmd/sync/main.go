@@ -0,0 +1,89 @@
+// sync 命令行工具，用于在服务之外手动执行一次豆瓣同步
+// 用法：
+//
+//	go run ./cmd/sync                          # 执行一次完整同步
+//	go run ./cmd/sync -dry-run                 # 预演：只请求和解析数据，不写入MySQL，输出变更预览报告
+//	go run ./cmd/sync -dry-run -output a.json  # 预演报告写入文件
+//	go run ./cmd/sync -record ./fixtures       # 照常同步，并将所有外部请求录制为夹具
+//	go run ./cmd/sync -replay ./fixtures       # 只使用录制的夹具离线同步，不访问网络
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"video-service/internal/service"
+	"video-service/pkg/infrastructure/blobstore"
+	"video-service/pkg/infrastructure/cache"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/database"
+	"video-service/pkg/infrastructure/httpfixture"
+	"video-service/pkg/infrastructure/logger"
+	"video-service/pkg/infrastructure/tracing"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "预演模式：照常请求和解析数据，但不写入MySQL，输出变更预览报告")
+	output := flag.String("output", "", "预演报告输出文件路径（默认输出到标准输出）")
+	record := flag.String("record", "", "将外部请求录制为夹具保存到该目录（覆盖 sync.http_fixtures 配置）")
+	replay := flag.String("replay", "", "只从该目录的夹具回放外部请求，不访问网络（覆盖 sync.http_fixtures 配置）")
+	flag.Parse()
+	if *record != "" && *replay != "" {
+		fmt.Fprintln(os.Stderr, "-record 和 -replay 不能同时使用")
+		os.Exit(2)
+	}
+
+	// 初始化配置、日志、数据库和Redis（预演模式同样需要读取数据库中的现有数据）
+	config.InitConfig()
+	switch {
+	case *record != "":
+		config.Cfg.Set("sync.http_fixtures.mode", httpfixture.ModeRecord)
+		config.Cfg.Set("sync.http_fixtures.dir", *record)
+	case *replay != "":
+		config.Cfg.Set("sync.http_fixtures.mode", httpfixture.ModeReplay)
+		config.Cfg.Set("sync.http_fixtures.dir", *replay)
+	}
+	logger.InitLogger()
+	tracing.InitTracing()
+	defer tracing.Shutdown(context.Background())
+	database.InitMySQL()
+	if database.DB == nil {
+		zap.L().Fatal("数据库未连接，请检查 mysql.dsn 配置")
+	}
+	cache.InitRedis()
+	blobstore.InitBlobStore()
+
+	var svc *service.DoubanSyncService
+	if *dryRun {
+		svc = service.NewDoubanSyncDryRunService()
+	} else {
+		svc = service.NewDoubanSyncService()
+	}
+
+	if err := svc.SyncAll(); err != nil {
+		zap.L().Fatal("豆瓣同步失败", zap.String("run_id", svc.RunID()), zap.Error(err))
+	}
+
+	if !*dryRun {
+		return
+	}
+
+	// 输出预演报告
+	data, err := json.MarshalIndent(svc.DryRunReport(), "", "  ")
+	if err != nil {
+		zap.L().Fatal("序列化预演报告失败", zap.Error(err))
+	}
+	if *output == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		zap.L().Fatal("写入预演报告失败", zap.String("output", *output), zap.Error(err))
+	}
+	zap.L().Info("预演报告已写入", zap.String("output", *output))
+}
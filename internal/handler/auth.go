@@ -0,0 +1,86 @@
+// handler 包提供HTTP请求处理器
+// auth.go 提供用户注册、登录和退出登录的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/middleware"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Register 用户注册
+// @Summary 用户注册
+// @Description 用户名为4-15个字母或数字，密码至少6个字符且不超过72字节（UTF-8），注册的用户为普通用户，注册成功后需要登录获取token
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Param request body service.RegisterRequest true "用户名、密码、昵称（可选）、邮箱（可选）"
+// @Success 200 {object} response.Response "注册成功的用户"
+// @Failure 200 {object} response.Response "参数无效或用户名重复"
+// @Router /api/auth/register [post]
+func Register(c *gin.Context) {
+	var req service.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return
+	}
+
+	user, err := service.NewAuthService().Register(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, user)
+}
+
+// Login 用户登录
+// @Summary 用户登录
+// @Description 校验用户名和密码后返回token，并记录登录设备（device为空时使用User-Agent）和IP；有效token超过最大设备数（auth.max_devices）时停用最早登录的token
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Param request body service.LoginRequest true "用户名、密码、设备名称（可选）"
+// @Success 200 {object} response.Response "token、过期时间和用户信息"
+// @Failure 200 {object} response.Response "用户名或密码错误"
+// @Router /api/auth/login [post]
+func Login(c *gin.Context) {
+	var req service.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return
+	}
+
+	result, err := service.NewAuthService().Login(c.Request.Context(), &req, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 停用当前请求使用的token（需要 Authorization: Bearer <token>），其他设备的token不受影响
+// @Tags 用户
+// @Produce json
+// @Success 200 {object} response.Response "已退出登录"
+// @Router /api/auth/logout [post]
+func Logout(c *gin.Context) {
+	token := c.GetString(middleware.TokenKey)
+	if token == "" {
+		response.Error(c, errors.CodeUnauthorized, errors.MsgNotLoggedIn)
+		return
+	}
+
+	if err := service.NewAuthService().Logout(c.Request.Context(), token); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.SuccessMsg(c, "已退出登录", nil)
+}
@@ -0,0 +1,71 @@
+// handler 包提供HTTP请求处理器
+// catalog_change.go 提供目录变更记录（视频字段变更历史、回滚）相关的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ListVideoChanges 分页查询视频的变更历史
+// @Summary 查询视频变更历史
+// @Description 返回视频及其剧集的字段变更记录（旧值、新值、操作者、同步运行ID），最新的在前
+// @Tags 变更记录
+// @Produce json
+// @Param id path int true "视频ID"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页条数，默认20，最大100"
+// @Success 200 {object} response.Response "变更记录列表"
+// @Router /api/admin/videos/{id}/changes [get]
+func ListVideoChanges(c *gin.Context) {
+	videoID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	page, pageSize := parsePagination(c)
+
+	changes, total, err := service.NewCatalogChangeService().ListVideoChanges(c.Request.Context(), videoID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, response.PageData{
+		List:     changes,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// RevertCatalogChange 回滚一条视频字段变更
+// @Summary 回滚变更
+// @Description 将字段恢复为该变更之前的值，并记录一条新的变更；字段在该变更之后又被修改过时返回冲突
+// @Tags 变更记录
+// @Produce json
+// @Param id path int true "变更记录ID"
+// @Success 200 {object} response.Response "回滚产生的变更记录"
+// @Router /api/admin/changes/{id}/revert [post]
+func RevertCatalogChange(c *gin.Context) {
+	changeID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	actor := adminActor(c)
+	revert, err := service.NewCatalogChangeService().RevertChange(c.Request.Context(), changeID, actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("管理员回滚目录变更",
+		zap.Any("user", c.Value("user")),
+		zap.Int64("change_id", changeID),
+		zap.Int64("video_id", revert.VideoID),
+		zap.String("field", revert.Field))
+	response.Success(c, revert)
+}
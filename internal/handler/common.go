@@ -0,0 +1,64 @@
+// handler 包提供HTTP请求处理器
+// common.go 提供各处理器共用的参数解析和错误响应辅助函数
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strconv"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/pkg/infrastructure/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// parsePagination 解析分页参数（page从1开始，page_size默认20，最大100）
+func parsePagination(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
+
+// parseIDParam 解析路径中的int64 ID参数，解析失败时返回参数错误响应并返回false
+func parseIDParam(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondError 根据服务层返回的错误写入响应
+// 业务错误（*errors.BusinessError）使用其错误码和错误信息，其他错误统一返回服务器内部错误
+func respondError(c *gin.Context, err error) {
+	var bizErr *errors.BusinessError
+	if stderrors.As(err, &bizErr) {
+		response.Error(c, bizErr.GetCode(), bizErr.GetMessage())
+		return
+	}
+	response.Error(c, errors.CodeInternalErr, errors.MsgInternalError)
+}
+
+// requestLogger 返回当前请求的日志记录器（带 trace_id），后台goroutine中使用时需在处理器返回前获取
+func requestLogger(c *gin.Context) *zap.Logger {
+	return logger.FromContext(c.Request.Context())
+}
+
+// adminActor 返回当前管理员作为目录变更记录操作者的标识（"admin:用户名"）
+func adminActor(c *gin.Context) string {
+	return fmt.Sprintf("%s:%v", model.CatalogActorAdmin, c.Value("user"))
+}
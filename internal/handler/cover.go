@@ -0,0 +1,50 @@
+// handler 包提供HTTP请求处理器
+// cover.go 提供封面镜像相关的HTTP处理器
+package handler
+
+import (
+	"strconv"
+
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+	"video-service/pkg/infrastructure/logger"
+	"video-service/pkg/infrastructure/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MirrorCovers 手动触发封面镜像
+// @Summary 镜像封面
+// @Description 在后台镜像尚未镜像的封面（新视频优先），用于存量数据回填
+// @Tags 封面
+// @Produce json
+// @Param limit query int false "本次最多处理的视频数，默认500"
+// @Success 200 {object} response.Response "镜像任务已启动"
+// @Router /api/admin/covers/mirror [post]
+func MirrorCovers(c *gin.Context) {
+	coverService := service.NewCoverMirrorService()
+	if coverService == nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBlobStoreDisabled)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if err != nil || limit <= 0 {
+		limit = 500
+	}
+
+	log := requestLogger(c)
+	log.Info("手动触发封面镜像", zap.Any("user", c.Value("user")), zap.Int("limit", limit))
+
+	// 下载图片较慢，在后台执行（去掉请求的取消，日志保留 trace_id）
+	ctx := logger.WithContext(tracing.Detach(c.Request.Context()), log)
+	go func() {
+		if _, _, err := coverService.MirrorPending(ctx, limit); err != nil {
+			log.Error("封面镜像失败", zap.Error(err))
+		}
+	}()
+
+	response.SuccessMsg(c, "封面镜像任务已启动，正在后台执行", nil)
+}
@@ -0,0 +1,24 @@
+// handler 包提供HTTP请求处理器
+// dictionary.go 提供国家/地区和类型词典的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/pkg/dictionary"
+	"video-service/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDictionary 查询国家/地区和类型词典
+// @Summary 查询词典
+// @Description 返回国家/地区和类型的规范代码及中英文名称，视频的 country_json/tags_json 中存储的是规范代码
+// @Tags 筛选
+// @Produce json
+// @Success 200 {object} response.Response "词典"
+// @Router /api/dictionary [get]
+func GetDictionary(c *gin.Context) {
+	response.Success(c, gin.H{
+		"countries": dictionary.Countries.Entries(),
+		"genres":    dictionary.Genres.Entries(),
+	})
+}
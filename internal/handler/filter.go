@@ -0,0 +1,49 @@
+// handler 包提供HTTP请求处理器
+// filter.go 提供筛选项（标签/国家地区/年份）相关的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetFilters 查询指定视频类型的筛选项
+// @Summary 查询筛选项
+// @Description 返回指定类型已发布视频的标签、国家/地区和年份筛选项及视频数，每次同步结束后自动更新
+// @Tags 筛选
+// @Produce json
+// @Param type query string true "视频类型(movie/tv/tvshow等)"
+// @Success 200 {object} response.Response "筛选项"
+// @Router /api/filters [get]
+func GetFilters(c *gin.Context) {
+	filters, err := service.NewFilterService().GetFilters(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, filters)
+}
+
+// RefreshFilters 手动重新统计筛选项
+// @Summary 重新统计筛选项
+// @Description 根据当前已发布的视频重新统计所有类型的筛选项
+// @Tags 筛选
+// @Produce json
+// @Success 200 {object} response.Response "统计完成"
+// @Router /api/admin/filters/refresh [post]
+func RefreshFilters(c *gin.Context) {
+	requestLogger(c).Info("手动触发筛选项统计", zap.Any("user", c.Value("user")))
+
+	if err := service.NewFilterService().Refresh(c.Request.Context()); err != nil {
+		requestLogger(c).Error("统计筛选项失败", zap.Error(err))
+		response.Error(c, errors.CodeInternalErr, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "筛选项统计完成", nil)
+}
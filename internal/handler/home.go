@@ -0,0 +1,27 @@
+// handler 包提供HTTP请求处理器
+// home.go 提供首页推荐位相关的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetHome 查询首页推荐位
+// @Summary 查询首页推荐位
+// @Description 按配置（home.rails）的顺序返回首页推荐位及其视频摘要，如最近更新、热门电影、各类型高分、本周新增；每个推荐位单独缓存，同步有变更后自动清除
+// @Tags 视频
+// @Produce json
+// @Success 200 {object} response.Response "推荐位列表"
+// @Router /api/home [get]
+func GetHome(c *gin.Context) {
+	rails, err := service.NewHomeService().Rails(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"rails": rails})
+}
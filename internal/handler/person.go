@@ -0,0 +1,34 @@
+// handler 包提供HTTP请求处理器
+// person.go 提供人物（演职员）相关的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPerson 查询人物详情及作品列表
+// @Summary 查询人物详情
+// @Description 返回人物信息及其参与的已发布作品（按上映日期降序，同一作品的多个角色合并）
+// @Tags 人物
+// @Produce json
+// @Param id path int true "人物ID"
+// @Success 200 {object} response.Response "人物详情"
+// @Failure 200 {object} response.Response "人物不存在"
+// @Router /api/people/{id} [get]
+func GetPerson(c *gin.Context) {
+	personID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := service.NewPeopleService().GetPersonDetail(personID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, detail)
+}
@@ -0,0 +1,94 @@
+// handler 包提供HTTP请求处理器
+// search.go 提供站内搜索相关的HTTP处理器
+package handler
+
+import (
+	"strconv"
+
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SearchVideos 搜索视频
+// @Summary 搜索视频
+// @Description 按标题、别名（原名、又名）、简介、导演和演员全文搜索已发布的视频，标题完全相同的排在最前，其余按相关度、评分、上映日期降序；highlight 中命中部分用 <em></em> 包裹
+// @Tags 搜索
+// @Produce json
+// @Param q query string true "关键词"
+// @Param type query string false "视频类型(movie/tv/tvshow等)"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页条数，默认20，最大100"
+// @Success 200 {object} response.Response "搜索结果"
+// @Router /api/search [get]
+func SearchVideos(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+
+	results, total, err := service.NewSearchService().Search(c.Request.Context(), c.Query("q"), c.Query("type"), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, response.PageData{
+		List:     results,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// SuggestVideos 搜索建议
+// @Summary 搜索建议
+// @Description 按标题或别名的前缀返回候选视频（按评分降序），q可以是原文、全拼或拼音首字母（如 kb 匹配“狂飙”），不区分大小写
+// @Tags 搜索
+// @Produce json
+// @Param q query string true "输入的前缀"
+// @Param limit query int false "候选数，默认10，最大20"
+// @Success 200 {object} response.Response "候选视频"
+// @Router /api/search/suggest [get]
+func SuggestVideos(c *gin.Context) {
+	suggestService := service.NewSuggestService()
+	if suggestService == nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgSuggestDisabled)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	items, err := suggestService.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, items)
+}
+
+// RebuildSuggestions 重建搜索建议索引
+// @Summary 重建搜索建议索引
+// @Description 清空后为全部已发布视频重新建立搜索建议索引（同步会自动更新变化的视频，通常只在首次启用或索引异常时使用）
+// @Tags 搜索
+// @Produce json
+// @Success 200 {object} response.Response "建立索引的视频数"
+// @Router /api/admin/search/suggest/rebuild [post]
+func RebuildSuggestions(c *gin.Context) {
+	suggestService := service.NewSuggestService()
+	if suggestService == nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgSuggestDisabled)
+		return
+	}
+
+	requestLogger(c).Info("手动触发搜索建议索引重建", zap.Any("user", c.Value("user")))
+
+	indexed, err := suggestService.Rebuild()
+	if err != nil {
+		requestLogger(c).Error("重建搜索建议索引失败", zap.Error(err))
+		response.Error(c, errors.CodeInternalErr, err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "搜索建议索引重建完成", gin.H{"videos": indexed})
+}
@@ -0,0 +1,138 @@
+// handler 包提供HTTP请求处理器
+// series.go 提供剧集系列（多季）相关的HTTP处理器
+package handler
+
+import (
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetSeries 查询系列详情
+// @Summary 查询系列详情
+// @Description 返回系列信息及按季数升序排列的各季视频
+// @Tags 系列
+// @Produce json
+// @Param id path int true "系列ID"
+// @Success 200 {object} response.Response "系列详情"
+// @Failure 200 {object} response.Response "系列不存在"
+// @Router /api/series/{id} [get]
+func GetSeries(c *gin.Context) {
+	seriesID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := service.NewSeriesService().GetSeriesDetail(c.Request.Context(), seriesID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// SetVideoSeries 管理员手动设置视频的系列和季数
+// @Summary 设置视频系列
+// @Description 手动指定视频所属系列（series_id 或 series_title 二选一）和季数，设置后锁定，同步不再自动修改
+// @Tags 系列
+// @Accept json
+// @Produce json
+// @Param id path int true "视频ID"
+// @Param body body service.SetVideoSeriesRequest true "系列和季数"
+// @Success 200 {object} response.Response "更新后的视频"
+// @Router /api/admin/videos/{id}/series [put]
+func SetVideoSeries(c *gin.Context) {
+	videoID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req service.SetVideoSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return
+	}
+
+	video, err := service.NewSeriesService().WithActor(adminActor(c)).SetVideoSeries(c.Request.Context(), videoID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("管理员设置视频系列",
+		zap.Any("user", c.Value("user")),
+		zap.Int64("video_id", videoID),
+		zap.Int64p("series_id", video.SeriesID),
+		zap.Int64p("season_number", video.SeasonNumber))
+	response.Success(c, video)
+}
+
+// UnlinkVideoSeries 管理员取消视频的系列关联
+// @Summary 取消视频系列关联
+// @Description 取消关联并锁定，同步不再自动关联
+// @Tags 系列
+// @Produce json
+// @Param id path int true "视频ID"
+// @Success 200 {object} response.Response "更新后的视频"
+// @Router /api/admin/videos/{id}/series [delete]
+func UnlinkVideoSeries(c *gin.Context) {
+	videoID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	video, err := service.NewSeriesService().WithActor(adminActor(c)).UnlinkVideoSeries(c.Request.Context(), videoID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("管理员取消视频系列关联", zap.Any("user", c.Value("user")), zap.Int64("video_id", videoID))
+	response.Success(c, video)
+}
+
+// UnlockVideoSeries 管理员解除视频的系列锁定
+// @Summary 解除视频系列锁定
+// @Description 解除管理员锁定，并立即按标题中的季数标记重新自动关联
+// @Tags 系列
+// @Produce json
+// @Param id path int true "视频ID"
+// @Success 200 {object} response.Response "更新后的视频"
+// @Router /api/admin/videos/{id}/series/unlock [post]
+func UnlockVideoSeries(c *gin.Context) {
+	videoID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	video, err := service.NewSeriesService().WithActor(adminActor(c)).UnlockVideoSeries(c.Request.Context(), videoID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("管理员解除视频系列锁定", zap.Any("user", c.Value("user")), zap.Int64("video_id", videoID))
+	response.Success(c, video)
+}
+
+// RelinkSeries 对存量视频执行系列自动关联
+// @Summary 回填系列关联
+// @Description 对所有未关联且未锁定的视频按标题中的季数标记执行自动关联
+// @Tags 系列
+// @Produce json
+// @Success 200 {object} response.Response "新关联的视频数"
+// @Router /api/admin/series/relink [post]
+func RelinkSeries(c *gin.Context) {
+	linked, err := service.NewSeriesService().WithActor(adminActor(c)).RelinkAll(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("系列关联回填失败", zap.Error(err))
+		response.Error(c, errors.CodeInternalErr, errors.MsgSeriesUpdateFailed)
+		return
+	}
+
+	response.Success(c, gin.H{"linked": linked})
+}
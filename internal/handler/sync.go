@@ -2,8 +2,10 @@
 package handler
 
 import (
+	"video-service/internal/pkg/errors"
 	"video-service/internal/pkg/response"
 	"video-service/internal/service"
+	"video-service/pkg/infrastructure/tracing"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -19,20 +21,58 @@ import (
 // @Failure 500 {object} response.Response "同步失败"
 // @Router /api/sync/douban/movies [post]
 func SyncDoubanMovies(c *gin.Context) {
-	zap.L().Info("手动触发豆瓣同步", zap.String("ip", c.ClientIP()))
-
 	// 创建豆瓣同步服务
-	doubanSyncService := service.NewDoubanSyncService()
+	// 同步在请求返回后继续执行，只继承请求的span（不继承取消），使同步的span挂在该请求下
+	doubanSyncService := service.NewDoubanSyncService().WithContext(tracing.Detach(c.Request.Context()))
+
+	// 带上run_id，便于将请求日志与同步日志关联
+	log := requestLogger(c).With(zap.String("run_id", doubanSyncService.RunID()))
+	log.Info("手动触发豆瓣同步", zap.String("ip", c.ClientIP()))
 
 	// 执行同步（在goroutine中异步执行，避免阻塞请求）
 	go func() {
 		if err := doubanSyncService.SyncAll(); err != nil {
-			zap.L().Error("豆瓣同步失败", zap.Error(err))
+			log.Error("豆瓣同步失败", zap.Error(err))
 		} else {
-			zap.L().Info("豆瓣同步成功")
+			log.Info("豆瓣同步成功")
 		}
 	}()
 
 	// 立即返回响应
 	response.SuccessMsg(c, "同步任务已启动，正在后台执行", nil)
 }
+
+// SyncDoubanDryRun 手动触发豆瓣同步预演（dry-run）
+// @Summary 豆瓣同步预演
+// @Description 照常请求和解析豆瓣数据，但不写入MySQL，生成将要新建/更新的视频和将要插入的剧集报告
+// @Tags 同步
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response "预演任务已启动，返回run_id"
+// @Router /api/sync/douban/dry-run [post]
+func SyncDoubanDryRun(c *gin.Context) {
+	requestLogger(c).Info("手动触发豆瓣同步预演", zap.String("ip", c.ClientIP()))
+
+	// 预演同样耗时较长，在后台执行，通过run_id查询结果
+	run := service.StartDoubanDryRun()
+
+	response.SuccessMsg(c, "预演任务已启动，正在后台执行", run)
+}
+
+// GetDoubanDryRun 查询豆瓣同步预演结果
+// @Summary 查询同步预演结果
+// @Description 根据run_id查询预演任务状态，任务完成后返回变更预览报告
+// @Tags 同步
+// @Produce json
+// @Param run_id path string true "预演任务ID"
+// @Success 200 {object} response.Response "预演任务状态和报告"
+// @Failure 200 {object} response.Response "预演任务不存在"
+// @Router /api/sync/douban/dry-run/{run_id} [get]
+func GetDoubanDryRun(c *gin.Context) {
+	run, ok := service.GetDoubanDryRun(c.Param("run_id"))
+	if !ok {
+		response.Error(c, errors.CodeNotFound, errors.MsgDryRunNotFound)
+		return
+	}
+	response.Success(c, run)
+}
@@ -0,0 +1,155 @@
+// handler 包提供HTTP请求处理器
+// video.go 提供视频相关的HTTP处理器
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ListVideos 查询视频列表
+// @Summary 查询视频列表
+// @Description 按条件筛选已发布的视频，使用游标分页：首页不传cursor，之后传上一页返回的next_cursor，has_more为false表示没有更多数据
+// @Tags 视频
+// @Produce json
+// @Param type query string false "视频类型(movie/tv/tvshow等)"
+// @Param country query string false "国家/地区代码，如CN"
+// @Param tag query string false "类型代码，如drama"
+// @Param director query string false "导演"
+// @Param actor query string false "演员"
+// @Param year_from query int false "上映年份下限（含）"
+// @Param year_to query int false "上映年份上限（含）"
+// @Param score_min query number false "评分下限（含）"
+// @Param score_max query number false "评分上限（含）"
+// @Param is_completed query bool false "是否完结"
+// @Param is_update query bool false "是否有更新"
+// @Param sort query string false "排序：release_date（默认，上映日期降序）/ score（评分降序）/ updated（最近更新）"
+// @Param cursor query string false "分页游标"
+// @Param limit query int false "每页条数，默认20，最大100"
+// @Param If-None-Match header string false "上次响应的ETag"
+// @Success 200 {object} response.Response "视频列表"
+// @Success 304 "列表未变化"
+// @Router /api/videos [get]
+func ListVideos(c *gin.Context) {
+	var req service.VideoListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return
+	}
+
+	videos, nextCursor, err := service.NewVideoListService().List(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// 列表的ETag由查询参数和本页视频的ID、更新时间组成
+	// 视频移出列表（如下架）时本页其他视频的更新时间不变，因此列表不使用 Last-Modified
+	parts := []interface{}{c.Request.URL.RawQuery}
+	for _, video := range videos {
+		parts = append(parts, video.ID)
+		if video.UpdatedAt != nil {
+			parts = append(parts, *video.UpdatedAt)
+		}
+	}
+	if response.NotModified(c, response.ETag(parts...), time.Time{}) {
+		return
+	}
+
+	response.Success(c, response.CursorData{
+		List:       videos,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	})
+}
+
+// GetVideo 查询视频详情
+// @Summary 查询视频详情
+// @Description 返回已发布视频的完整信息及按集数排序的剧集（播放地址、字幕）；已被合并的旧视频ID返回规范视频的详情；fields 为逗号分隔的字段名，指定时只返回这些字段（id总是返回），不包含episodes时不查询剧集
+// @Tags 视频
+// @Produce json
+// @Param id path int true "视频ID"
+// @Param fields query string false "返回的字段，如 title,cover_url,episodes"
+// @Param If-None-Match header string false "上次响应的ETag"
+// @Param If-Modified-Since header string false "上次响应的Last-Modified"
+// @Success 200 {object} response.Response "视频详情"
+// @Success 304 "视频和剧集未变化"
+// @Failure 200 {object} response.Response "视频不存在或未发布"
+// @Router /api/videos/{id} [get]
+func GetVideo(c *gin.Context) {
+	videoID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	fields := service.ParseFields(c.Query("fields"))
+	detail, version, err := service.NewVideoDetailService().GetDetail(c.Request.Context(), videoID, fields)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	// 返回的字段不同时内容不同，ETag包含fields
+	etag := response.ETag(version.VideoID, version.UpdatedAt, version.EpisodeCount, strings.Join(fields, ","))
+	if response.NotModified(c, etag, version.UpdatedAt) {
+		return
+	}
+
+	response.Success(c, detail)
+}
+
+// ResolveVideo 解析视频ID
+// @Summary 解析视频ID
+// @Description 已被去重合并的旧视频ID返回合并后的规范视频ID，客户端持有的旧ID据此继续可用
+// @Tags 视频
+// @Produce json
+// @Param id path int true "视频ID"
+// @Success 200 {object} response.Response "规范视频ID及是否发生重定向"
+// @Failure 200 {object} response.Response "视频不存在"
+// @Router /api/videos/{id}/resolve [get]
+func ResolveVideo(c *gin.Context) {
+	videoID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	canonicalID, redirected, err := service.NewVideoDedupService().ResolveVideoID(videoID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"video_id":   canonicalID,
+		"redirected": redirected,
+	})
+}
+
+// DedupVideos 执行跨来源视频去重
+// @Summary 视频去重
+// @Description 按IMDb ID（或标题+年份+导演）聚类，将重复视频合并到规范视频；dry_run=true时只返回重复组，不修改数据
+// @Tags 视频
+// @Produce json
+// @Param dry_run query bool false "是否只预览，默认false"
+// @Success 200 {object} response.Response "去重报告"
+// @Router /api/admin/videos/dedup [post]
+func DedupVideos(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	requestLogger(c).Info("手动触发视频去重", zap.Any("user", c.Value("user")), zap.Bool("dry_run", dryRun))
+
+	report, err := service.NewVideoDedupService().Run(dryRun)
+	if err != nil {
+		requestLogger(c).Error("视频去重失败", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, report)
+}
@@ -0,0 +1,113 @@
+// handler 包提供HTTP请求处理器
+// webhook.go 提供Webhook投递记录查询相关的HTTP处理器
+package handler
+
+import (
+	stderrors "errors"
+	"strconv"
+
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+	"video-service/internal/repository"
+	"video-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListWebhookDeliveries 分页查询Webhook投递记录
+// @Summary 查询Webhook投递记录
+// @Description 按事件类型、端点名称、投递状态过滤，按ID降序分页返回
+// @Tags Webhook
+// @Produce json
+// @Param event query string false "事件类型（如 video.created）"
+// @Param endpoint query string false "端点名称"
+// @Param status query string false "投递状态（pending/success/failed）"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页条数，默认20，最大100"
+// @Success 200 {object} response.Response "投递记录列表"
+// @Router /api/admin/webhooks/deliveries [get]
+func ListWebhookDeliveries(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	filter := repository.WebhookDeliveryFilter{
+		Event:    c.Query("event"),
+		Endpoint: c.Query("endpoint"),
+		Status:   c.Query("status"),
+	}
+
+	deliveries, total, err := service.ListWebhookDeliveries(filter, page, pageSize)
+	if err != nil {
+		requestLogger(c).Error("查询Webhook投递记录失败", zap.Error(err))
+		response.Error(c, errors.CodeInternalErr, errors.MsgWebhookDeliveryQueryFailed)
+		return
+	}
+
+	response.Success(c, response.PageData{
+		List:     deliveries,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// GetWebhookDelivery 查询单条Webhook投递记录
+// @Summary 查询Webhook投递记录详情
+// @Tags Webhook
+// @Produce json
+// @Param id path int true "投递记录ID"
+// @Success 200 {object} response.Response "投递记录"
+// @Router /api/admin/webhooks/deliveries/{id} [get]
+func GetWebhookDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return
+	}
+
+	delivery, err := service.GetWebhookDelivery(id)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, errors.CodeNotFound, errors.MsgWebhookDeliveryNotFound)
+			return
+		}
+		requestLogger(c).Error("查询Webhook投递记录失败", zap.Int64("id", id), zap.Error(err))
+		response.Error(c, errors.CodeInternalErr, errors.MsgWebhookDeliveryQueryFailed)
+		return
+	}
+
+	response.Success(c, delivery)
+}
+
+// RedeliverWebhook 重新投递指定的Webhook记录
+// @Summary 重新投递Webhook
+// @Description 重置尝试次数并在后台重新投递，只能重新投递已结束（success/failed）的记录
+// @Tags Webhook
+// @Produce json
+// @Param id path int true "投递记录ID"
+// @Success 200 {object} response.Response "已开始重新投递"
+// @Router /api/admin/webhooks/deliveries/{id}/redeliver [post]
+func RedeliverWebhook(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(c, errors.CodeBadRequest, errors.MsgBadRequest)
+		return
+	}
+
+	delivery, err := service.NewWebhookService().Redeliver(c.Request.Context(), id)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			response.Error(c, errors.CodeNotFound, errors.MsgWebhookDeliveryNotFound)
+			return
+		}
+		if stderrors.Is(err, errors.ErrWebhookDeliveryInProgress) {
+			respondError(c, err)
+			return
+		}
+		requestLogger(c).Error("重新投递Webhook失败", zap.Int64("id", id), zap.Error(err))
+		response.Error(c, errors.CodeInternalErr, errors.MsgWebhookRedeliverFailed+": "+err.Error())
+		return
+	}
+
+	response.SuccessMsg(c, "已开始重新投递", delivery)
+}
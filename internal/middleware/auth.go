@@ -1,24 +1,38 @@
 // middleware 包提供HTTP请求中间件
-// JWTAuth 提供JWT认证功能
+// JWTAuth 提供JWT认证功能，AdminAuth 限制只有管理员可以访问
 package middleware
 
 import (
 	"strings"
+	"video-service/internal/model"
 	"video-service/internal/pkg/auth"
 	"video-service/internal/pkg/errors"
 	"video-service/internal/pkg/response"
+	"video-service/pkg/infrastructure/logger"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
+// TokenKey 是context中存储当前请求token的键名（用于退出登录）
+const TokenKey = "token"
+
+// RoleKey 是context中存储当前用户角色的键名
+const RoleKey = "role"
+
+// TokenChecker 检查token是否已停用并返回用户当前角色（由认证服务实现，路由初始化时注入）
+type TokenChecker interface {
+	CheckToken(token string) (role string, revoked bool, err error)
+}
+
 // JWTAuth 返回一个JWT认证中间件
 // 功能：
 // 1. 从请求头中提取JWT token
 // 2. 验证token的签名和过期时间
-// 3. 解析token中的用户信息并存储到context中
-// 4. 如果认证失败，返回401错误
-// 注意：此中间件仅做JWT签名验证，如需数据库验证token状态，请在业务层实现
-func JWTAuth() gin.HandlerFunc {
+// 3. 通过checker检查token是否已停用（退出登录、超过最大设备数被停用或没有登录记录）
+// 4. 将token中的用户信息和数据库中的当前角色存储到context中（取消管理员后立即生效，不必等token过期）
+// 5. 如果认证失败，返回401错误
+func JWTAuth(checker TokenChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从Authorization请求头中获取token
 		// 格式: Authorization: Bearer <token>
@@ -48,11 +62,42 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// 检查登录记录中的token状态
+		role, revoked, err := checker.CheckToken(tokenString)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("查询token状态失败", zap.Error(err))
+			response.Error(c, errors.CodeInternalErr, errors.MsgInternalError)
+			c.Abort()
+			return
+		}
+		if revoked {
+			response.Error(c, errors.CodeUnauthorized, errors.MsgTokenInvalid)
+			c.Abort()
+			return
+		}
+
 		// 将用户名存储到context中，供后续处理器使用
 		c.Set("user", claims.Username)
 		c.Set("user_id", claims.Username) // 可以根据需要添加更多用户信息
+		c.Set(RoleKey, role)
+		c.Set(TokenKey, tokenString)
 
 		// 认证成功，继续处理请求
 		c.Next()
 	}
 }
+
+// AdminAuth 返回一个管理员权限中间件，需要在 JWTAuth 之后使用
+// 用户当前角色（由 JWTAuth 从数据库读取）不是管理员时返回403错误
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(RoleKey) != model.UserRoleAdmin {
+			logger.FromContext(c.Request.Context()).Warn("非管理员访问管理接口",
+				zap.Any("user", c.Value("user")), zap.String("path", c.Request.URL.Path))
+			response.Error(c, errors.CodeForbidden, errors.MsgAdminRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
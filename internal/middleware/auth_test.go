@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/auth"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTokenChecker 记录已停用token和token所属用户当前角色的检查器
+type fakeTokenChecker struct {
+	roles   map[string]string
+	revoked map[string]bool
+	err     error
+}
+
+// CheckToken 实现 TokenChecker
+func (f *fakeTokenChecker) CheckToken(token string) (string, bool, error) {
+	return f.roles[token], f.revoked[token], f.err
+}
+
+// newAuthTestRouter 创建带 /user（只需登录）和 /admin（需要管理员）两个路由的测试路由
+func newAuthTestRouter(checker TokenChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(c *gin.Context) {
+		response.Success(c, gin.H{"user": c.GetString("user"), "role": c.GetString(RoleKey)})
+	}
+	r.GET("/user", JWTAuth(checker), ok)
+	r.GET("/admin", JWTAuth(checker), AdminAuth(), ok)
+	return r
+}
+
+// serveAuth 发起请求并返回响应的业务状态码
+func serveAuth(t *testing.T, r *gin.Engine, path, authorization string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp response.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v (%s)", err, w.Body.String())
+	}
+	return resp.Code
+}
+
+// mustToken 生成测试token
+func mustToken(t *testing.T, username, role string) string {
+	t.Helper()
+	token, err := auth.GenerateToken(username, role, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("生成token失败: %v", err)
+	}
+	return token
+}
+
+func TestJWTAuth(t *testing.T) {
+	userToken := mustToken(t, "tvuser01", model.UserRoleUser)
+	revokedToken := mustToken(t, "tvuser01", model.UserRoleUser)
+	expiredToken, err := auth.GenerateToken("tvuser01", model.UserRoleUser, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("生成token失败: %v", err)
+	}
+	r := newAuthTestRouter(&fakeTokenChecker{revoked: map[string]bool{revokedToken: true}})
+
+	cases := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"缺少请求头", "", errors.CodeUnauthorized},
+		{"格式错误", "Token " + userToken, errors.CodeUnauthorized},
+		{"签名无效", "Bearer " + userToken + "x", errors.CodeUnauthorized},
+		{"已过期", "Bearer " + expiredToken, errors.CodeUnauthorized},
+		{"已停用", "Bearer " + revokedToken, errors.CodeUnauthorized},
+		{"有效", "Bearer " + userToken, errors.CodeSuccess},
+	}
+	for _, c := range cases {
+		if got := serveAuth(t, r, "/user", c.authorization); got != c.want {
+			t.Errorf("%s: code = %d, 期望 %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestJWTAuthCheckerError(t *testing.T) {
+	r := newAuthTestRouter(&fakeTokenChecker{err: stderrors.New("db down")})
+	if got := serveAuth(t, r, "/user", "Bearer "+mustToken(t, "tvuser01", model.UserRoleUser)); got != errors.CodeInternalErr {
+		t.Errorf("查询token状态失败时 code = %d, 期望 %d", got, errors.CodeInternalErr)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	userToken := mustToken(t, "tvuser01", model.UserRoleUser)
+	adminToken := mustToken(t, "admin01", model.UserRoleAdmin)
+	revokedAdminToken := mustToken(t, "admin01", model.UserRoleAdmin)
+	demotedToken := mustToken(t, "admin02", model.UserRoleAdmin)
+	promotedToken := mustToken(t, "tvuser02", model.UserRoleUser)
+	r := newAuthTestRouter(&fakeTokenChecker{
+		roles: map[string]string{
+			userToken:         model.UserRoleUser,
+			adminToken:        model.UserRoleAdmin,
+			revokedAdminToken: model.UserRoleAdmin,
+			demotedToken:      model.UserRoleUser,
+			promotedToken:     model.UserRoleAdmin,
+		},
+		revoked: map[string]bool{revokedAdminToken: true},
+	})
+
+	cases := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		// 以数据库中的当前角色为准，token中的角色不影响判断
+		{"普通用户", userToken, errors.CodeForbidden},
+		{"没有角色", mustToken(t, "tvuser01", ""), errors.CodeForbidden},
+		{"已取消管理员", demotedToken, errors.CodeForbidden},
+		{"管理员已停用", revokedAdminToken, errors.CodeUnauthorized},
+		{"管理员", adminToken, errors.CodeSuccess},
+		{"授权后未重新登录", promotedToken, errors.CodeSuccess},
+	}
+	for _, c := range cases {
+		if got := serveAuth(t, r, "/admin", "Bearer "+c.token); got != c.want {
+			t.Errorf("%s: code = %d, 期望 %d", c.name, got, c.want)
+		}
+	}
+}
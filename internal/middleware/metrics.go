@@ -0,0 +1,36 @@
+// middleware 包提供HTTP请求中间件
+// Metrics 提供HTTP请求的Prometheus指标记录功能
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"video-service/pkg/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 返回一个HTTP请求指标中间件
+// 功能：按请求方法、路由模板和状态码记录请求总数和耗时（metrics.HTTPRequestsTotal / HTTPRequestDuration）
+// 使用路由模板（如 /api/videos/:id）而不是实际路径作为 endpoint，避免指标基数无限增长；未匹配到路由的请求记为 unmatched
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		// 跳过 /metrics 路径（Prometheus 监控请求）
+		if endpoint == "/metrics" {
+			return
+		}
+
+		method := c.Request.Method
+		metrics.HTTPRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, endpoint).Observe(time.Since(start).Seconds())
+	}
+}
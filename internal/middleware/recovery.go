@@ -30,10 +30,11 @@ func RecoveryWithZap(logger *zap.Logger) gin.HandlerFunc {
 			if r := recover(); r != nil {
 				// 记录panic详细信息到日志
 				logger.Error("panic recovered",
-					zap.Any("error", r),                        // panic的错误内容
-					zap.ByteString("stack", debug.Stack()),     // 完整的堆栈跟踪
-					zap.String("path", c.Request.URL.Path),     // 请求路径
-					zap.Duration("elapsed", time.Since(start)), // 请求处理耗时
+					zap.Any("error", r),                             // panic的错误内容
+					zap.ByteString("stack", debug.Stack()),          // 完整的堆栈跟踪
+					zap.String("path", c.Request.URL.Path),          // 请求路径
+					zap.String(TraceIDKey, c.GetString(TraceIDKey)), // 追踪ID
+					zap.Duration("elapsed", time.Since(start)),      // 请求处理耗时
 				)
 
 				// 返回统一的错误响应给客户端
@@ -3,8 +3,12 @@
 package middleware
 
 import (
+	"video-service/pkg/infrastructure/logger"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
 // TraceIDKey 是context中存储追踪ID的键名
@@ -18,14 +22,21 @@ func Trace() gin.HandlerFunc {
 		// 尝试从请求头中获取追踪ID（如果上游服务已设置）
 		traceID := c.GetHeader("X-Trace-ID")
 
-		// 如果请求头中没有追踪ID，则生成一个新的UUID
+		// 如果请求头中没有追踪ID，优先使用OpenTelemetry的trace ID（与span关联），否则生成一个新的UUID
 		if traceID == "" {
-			traceID = uuid.New().String()
+			if sc := trace.SpanContextFromContext(c.Request.Context()); sc.HasTraceID() {
+				traceID = sc.TraceID().String()
+			} else {
+				traceID = uuid.New().String()
+			}
 		}
 
 		// 将追踪ID存储到context中，供后续中间件和处理器使用
 		c.Set(TraceIDKey, traceID)
 
+		// 将带 trace_id 的日志记录器放入请求上下文，处理器通过 logger.FromContext 获取
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), zap.L().With(zap.String(TraceIDKey, traceID))))
+
 		// 将追踪ID添加到响应头中，方便客户端追踪
 		c.Header("X-Trace-ID", traceID)
 
@@ -16,6 +16,7 @@ type User struct {
 	Password       string     `gorm:"column:password;size:255;not null;comment:密码(加密存储)" json:"-"`
 	Nickname       string     `gorm:"size:100;comment:昵称" json:"nickname"`
 	Email          string     `gorm:"size:255;comment:邮箱地址" json:"email"`
+	Role           string     `gorm:"size:20;not null;default:user;comment:角色：user普通用户，admin管理员" json:"role"`
 	Avatar         string     `gorm:"type:text;comment:头像URL" json:"avatar"`
 	AccWeb         string     `gorm:"column:acc_web;size:255;comment:Web端访问码" json:"acc_web"`
 	AccWebCreateAt *time.Time `gorm:"column:acc_web_create_at;comment:Web端访问码创建时间" json:"acc_web_create_at"`
@@ -71,8 +72,17 @@ type Video struct {
 	EpisodeCount *int64         `gorm:"column:episode_count;comment:集数" json:"episode_count"`
 	IsCompleted  bool           `gorm:"column:is_completed;default:0;comment:是否完结(0:未完结,1:已完结)" json:"is_completed"`
 	IsUpdate     bool           `gorm:"column:is_update;default:0;comment:是否有更新(0:无更新,1:有更新)" json:"is_update"`
-	CreatedAt    *time.Time     `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
-	UpdatedAt    *time.Time     `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
+	SeriesID     *int64         `gorm:"column:series_id;index;comment:所属系列ID（多季剧集的同一系列）" json:"series_id"`
+	SeasonNumber *int64         `gorm:"column:season_number;comment:季数（从1开始）" json:"season_number"`
+	SeriesLocked bool           `gorm:"column:series_locked;default:0;comment:系列关联是否由管理员锁定(0:否,1:是)，锁定后同步不再自动修改" json:"series_locked"`
+	// 封面镜像：镜像成功后 CoverURL 替换为自有存储地址，原始地址保存在 CoverSourceURL
+	CoverSourceURL      string         `gorm:"column:cover_source_url;type:text;comment:封面原始地址（镜像前的来源站点地址）" json:"cover_source_url"`
+	CoverThumbsJSON     datatypes.JSON `gorm:"column:cover_thumbs_json;type:json;comment:封面缩略图地址（JSON对象，键为宽度如w320）" json:"cover_thumbs_json"`
+	CoverMirroredAt     *time.Time     `gorm:"column:cover_mirrored_at;comment:封面镜像时间" json:"cover_mirrored_at"`
+	CoverMirrorFailures int            `gorm:"column:cover_mirror_failures;default:0;comment:封面镜像连续失败次数" json:"-"`
+	CreditsCheckedAt    *time.Time     `gorm:"column:credits_checked_at;comment:最近一次为补充演职员请求详情页的时间" json:"-"`
+	CreatedAt           *time.Time     `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+	UpdatedAt           *time.Time     `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -80,6 +90,123 @@ func (Video) TableName() string {
 	return "videos"
 }
 
+// Series 系列模型
+// 将同一部剧的多季（如"XX 第一季"、"XX 第二季"）关联到一起，每一季仍是独立的Video
+type Series struct {
+	ID          int64      `gorm:"primaryKey;comment:系列ID，使用雪花算法生成（非自增主键）" json:"id"`
+	Title       string     `gorm:"size:255;not null;uniqueIndex:idx_series_title_type;comment:系列标题（去掉季数标记后的标题）" json:"title"`
+	Type        string     `gorm:"size:32;uniqueIndex:idx_series_title_type;comment:视频类型(movie/tv/tvshow等)" json:"type"`
+	CoverURL    string     `gorm:"column:cover_url;type:text;comment:封面图片地址" json:"cover_url"`
+	Description string     `gorm:"type:text;comment:系列简介" json:"description"`
+	CreatedAt   *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+	UpdatedAt   *time.Time `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Series) TableName() string {
+	return "series"
+}
+
+// VideoRedirect 视频重定向模型
+// 重复视频被合并后删除，保留旧ID到规范视频ID的映射，持有旧ID的客户端仍可解析到合并后的视频
+type VideoRedirect struct {
+	FromVideoID int64      `gorm:"column:from_video_id;primaryKey;autoIncrement:false;comment:被合并（已删除）的视频ID" json:"from_video_id"`
+	ToVideoID   int64      `gorm:"column:to_video_id;index;not null;comment:合并后的规范视频ID" json:"to_video_id"`
+	Source      string     `gorm:"size:255;comment:被合并视频的来源(如:douban、xiaoya)" json:"source"`
+	SourceID    *int64     `gorm:"column:source_id;index;comment:被合并视频的来源站点ID（同步时据此跳过已合并的视频）" json:"source_id"`
+	Reason      string     `gorm:"size:32;comment:合并依据(imdb/title_year_director)" json:"reason"`
+	CreatedAt   *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+}
+
+// TableName 指定表名
+func (VideoRedirect) TableName() string {
+	return "video_redirects"
+}
+
+// Person 人物模型
+// 存储导演、编剧、演员等演职人员，来源于豆瓣详情页中的 /celebrity/ 或 /personage/ 链接
+type Person struct {
+	ID                int64      `gorm:"primaryKey;comment:人物ID，使用雪花算法生成（非自增主键）" json:"id"`
+	Name              string     `gorm:"size:255;index;not null;comment:姓名" json:"name"`
+	DoubanID          *int64     `gorm:"column:douban_id;uniqueIndex;comment:豆瓣影人ID（/celebrity/{id}/）" json:"douban_id"`
+	DoubanPersonageID *int64     `gorm:"column:douban_personage_id;uniqueIndex;comment:豆瓣人物ID（/personage/{id}/）" json:"douban_personage_id"`
+	CreatedAt         *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+	UpdatedAt         *time.Time `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Person) TableName() string {
+	return "people"
+}
+
+// 演职员角色
+const (
+	CreditRoleDirector = "director" // 导演
+	CreditRoleWriter   = "writer"   // 编剧
+	CreditRoleActor    = "actor"    // 演员
+)
+
+// VideoCredit 视频演职员模型
+// 记录人物在视频中担任的角色和署名顺序，videos 表中的 director_json/actors_json 作为筛选索引的冗余缓存保留
+type VideoCredit struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement;comment:演职员记录ID" json:"id"`
+	VideoID      int64      `gorm:"column:video_id;not null;uniqueIndex:idx_video_credits_video_person_role;comment:视频ID" json:"video_id"`
+	PersonID     int64      `gorm:"column:person_id;not null;index;uniqueIndex:idx_video_credits_video_person_role;comment:人物ID" json:"person_id"`
+	Role         string     `gorm:"size:16;not null;uniqueIndex:idx_video_credits_video_person_role;comment:角色(director/writer/actor)" json:"role"`
+	BillingOrder int        `gorm:"column:billing_order;default:0;comment:署名顺序（同一角色内从0开始）" json:"billing_order"`
+	CreatedAt    *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+}
+
+// TableName 指定表名
+func (VideoCredit) TableName() string {
+	return "video_credits"
+}
+
+// 别名类型
+const (
+	VideoTitleKindOriginal = "original" // 原名（原语言标题）
+	VideoTitleKindAlias    = "alias"    // 又名
+)
+
+// VideoTitle 视频别名模型
+// 存储详情页中的原名和又名，用于播放地址搜索匹配和站内搜索
+type VideoTitle struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement;comment:别名记录ID" json:"id"`
+	VideoID   int64      `gorm:"column:video_id;not null;uniqueIndex:idx_video_titles_video_title;comment:视频ID" json:"video_id"`
+	Title     string     `gorm:"size:255;not null;uniqueIndex:idx_video_titles_video_title;index;comment:别名" json:"title"`
+	Language  string     `gorm:"size:16;comment:语言(zh/zh-TW/zh-HK/ja/ko/en，根据文字和地区标记推断，未知为空)" json:"language"`
+	Kind      string     `gorm:"size:16;not null;comment:别名类型(original:原名 alias:又名)" json:"kind"`
+	CreatedAt *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+}
+
+// TableName 指定表名
+func (VideoTitle) TableName() string {
+	return "video_titles"
+}
+
+// 日期类型
+const (
+	ReleaseDateKindRelease  = "release"  // 上映日期（电影）
+	ReleaseDateKindPremiere = "premiere" // 首播（电视剧、综艺等）
+)
+
+// VideoReleaseDate 视频各地区上映日期模型
+// 保存详情页中"上映日期"/"首播"的每一条记录，videos.release_date 按地区偏好从中选出
+type VideoReleaseDate struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement;comment:上映日期记录ID" json:"id"`
+	VideoID     int64      `gorm:"column:video_id;not null;uniqueIndex:idx_video_release_dates_video_date_region;comment:视频ID" json:"video_id"`
+	ReleaseDate time.Time  `gorm:"column:release_date;type:date;not null;uniqueIndex:idx_video_release_dates_video_date_region;comment:上映日期" json:"release_date"`
+	Region      string     `gorm:"size:64;uniqueIndex:idx_video_release_dates_video_date_region;comment:地区或场合（原文，如中国大陆、戛纳电影节）" json:"region"`
+	Country     string     `gorm:"size:8;comment:国家/地区规范代码（地区不是国家/地区时为空）" json:"country"`
+	Kind        string     `gorm:"size:16;not null;comment:日期类型(release:上映 premiere:首播)" json:"kind"`
+	CreatedAt   *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+}
+
+// TableName 指定表名
+func (VideoReleaseDate) TableName() string {
+	return "video_release_dates"
+}
+
 // Episode 剧集/集数模型
 // 存储视频的每一集信息，一个Video可以有多个Episode
 type Episode struct {
@@ -101,6 +228,82 @@ func (Episode) TableName() string {
 	return "episodes"
 }
 
+// 变更记录的实体类型
+const (
+	CatalogEntityVideo   = "video"   // videos 表
+	CatalogEntityEpisode = "episode" // episodes 表
+)
+
+// 用户角色，只有管理员可以访问管理接口（/api/admin）
+const (
+	UserRoleUser  = "user"  // 普通用户（注册的用户）
+	UserRoleAdmin = "admin" // 管理员
+)
+
+// 变更记录的操作者（管理员为 "admin:用户名"）
+const (
+	CatalogActorSync    = "sync"    // 豆瓣同步
+	CatalogActorSystem  = "system"  // 回填命令、封面镜像回填等后台任务
+	CatalogActorReparse = "reparse" // 归档页面的离线重新解析
+	CatalogActorDedup   = "dedup"   // 跨来源视频去重合并
+	CatalogActorAdmin   = "admin"   // 管理员（前缀）
+)
+
+// 记录新建或删除（而非修改字段）时使用的字段名
+const (
+	CatalogFieldCreated = "*created" // 新值为新建时的字段快照
+	CatalogFieldDeleted = "*deleted" // 旧值为删除前的字段快照
+)
+
+// CatalogChange 目录变更记录模型
+// 记录 videos/episodes 每个字段的变更（旧值、新值、操作者），用于追溯数据来源和回滚
+type CatalogChange struct {
+	ID         int64          `gorm:"primaryKey;autoIncrement;comment:变更记录ID" json:"id"`
+	EntityType string         `gorm:"size:16;not null;index:idx_catalog_changes_entity;comment:实体类型(video/episode)" json:"entity_type"`
+	EntityID   int64          `gorm:"column:entity_id;not null;index:idx_catalog_changes_entity;comment:实体ID(视频ID或剧集ID)" json:"entity_id"`
+	VideoID    int64          `gorm:"column:video_id;not null;index;comment:所属视频ID(剧集的变更也记录所属视频，用于查询视频的完整历史)" json:"video_id"`
+	Field      string         `gorm:"size:64;not null;comment:字段名(*created表示新建)" json:"field"`
+	OldValue   datatypes.JSON `gorm:"column:old_value;type:json;comment:旧值(JSON格式，NULL表示无值)" json:"old_value"`
+	NewValue   datatypes.JSON `gorm:"column:new_value;type:json;comment:新值(JSON格式，NULL表示无值)" json:"new_value"`
+	Actor      string         `gorm:"size:100;not null;comment:操作者(sync/reparse/system/admin:用户名)" json:"actor"`
+	SyncRunID  string         `gorm:"column:sync_run_id;size:36;index;comment:同步运行ID(操作者为sync或reparse时)" json:"sync_run_id,omitempty"`
+	RevertOf   *int64         `gorm:"column:revert_of;comment:回滚的变更记录ID(此记录由回滚产生时)" json:"revert_of,omitempty"`
+	CreatedAt  *time.Time     `gorm:"autoCreateTime;comment:变更时间" json:"created_at"`
+}
+
+// TableName 指定表名
+func (CatalogChange) TableName() string {
+	return "catalog_changes"
+}
+
+// 归档页面类型
+const (
+	PageArchiveKindDetail = "detail" // 详情页
+	PageArchiveKindList   = "list"   // 列表接口中的单个条目（JSON）
+	PageArchiveKindSearch = "search" // 匹配到播放地址的搜索接口响应（JSON），来源ID为视频ID
+)
+
+// PageArchive 页面归档索引模型
+// 同步请求到的原始页面压缩后保存在归档存储中，这里记录每个来源ID最近一次请求的存储位置和请求时间
+type PageArchive struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement;comment:归档记录ID" json:"id"`
+	Source         string     `gorm:"size:32;not null;uniqueIndex:idx_page_archives_source_kind;comment:来源站点(如:douban)" json:"source"`
+	SourceID       int64      `gorm:"column:source_id;not null;uniqueIndex:idx_page_archives_source_kind;comment:来源站点的视频ID" json:"source_id"`
+	Kind           string     `gorm:"size:16;not null;uniqueIndex:idx_page_archives_source_kind;comment:页面类型(detail:详情页,list:列表条目,search:播放地址搜索结果)" json:"kind"`
+	StorageKey     string     `gorm:"column:storage_key;size:255;not null;comment:归档存储中的对象键(gzip压缩)" json:"storage_key"`
+	ContentType    string     `gorm:"column:content_type;size:64;comment:原始内容类型" json:"content_type"`
+	Size           int64      `gorm:"column:size;comment:原始大小(字节)" json:"size"`
+	CompressedSize int64      `gorm:"column:compressed_size;comment:压缩后大小(字节)" json:"compressed_size"`
+	FetchedAt      time.Time  `gorm:"column:fetched_at;not null;comment:请求时间" json:"fetched_at"`
+	CreatedAt      *time.Time `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+	UpdatedAt      *time.Time `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (PageArchive) TableName() string {
+	return "page_archives"
+}
+
 // Danmaku 弹幕模型
 // 存储视频播放时的弹幕信息
 type Danmaku struct {
@@ -134,14 +337,17 @@ func (UserFavorite) TableName() string {
 }
 
 // FilterInfo 筛选信息模型
-// 存储视频筛选条件信息
+// 每行是一个筛选项，由同步后的统计任务根据已发布视频生成：
+// Name 为显示名称，Tags/Country/Year 中只有一个有值（对应筛选维度），Count 为该筛选项下的视频数
 type FilterInfo struct {
-	ID      int64  `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name    string `gorm:"size:255" json:"name"`
-	Type    string `gorm:"size:255" json:"type"`
-	Country string `gorm:"size:255" json:"country"`
-	Year    string `gorm:"size:255" json:"year"`
-	Tags    string `gorm:"size:255" json:"tags"`
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string     `gorm:"size:255" json:"name"`
+	Type      string     `gorm:"size:255;index:idx_filter_info_type" json:"type"`
+	Country   string     `gorm:"size:255" json:"country"`
+	Year      string     `gorm:"size:255" json:"year"`
+	Tags      string     `gorm:"size:255" json:"tags"`
+	Count     int64      `gorm:"column:count;default:0;comment:该筛选项下的已发布视频数" json:"count"`
+	UpdatedAt *time.Time `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -169,3 +375,34 @@ type AppVersion struct {
 func (AppVersion) TableName() string {
 	return "app_versions"
 }
+
+// Webhook投递状态
+const (
+	WebhookStatusPending = "pending" // 待投递/重试中
+	WebhookStatusSuccess = "success" // 投递成功
+	WebhookStatusFailed  = "failed"  // 重试次数用尽，投递失败
+)
+
+// WebhookDelivery Webhook投递记录模型
+// 每个事件向每个订阅的端点投递一次，记录投递状态、重试次数和最后一次响应
+type WebhookDelivery struct {
+	ID           int64          `gorm:"primaryKey;autoIncrement;comment:投递记录ID" json:"id"`
+	DeliveryID   string         `gorm:"column:delivery_id;size:64;uniqueIndex;not null;comment:投递唯一标识(随请求头X-Webhook-Delivery发送)" json:"delivery_id"`
+	Event        string         `gorm:"size:64;index;not null;comment:事件类型" json:"event"`
+	Endpoint     string         `gorm:"size:100;index;comment:端点名称" json:"endpoint"`
+	URL          string         `gorm:"column:url;type:text;comment:投递地址" json:"url"`
+	Payload      datatypes.JSON `gorm:"column:payload;type:json;comment:投递内容(JSON格式)" json:"payload"`
+	Status       string         `gorm:"size:20;index;not null;comment:投递状态(pending/success/failed)" json:"status"`
+	Attempts     int            `gorm:"column:attempts;default:0;comment:已尝试次数" json:"attempts"`
+	ResponseCode int            `gorm:"column:response_code;comment:最后一次响应状态码" json:"response_code"`
+	LastError    string         `gorm:"column:last_error;type:text;comment:最后一次错误信息" json:"last_error"`
+	NextRetryAt  *time.Time     `gorm:"column:next_retry_at;comment:下次重试时间（投递中时为占用的截止时间）" json:"next_retry_at"`
+	DeliveredAt  *time.Time     `gorm:"column:delivered_at;comment:投递成功时间" json:"delivered_at"`
+	CreatedAt    *time.Time     `gorm:"autoCreateTime;comment:创建时间" json:"created_at"`
+	UpdatedAt    *time.Time     `gorm:"autoUpdateTime;comment:更新时间" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -14,9 +14,10 @@ import (
 var defaultKey = []byte("change-me-default")
 
 // Claims 定义JWT token的载荷结构
-// 包含用户名和JWT标准的RegisteredClaims
+// 包含用户名、角色和JWT标准的RegisteredClaims
 type Claims struct {
 	Username string `json:"username"`
+	Role     string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -32,6 +33,7 @@ func GetJWTKey() []byte {
 // GenerateToken 生成JWT token
 // 参数：
 //   - username: 用户名
+//   - role: 用户角色（管理接口只接受管理员角色的token）
 //   - expiration: token过期时间
 //
 // 返回：
@@ -41,10 +43,11 @@ func GetJWTKey() []byte {
 // 注意：为避免token重复，添加了以下唯一性保证：
 //   - ID (jti): 使用UUID确保每个token都有唯一标识
 //   - IssuedAt (iat): 记录签发时间戳（精确到秒）
-func GenerateToken(username string, expiration time.Time) (string, error) {
+func GenerateToken(username, role string, expiration time.Time) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.New().String(), // jti - JWT ID，确保token唯一性
 			ExpiresAt: jwt.NewNumericDate(expiration),
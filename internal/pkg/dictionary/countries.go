@@ -0,0 +1,59 @@
+package dictionary
+
+// countryEntries 国家/地区词条（代码为 ISO 3166-1 alpha-2，已不存在的国家使用 ISO 3166-3 代码）
+var countryEntries = []*Entry{
+	{Code: "CN", Name: "中国大陆", NameEn: "Mainland China", Aliases: []string{"中国", "大陆", "内地", "中国内地", "China"}},
+	{Code: "HK", Name: "中国香港", NameEn: "Hong Kong", Aliases: []string{"香港", "Hong Kong, China"}},
+	{Code: "TW", Name: "中国台湾", NameEn: "Taiwan", Aliases: []string{"台湾", "臺灣", "Taiwan, China"}},
+	{Code: "MO", Name: "中国澳门", NameEn: "Macau", Aliases: []string{"澳门", "Macao"}},
+	{Code: "US", Name: "美国", NameEn: "United States", Aliases: []string{"USA", "美國"}},
+	{Code: "GB", Name: "英国", NameEn: "United Kingdom", Aliases: []string{"UK", "英國"}},
+	{Code: "JP", Name: "日本", NameEn: "Japan"},
+	{Code: "KR", Name: "韩国", NameEn: "South Korea", Aliases: []string{"南韩", "韓國", "Korea"}},
+	{Code: "KP", Name: "朝鲜", NameEn: "North Korea", Aliases: []string{"北韩"}},
+	{Code: "FR", Name: "法国", NameEn: "France"},
+	{Code: "DE", Name: "德国", NameEn: "Germany", Aliases: []string{"西德", "德國"}},
+	{Code: "DDDE", Name: "东德", NameEn: "East Germany"},
+	{Code: "IT", Name: "意大利", NameEn: "Italy"},
+	{Code: "ES", Name: "西班牙", NameEn: "Spain"},
+	{Code: "PT", Name: "葡萄牙", NameEn: "Portugal"},
+	{Code: "CA", Name: "加拿大", NameEn: "Canada"},
+	{Code: "AU", Name: "澳大利亚", NameEn: "Australia", Aliases: []string{"澳洲"}},
+	{Code: "NZ", Name: "新西兰", NameEn: "New Zealand"},
+	{Code: "IE", Name: "爱尔兰", NameEn: "Ireland"},
+	{Code: "IN", Name: "印度", NameEn: "India"},
+	{Code: "TH", Name: "泰国", NameEn: "Thailand"},
+	{Code: "VN", Name: "越南", NameEn: "Vietnam"},
+	{Code: "SG", Name: "新加坡", NameEn: "Singapore"},
+	{Code: "MY", Name: "马来西亚", NameEn: "Malaysia"},
+	{Code: "ID", Name: "印度尼西亚", NameEn: "Indonesia", Aliases: []string{"印尼"}},
+	{Code: "PH", Name: "菲律宾", NameEn: "Philippines"},
+	{Code: "RU", Name: "俄罗斯", NameEn: "Russia"},
+	{Code: "SUHH", Name: "苏联", NameEn: "Soviet Union"},
+	{Code: "UA", Name: "乌克兰", NameEn: "Ukraine"},
+	{Code: "PL", Name: "波兰", NameEn: "Poland"},
+	{Code: "CZ", Name: "捷克", NameEn: "Czech Republic", Aliases: []string{"捷克斯洛伐克"}},
+	{Code: "HU", Name: "匈牙利", NameEn: "Hungary"},
+	{Code: "RO", Name: "罗马尼亚", NameEn: "Romania"},
+	{Code: "AT", Name: "奥地利", NameEn: "Austria"},
+	{Code: "CH", Name: "瑞士", NameEn: "Switzerland"},
+	{Code: "BE", Name: "比利时", NameEn: "Belgium"},
+	{Code: "NL", Name: "荷兰", NameEn: "Netherlands"},
+	{Code: "LU", Name: "卢森堡", NameEn: "Luxembourg"},
+	{Code: "SE", Name: "瑞典", NameEn: "Sweden"},
+	{Code: "NO", Name: "挪威", NameEn: "Norway"},
+	{Code: "DK", Name: "丹麦", NameEn: "Denmark"},
+	{Code: "FI", Name: "芬兰", NameEn: "Finland"},
+	{Code: "IS", Name: "冰岛", NameEn: "Iceland"},
+	{Code: "GR", Name: "希腊", NameEn: "Greece"},
+	{Code: "TR", Name: "土耳其", NameEn: "Turkey"},
+	{Code: "IL", Name: "以色列", NameEn: "Israel"},
+	{Code: "IR", Name: "伊朗", NameEn: "Iran"},
+	{Code: "EG", Name: "埃及", NameEn: "Egypt"},
+	{Code: "ZA", Name: "南非", NameEn: "South Africa"},
+	{Code: "MX", Name: "墨西哥", NameEn: "Mexico"},
+	{Code: "BR", Name: "巴西", NameEn: "Brazil"},
+	{Code: "AR", Name: "阿根廷", NameEn: "Argentina"},
+	{Code: "CL", Name: "智利", NameEn: "Chile"},
+	{Code: "CO", Name: "哥伦比亚", NameEn: "Colombia"},
+}
@@ -0,0 +1,111 @@
+// dictionary 包提供国家/地区和类型的规范化词典
+// 来源站点的写法不统一（如"中国香港"和"香港"），入库前统一转换为规范代码，
+// 国家/地区使用 ISO 3166-1 alpha-2 代码（如 HK），类型使用英文小写代码（如 drama）
+// 新增别名时只需修改 countries.go / genres.go 中的词条
+package dictionary
+
+import (
+	"strings"
+)
+
+// Entry 词条
+type Entry struct {
+	Code    string   `json:"code"`    // 规范代码（入库的值）
+	Name    string   `json:"name"`    // 中文名称
+	NameEn  string   `json:"name_en"` // 英文名称
+	Aliases []string `json:"-"`       // 别名（中文名称、英文名称和代码本身无需重复列出）
+}
+
+// Dictionary 词典：别名到规范代码的映射
+type Dictionary struct {
+	entries []*Entry
+	byCode  map[string]*Entry
+	byAlias map[string]*Entry
+}
+
+// newDictionary 根据词条构建词典，别名匹配忽略大小写和空白
+func newDictionary(entries []*Entry) *Dictionary {
+	d := &Dictionary{
+		entries: entries,
+		byCode:  make(map[string]*Entry, len(entries)),
+		byAlias: make(map[string]*Entry, len(entries)*4),
+	}
+	for _, entry := range entries {
+		d.byCode[entry.Code] = entry
+		for _, alias := range append([]string{entry.Code, entry.Name, entry.NameEn}, entry.Aliases...) {
+			d.byAlias[normalizeAlias(alias)] = entry
+		}
+	}
+	return d
+}
+
+// normalizeAlias 别名标准化：去掉空白并转为小写
+func normalizeAlias(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), ""))
+}
+
+// Lookup 根据任意写法（别名、名称或代码）查找词条
+func (d *Dictionary) Lookup(value string) (*Entry, bool) {
+	entry, ok := d.byAlias[normalizeAlias(value)]
+	return entry, ok
+}
+
+// ByCode 根据规范代码查找词条
+func (d *Dictionary) ByCode(code string) (*Entry, bool) {
+	entry, ok := d.byCode[code]
+	return entry, ok
+}
+
+// Entries 返回全部词条（按定义顺序）
+func (d *Dictionary) Entries() []*Entry {
+	return d.entries
+}
+
+// Canonicalize 将一组取值转换为规范代码并去重（保持原顺序）
+// 词典中没有的取值原样保留（去掉首尾空白），返回值 unknown 为这些取值，便于补充词典
+func (d *Dictionary) Canonicalize(values []string) (codes []string, unknown []string) {
+	seen := make(map[string]bool, len(values))
+	codes = make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		code := value
+		if entry, ok := d.Lookup(value); ok {
+			code = entry.Code
+		} else {
+			unknown = append(unknown, value)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, unknown
+}
+
+// DisplayName 返回代码对应的中文名称，词典中没有时返回代码本身
+func (d *Dictionary) DisplayName(code string) string {
+	if entry, ok := d.byCode[code]; ok {
+		return entry.Name
+	}
+	return code
+}
+
+// EnglishName 返回代码对应的英文名称，词典中没有时返回空字符串
+func (d *Dictionary) EnglishName(code string) string {
+	if entry, ok := d.byCode[code]; ok {
+		return entry.NameEn
+	}
+	return ""
+}
+
+var (
+	// Countries 国家/地区词典
+	Countries = newDictionary(countryEntries)
+
+	// Genres 类型词典
+	Genres = newDictionary(genreEntries)
+)
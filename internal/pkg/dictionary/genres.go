@@ -0,0 +1,40 @@
+package dictionary
+
+// genreEntries 类型词条（豆瓣的类型名称，以及英文页面中出现的写法）
+var genreEntries = []*Entry{
+	{Code: "drama", Name: "剧情", NameEn: "Drama"},
+	{Code: "comedy", Name: "喜剧", NameEn: "Comedy"},
+	{Code: "action", Name: "动作", NameEn: "Action"},
+	{Code: "romance", Name: "爱情", NameEn: "Romance"},
+	{Code: "sci-fi", Name: "科幻", NameEn: "Sci-Fi", Aliases: []string{"Science Fiction"}},
+	{Code: "animation", Name: "动画", NameEn: "Animation", Aliases: []string{"动漫"}},
+	{Code: "mystery", Name: "悬疑", NameEn: "Mystery"},
+	{Code: "thriller", Name: "惊悚", NameEn: "Thriller"},
+	{Code: "horror", Name: "恐怖", NameEn: "Horror"},
+	{Code: "crime", Name: "犯罪", NameEn: "Crime"},
+	{Code: "lgbtq", Name: "同性", NameEn: "LGBTQ"},
+	{Code: "music", Name: "音乐", NameEn: "Music"},
+	{Code: "musical", Name: "歌舞", NameEn: "Musical", Aliases: []string{"音乐剧"}},
+	{Code: "biography", Name: "传记", NameEn: "Biography"},
+	{Code: "history", Name: "历史", NameEn: "History"},
+	{Code: "war", Name: "战争", NameEn: "War"},
+	{Code: "western", Name: "西部", NameEn: "Western"},
+	{Code: "fantasy", Name: "奇幻", NameEn: "Fantasy", Aliases: []string{"魔幻"}},
+	{Code: "adventure", Name: "冒险", NameEn: "Adventure"},
+	{Code: "disaster", Name: "灾难", NameEn: "Disaster"},
+	{Code: "wuxia", Name: "武侠", NameEn: "Wuxia"},
+	{Code: "costume", Name: "古装", NameEn: "Costume"},
+	{Code: "family", Name: "家庭", NameEn: "Family"},
+	{Code: "kids", Name: "儿童", NameEn: "Kids", Aliases: []string{"少儿"}},
+	{Code: "sport", Name: "运动", NameEn: "Sport", Aliases: []string{"体育"}},
+	{Code: "documentary", Name: "纪录片", NameEn: "Documentary", Aliases: []string{"纪录"}},
+	{Code: "short", Name: "短片", NameEn: "Short"},
+	{Code: "film-noir", Name: "黑色电影", NameEn: "Film-Noir"},
+	{Code: "chinese-opera", Name: "戏曲", NameEn: "Chinese Opera"},
+	{Code: "supernatural", Name: "鬼怪", NameEn: "Supernatural"},
+	{Code: "erotic", Name: "情色", NameEn: "Erotic", Aliases: []string{"Adult"}},
+	{Code: "reality-tv", Name: "真人秀", NameEn: "Reality-TV"},
+	{Code: "talk-show", Name: "脱口秀", NameEn: "Talk-Show"},
+	{Code: "game-show", Name: "游戏", NameEn: "Game-Show"},
+	{Code: "news", Name: "新闻", NameEn: "News"},
+}
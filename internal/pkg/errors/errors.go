@@ -10,6 +10,7 @@ const (
 	CodeBadRequest   = 400 // 请求参数错误
 	CodeUnauthorized = 401 // 未授权（需要登录或token无效）
 	CodeForbidden    = 403 // 禁止访问
+	CodeNotFound     = 404 // 资源不存在
 	CodeConflict     = 409 // 资源冲突（如用户已存在）
 	CodeInternalErr  = 500 // 服务器内部错误
 )
@@ -34,9 +35,11 @@ const (
 	MsgUserCreateFailed      = "创建用户失败"
 	MsgUserInfoFormatError   = "用户信息格式错误"
 	MsgNotLoggedIn           = "未登录"
+	MsgAdminRequired         = "需要管理员权限"
 
 	// 密码相关错误信息
 	MsgPasswordEncryptFailed = "密码加密失败"
+	MsgPasswordLengthInvalid = "密码至少6个字符且不能超过72字节"
 
 	// Token相关错误信息
 	MsgTokenGenerateFailed   = "生成token失败"
@@ -48,6 +51,49 @@ const (
 	MsgTokenInvalidFormat    = "invalid authorization header format"
 	MsgTokenInvalid          = "invalid token"
 
+	// 同步相关错误信息
+	MsgDryRunNotFound = "预演任务不存在"
+
+	// Webhook相关错误信息
+	MsgWebhookDeliveryNotFound    = "Webhook投递记录不存在"
+	MsgWebhookDeliveryQueryFailed = "查询Webhook投递记录失败"
+	MsgWebhookRedeliverFailed     = "重新投递Webhook失败"
+	MsgWebhookDeliveryInProgress  = "投递记录仍在投递或等待重试，不能重新投递"
+
+	// 视频/系列相关错误信息
+	MsgVideoNotFound        = "视频不存在"
+	MsgVideoQueryFailed     = "查询视频失败"
+	MsgVideoSortInvalid     = "不支持的排序方式"
+	MsgVideoCursorInvalid   = "分页游标无效"
+	MsgSeriesNotFound       = "系列不存在"
+	MsgSeriesQueryFailed    = "查询系列失败"
+	MsgSeriesUpdateFailed   = "更新系列关联失败"
+	MsgSeriesTargetRequired = "series_id 和 series_title 必须指定其一"
+	MsgSeasonNumberInvalid  = "季数必须大于0"
+
+	// 人物相关错误信息
+	MsgPersonNotFound    = "人物不存在"
+	MsgPersonQueryFailed = "查询人物失败"
+
+	// 筛选项相关错误信息
+	MsgFilterTypeRequired = "type 不能为空"
+	MsgFilterQueryFailed  = "查询筛选项失败"
+
+	// 搜索相关错误信息
+	MsgSearchKeywordRequired = "搜索关键词不能为空"
+	MsgSearchFailed          = "搜索失败"
+	MsgSuggestDisabled       = "未配置Redis（redis.addr），搜索建议不可用"
+
+	// 目录变更记录相关错误信息
+	MsgCatalogChangeNotFound      = "变更记录不存在"
+	MsgCatalogChangeQueryFailed   = "查询变更记录失败"
+	MsgCatalogChangeNotRevertible = "该变更不支持回滚"
+	MsgCatalogChangeConflict      = "字段在该变更之后已被修改，不能回滚"
+	MsgCatalogChangeRevertFailed  = "回滚变更失败"
+
+	// 封面相关错误信息
+	MsgBlobStoreDisabled = "未配置对象存储（storage.driver）"
+
 	// 服务器错误信息
 	MsgServerPanic = "server panic"
 )
@@ -108,9 +154,11 @@ var (
 	ErrUserCreateFailed      = New(CodeInternalErr, MsgUserCreateFailed)
 	ErrUserInfoFormatError   = New(CodeInternalErr, MsgUserInfoFormatError)
 	ErrNotLoggedIn           = New(CodeUnauthorized, MsgNotLoggedIn)
+	ErrAdminRequired         = New(CodeForbidden, MsgAdminRequired)
 
 	// 密码相关错误
 	ErrPasswordEncryptFailed = New(CodeInternalErr, MsgPasswordEncryptFailed)
+	ErrPasswordLengthInvalid = New(CodeBadRequest, MsgPasswordLengthInvalid)
 
 	// Token相关错误
 	ErrTokenGenerateFailed   = New(CodeInternalErr, MsgTokenGenerateFailed)
@@ -120,6 +168,44 @@ var (
 	ErrTokenDuplicate        = New(CodeConflict, MsgTokenDuplicate)
 	ErrTokenMissing          = New(CodeUnauthorized, MsgTokenMissing)
 	ErrTokenInvalidFormat    = New(CodeUnauthorized, MsgTokenInvalidFormat)
+
+	// 同步相关错误
+	ErrDryRunNotFound = New(CodeNotFound, MsgDryRunNotFound)
+
+	// Webhook相关错误
+	ErrWebhookDeliveryNotFound    = New(CodeNotFound, MsgWebhookDeliveryNotFound)
+	ErrWebhookDeliveryQueryFailed = New(CodeInternalErr, MsgWebhookDeliveryQueryFailed)
+	ErrWebhookDeliveryInProgress  = New(CodeConflict, MsgWebhookDeliveryInProgress)
+
+	// 视频/系列相关错误
+	ErrVideoNotFound        = New(CodeNotFound, MsgVideoNotFound)
+	ErrVideoQueryFailed     = New(CodeInternalErr, MsgVideoQueryFailed)
+	ErrVideoSortInvalid     = New(CodeBadRequest, MsgVideoSortInvalid)
+	ErrVideoCursorInvalid   = New(CodeBadRequest, MsgVideoCursorInvalid)
+	ErrSeriesNotFound       = New(CodeNotFound, MsgSeriesNotFound)
+	ErrSeriesQueryFailed    = New(CodeInternalErr, MsgSeriesQueryFailed)
+	ErrSeriesUpdateFailed   = New(CodeInternalErr, MsgSeriesUpdateFailed)
+	ErrSeriesTargetRequired = New(CodeBadRequest, MsgSeriesTargetRequired)
+	ErrSeasonNumberInvalid  = New(CodeBadRequest, MsgSeasonNumberInvalid)
+
+	// 人物相关错误
+	ErrPersonNotFound    = New(CodeNotFound, MsgPersonNotFound)
+	ErrPersonQueryFailed = New(CodeInternalErr, MsgPersonQueryFailed)
+
+	// 筛选项相关错误
+	ErrFilterTypeRequired = New(CodeBadRequest, MsgFilterTypeRequired)
+	ErrFilterQueryFailed  = New(CodeInternalErr, MsgFilterQueryFailed)
+
+	// 搜索相关错误
+	ErrSearchKeywordRequired = New(CodeBadRequest, MsgSearchKeywordRequired)
+	ErrSearchFailed          = New(CodeInternalErr, MsgSearchFailed)
+
+	// 目录变更记录相关错误
+	ErrCatalogChangeNotFound      = New(CodeNotFound, MsgCatalogChangeNotFound)
+	ErrCatalogChangeQueryFailed   = New(CodeInternalErr, MsgCatalogChangeQueryFailed)
+	ErrCatalogChangeNotRevertible = New(CodeBadRequest, MsgCatalogChangeNotRevertible)
+	ErrCatalogChangeConflict      = New(CodeConflict, MsgCatalogChangeConflict)
+	ErrCatalogChangeRevertFailed  = New(CodeInternalErr, MsgCatalogChangeRevertFailed)
 )
 
 // NewTokenInvalid 创建token无效错误（需要传入具体错误信息）
@@ -0,0 +1,75 @@
+// response 包提供统一的HTTP响应格式封装
+package response
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag 由各部分的取值生成弱ETag（W/"..."），任一部分变化时ETag随之变化
+// 弱ETag表示语义相同，JSON字段顺序等字节级差异不影响缓存
+func ETag(parts ...interface{}) string {
+	h := sha1.New()
+	for _, part := range parts {
+		if t, ok := part.(time.Time); ok {
+			part = t.UnixNano()
+		}
+		fmt.Fprintf(h, "%v\x00", part)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:20] + `"`
+}
+
+// NotModified 处理条件请求：设置 ETag 和 Last-Modified 响应头，客户端缓存仍然有效时返回304并返回true
+// 参数：
+//
+//	c: Gin上下文
+//	etag: 当前数据的ETag（为空时不设置、不比较）
+//	lastModified: 当前数据的最后修改时间（为零值时不设置、不比较）
+//
+// 按 RFC 7232：请求带 If-None-Match 时只比较ETag（弱比较），否则比较 If-Modified-Since（精确到秒）
+// 只处理GET和HEAD请求，返回true时调用方不应再写响应体
+func NotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	// 要求客户端每次使用缓存前都重新验证
+	c.Header("Cache-Control", "no-cache")
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	notModified := false
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		notModified = etag != "" && etagMatch(inm, etag)
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil {
+			notModified = !lastModified.Truncate(time.Second).After(t)
+		}
+	}
+	if notModified {
+		c.AbortWithStatus(http.StatusNotModified)
+	}
+	return notModified
+}
+
+// etagMatch 判断 If-None-Match 请求头是否与ETag匹配（弱比较：忽略 W/ 前缀），支持逗号分隔的多个ETag和 *
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
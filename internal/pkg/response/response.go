@@ -21,11 +21,27 @@ type Response struct {
 	TraceID string      `json:"trace_id,omitempty"` // 请求追踪ID（可选，用于分布式追踪）
 }
 
+// PageData 定义分页列表的响应数据结构
+type PageData struct {
+	List     interface{} `json:"list"`      // 当前页数据
+	Total    int64       `json:"total"`     // 总记录数
+	Page     int         `json:"page"`      // 当前页码（从1开始）
+	PageSize int         `json:"page_size"` // 每页条数
+}
+
+// CursorData 定义游标分页列表的响应数据结构
+type CursorData struct {
+	List       interface{} `json:"list"`        // 当前页数据
+	NextCursor string      `json:"next_cursor"` // 下一页游标，没有更多数据时为空
+	HasMore    bool        `json:"has_more"`    // 是否还有更多数据
+}
+
 // 错误码常量从 errors 包导入
 const (
 	CodeSuccess      = errors.CodeSuccess      // 成功
 	CodeBadRequest   = errors.CodeBadRequest   // 请求参数错误
 	CodeUnauthorized = errors.CodeUnauthorized // 未授权（需要登录或token无效）
+	CodeNotFound     = errors.CodeNotFound     // 资源不存在
 	CodeConflict     = errors.CodeConflict     // 资源冲突（如用户已存在）
 	CodeInternalErr  = errors.CodeInternalErr  // 服务器内部错误
 )
@@ -0,0 +1,88 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// CatalogChangeRepository 目录变更记录仓库接口
+type CatalogChangeRepository interface {
+	// Create 批量创建变更记录
+	Create(changes []*model.CatalogChange) error
+
+	// FindByID 根据ID查找变更记录
+	FindByID(id int64) (*model.CatalogChange, error)
+
+	// ListByVideoID 分页查询视频（包括其剧集）的变更记录（按ID降序），返回记录列表和总数
+	ListByVideoID(videoID int64, page, pageSize int) ([]*model.CatalogChange, int64, error)
+
+	// ApplyVideoRevert 回滚视频字段：在同一事务中将字段更新为value并写入回滚产生的变更记录
+	ApplyVideoRevert(revert *model.CatalogChange, value interface{}) error
+}
+
+// catalogChangeRepository 目录变更记录仓库实现
+type catalogChangeRepository struct{}
+
+// NewCatalogChangeRepository 创建目录变更记录仓库实例
+func NewCatalogChangeRepository() CatalogChangeRepository {
+	return &catalogChangeRepository{}
+}
+
+// Create 批量创建变更记录
+func (r *catalogChangeRepository) Create(changes []*model.CatalogChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return database.DB.Create(&changes).Error
+}
+
+// FindByID 根据ID查找变更记录
+func (r *catalogChangeRepository) FindByID(id int64) (*model.CatalogChange, error) {
+	var change model.CatalogChange
+	err := database.DB.Where("id = ?", id).First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ListByVideoID 分页查询视频（包括其剧集）的变更记录（按ID降序），返回记录列表和总数
+func (r *catalogChangeRepository) ListByVideoID(videoID int64, page, pageSize int) ([]*model.CatalogChange, int64, error) {
+	query := database.DB.Model(&model.CatalogChange{}).Where("video_id = ?", videoID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var changes []*model.CatalogChange
+	err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&changes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return changes, total, nil
+}
+
+// ApplyVideoRevert 回滚视频字段：在同一事务中将字段更新为value并写入回滚产生的变更记录
+// 字段名取自 revert.Field，调用方需保证其为可回滚的 videos 列
+func (r *catalogChangeRepository) ApplyVideoRevert(revert *model.CatalogChange, value interface{}) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Video{}).
+			Where("id = ?", revert.EntityID).
+			Update(revert.Field, value).Error; err != nil {
+			return err
+		}
+		return tx.Create(revert).Error
+	})
+	if err != nil {
+		return err
+	}
+	InvalidateVideoCache(revert.EntityID)
+	return nil
+}
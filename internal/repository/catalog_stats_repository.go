@@ -0,0 +1,120 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+)
+
+// VideoStateCount 某一类型、状态和完结状态的视频数
+type VideoStateCount struct {
+	Type        string
+	Status      string
+	IsCompleted bool
+	Count       int64
+}
+
+// TypeCount 某一类型的视频数
+type TypeCount struct {
+	Type  string
+	Count int64
+}
+
+// ChannelCount 某一频道（播放源）的剧集数
+type ChannelCount struct {
+	Channel string
+	Count   int64
+}
+
+// CatalogStatsRepository 目录统计仓库接口（只做分组计数，用于监控指标）
+type CatalogStatsRepository interface {
+	// CountVideosByState 按类型、状态和是否完结统计视频数
+	CountVideosByState() ([]*VideoStateCount, error)
+
+	// CountVideosMissingDetails 按类型统计缺少详情的视频数（条件与同步补充详情的查询一致）
+	CountVideosMissingDetails() ([]*TypeCount, error)
+
+	// CountVideosWithoutEpisodes 按类型统计没有任何剧集的视频数
+	CountVideosWithoutEpisodes() ([]*TypeCount, error)
+
+	// CountEpisodesByChannel 按频道统计剧集数
+	CountEpisodesByChannel() ([]*ChannelCount, error)
+
+	// CountUnhealthyEpisodesByChannel 按频道统计播放地址不可用（为空或不是http(s)地址）的剧集数
+	CountUnhealthyEpisodesByChannel() ([]*ChannelCount, error)
+}
+
+// catalogStatsRepository 目录统计仓库实现
+type catalogStatsRepository struct{}
+
+// NewCatalogStatsRepository 创建目录统计仓库实例
+func NewCatalogStatsRepository() CatalogStatsRepository {
+	return &catalogStatsRepository{}
+}
+
+// CountVideosByState 按类型、状态和是否完结统计视频数
+func (r *catalogStatsRepository) CountVideosByState() ([]*VideoStateCount, error) {
+	var counts []*VideoStateCount
+	err := database.DB.Model(&model.Video{}).
+		Select("COALESCE(type, '') AS type, COALESCE(status, '') AS status, COALESCE(is_completed, 0) AS is_completed, COUNT(*) AS count").
+		Group("1, 2, 3").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountVideosMissingDetails 按类型统计缺少详情的视频数（条件与同步补充详情的查询一致）
+func (r *catalogStatsRepository) CountVideosMissingDetails() ([]*TypeCount, error) {
+	var counts []*TypeCount
+	err := database.DB.Model(&model.Video{}).
+		Select("COALESCE(type, '') AS type, COUNT(*) AS count").
+		Where("source_id IS NOT NULL AND source_id != 0 AND (release_date IS NULL) AND (country_json IS NULL OR country_json = '[]')").
+		Group("1").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountVideosWithoutEpisodes 按类型统计没有任何剧集的视频数
+func (r *catalogStatsRepository) CountVideosWithoutEpisodes() ([]*TypeCount, error) {
+	var counts []*TypeCount
+	err := database.DB.Raw(`
+		SELECT COALESCE(v.type, '') AS type, COUNT(*) AS count
+		FROM videos v
+		WHERE NOT EXISTS (SELECT 1 FROM episodes e WHERE e.video_id = v.id)
+		GROUP BY 1`).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountEpisodesByChannel 按频道统计剧集数
+func (r *catalogStatsRepository) CountEpisodesByChannel() ([]*ChannelCount, error) {
+	return r.countEpisodesByChannel("")
+}
+
+// CountUnhealthyEpisodesByChannel 按频道统计播放地址不可用（为空或不是http(s)地址）的剧集数
+func (r *catalogStatsRepository) CountUnhealthyEpisodesByChannel() ([]*ChannelCount, error) {
+	return r.countEpisodesByChannel("play_urls = '' OR (play_urls NOT LIKE 'http://%' AND play_urls NOT LIKE 'https://%')")
+}
+
+// countEpisodesByChannel 按频道统计满足条件的剧集数，where为空时统计全部剧集
+func (r *catalogStatsRepository) countEpisodesByChannel(where string) ([]*ChannelCount, error) {
+	var counts []*ChannelCount
+	query := database.DB.Model(&model.Episode{}).
+		Select("COALESCE(channel, '') AS channel, COUNT(*) AS count")
+	if where != "" {
+		query = query.Where(where)
+	}
+	err := query.Group("1").Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
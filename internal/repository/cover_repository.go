@@ -0,0 +1,80 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// CoverRepository 封面镜像仓库接口
+type CoverRepository interface {
+	// FindVideosNeedMirror 查找封面尚未镜像且失败次数小于maxFailures的视频（返回 id、title 和封面相关的列）
+	FindVideosNeedMirror(maxFailures, limit int) ([]*model.Video, error)
+
+	// SaveMirroredCover 保存镜像结果：cover_url 替换为自有存储地址，并同步更新使用相同原始封面的系列
+	SaveMirroredCover(videoID int64, sourceURL, coverURL string, thumbs datatypes.JSON) error
+
+	// IncrMirrorFailures 镜像失败次数加1
+	IncrMirrorFailures(videoID int64) error
+}
+
+// coverRepository 封面镜像仓库实现
+type coverRepository struct{}
+
+// NewCoverRepository 创建封面镜像仓库实例
+func NewCoverRepository() CoverRepository {
+	return &coverRepository{}
+}
+
+// FindVideosNeedMirror 查找封面尚未镜像且失败次数小于maxFailures的视频（新视频优先）
+func (r *coverRepository) FindVideosNeedMirror(maxFailures, limit int) ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Select("id", "title", "cover_url", "cover_source_url", "cover_thumbs_json").
+		Where("cover_mirrored_at IS NULL AND cover_url IS NOT NULL AND cover_url != '' AND cover_mirror_failures < ?", maxFailures).
+		Order("id DESC").
+		Limit(limit).
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// SaveMirroredCover 保存镜像结果：cover_url 替换为自有存储地址，并同步更新使用相同原始封面的系列
+func (r *coverRepository) SaveMirroredCover(videoID int64, sourceURL, coverURL string, thumbs datatypes.JSON) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Video{}).
+			Where("id = ?", videoID).
+			Updates(map[string]interface{}{
+				"cover_source_url":      sourceURL,
+				"cover_url":             coverURL,
+				"cover_thumbs_json":     thumbs,
+				"cover_mirrored_at":     time.Now(),
+				"cover_mirror_failures": 0,
+			}).Error; err != nil {
+			return err
+		}
+
+		// 系列创建时复制了视频的原始封面
+		return tx.Model(&model.Series{}).
+			Where("cover_url = ?", sourceURL).
+			Update("cover_url", coverURL).Error
+	})
+	if err != nil {
+		return err
+	}
+	InvalidateVideoCache(videoID)
+	return nil
+}
+
+// IncrMirrorFailures 镜像失败次数加1
+func (r *coverRepository) IncrMirrorFailures(videoID int64) error {
+	return database.DB.Model(&model.Video{}).
+		Where("id = ?", videoID).
+		UpdateColumn("cover_mirror_failures", gorm.Expr("cover_mirror_failures + 1")).Error
+}
@@ -14,7 +14,7 @@ type EpisodeRepository interface {
 	// Create 创建剧集记录
 	Create(episode *model.Episode) error
 
-	// FindByVideoID 根据视频ID查找所有剧集
+	// FindByVideoID 根据视频ID查找所有剧集（按集数升序）
 	FindByVideoID(videoID int64) ([]*model.Episode, error)
 
 	// CountByVideoID 根据视频ID统计episode数量
@@ -55,10 +55,13 @@ func (r *episodeRepository) Create(episode *model.Episode) error {
 	})
 }
 
-// FindByVideoID 根据视频ID查找所有剧集
+// FindByVideoID 根据视频ID查找所有剧集（按集数升序，集数相同时按ID升序）
 func (r *episodeRepository) FindByVideoID(videoID int64) ([]*model.Episode, error) {
 	var episodes []*model.Episode
-	err := database.DB.Where("video_id = ?", videoID).Find(&episodes).Error
+	err := database.DB.Where("video_id = ?", videoID).
+		Order("episode_number ASC").
+		Order("id ASC").
+		Find(&episodes).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,141 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// FacetCount 筛选维度上的一个取值及其视频数
+type FacetCount struct {
+	Value string
+	Count int64
+}
+
+// YearCount 某一年的视频数
+type YearCount struct {
+	Year  int
+	Count int64
+}
+
+// FilterRepository 筛选信息仓库接口
+type FilterRepository interface {
+	// FindPublishedTypes 查询已发布视频的所有类型
+	FindPublishedTypes() ([]string, error)
+
+	// CountTags 统计指定类型的已发布视频中各标签的视频数
+	CountTags(videoType string) ([]*FacetCount, error)
+
+	// CountCountries 统计指定类型的已发布视频中各国家/地区的视频数
+	CountCountries(videoType string) ([]*FacetCount, error)
+
+	// CountYears 统计指定类型的已发布视频中各上映年份的视频数
+	CountYears(videoType string) ([]*YearCount, error)
+
+	// ReplaceByType 替换指定类型的全部筛选项（事务内先删除后插入）
+	ReplaceByType(videoType string, filters []*model.FilterInfo) error
+
+	// DeleteExceptTypes 删除不在给定类型列表中的筛选项（该类型已没有已发布视频）
+	DeleteExceptTypes(videoTypes []string) error
+
+	// FindByType 查询指定类型的全部筛选项
+	FindByType(videoType string) ([]*model.FilterInfo, error)
+}
+
+// filterRepository 筛选信息仓库实现
+type filterRepository struct{}
+
+// NewFilterRepository 创建筛选信息仓库实例
+func NewFilterRepository() FilterRepository {
+	return &filterRepository{}
+}
+
+// FindPublishedTypes 查询已发布视频的所有类型
+func (r *filterRepository) FindPublishedTypes() ([]string, error) {
+	var types []string
+	err := database.DB.Model(&model.Video{}).
+		Where("status = ? AND type IS NOT NULL AND type != ''", "1").
+		Distinct().
+		Pluck("type", &types).Error
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// CountTags 统计指定类型的已发布视频中各标签的视频数
+func (r *filterRepository) CountTags(videoType string) ([]*FacetCount, error) {
+	return r.countJSONArray("tags_json", videoType)
+}
+
+// CountCountries 统计指定类型的已发布视频中各国家/地区的视频数
+func (r *filterRepository) CountCountries(videoType string) ([]*FacetCount, error) {
+	return r.countJSONArray("country_json", videoType)
+}
+
+// countJSONArray 使用 JSON_TABLE 展开JSON数组列并按取值统计视频数（同一视频重复的取值只计一次）
+func (r *filterRepository) countJSONArray(column, videoType string) ([]*FacetCount, error) {
+	var counts []*FacetCount
+	err := database.DB.Raw(`
+		SELECT jt.value AS value, COUNT(DISTINCT v.id) AS count
+		FROM videos v,
+			JSON_TABLE(v.`+column+`, '$[*]' COLUMNS (value VARCHAR(255) PATH '$')) AS jt
+		WHERE v.type = ? AND v.status = ? AND jt.value IS NOT NULL AND jt.value != ''
+		GROUP BY jt.value
+		ORDER BY count DESC, value ASC`, videoType, "1").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountYears 统计指定类型的已发布视频中各上映年份的视频数
+func (r *filterRepository) CountYears(videoType string) ([]*YearCount, error) {
+	var counts []*YearCount
+	err := database.DB.Model(&model.Video{}).
+		Select("YEAR(release_date) AS year, COUNT(*) AS count").
+		Where("type = ? AND status = ? AND release_date IS NOT NULL", videoType, "1").
+		Group("YEAR(release_date)").
+		Order("year DESC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ReplaceByType 替换指定类型的全部筛选项（事务内先删除后插入）
+func (r *filterRepository) ReplaceByType(videoType string, filters []*model.FilterInfo) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("type = ?", videoType).Delete(&model.FilterInfo{}).Error; err != nil {
+			return err
+		}
+		if len(filters) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(filters, 200).Error
+	})
+}
+
+// DeleteExceptTypes 删除不在给定类型列表中的筛选项
+func (r *filterRepository) DeleteExceptTypes(videoTypes []string) error {
+	query := database.DB
+	if len(videoTypes) > 0 {
+		query = query.Where("type NOT IN ?", videoTypes)
+	} else {
+		query = query.Where("1 = 1")
+	}
+	return query.Delete(&model.FilterInfo{}).Error
+}
+
+// FindByType 查询指定类型的全部筛选项
+func (r *filterRepository) FindByType(videoType string) ([]*model.FilterInfo, error) {
+	var filters []*model.FilterInfo
+	if err := database.DB.Where("type = ?", videoType).Order("id ASC").Find(&filters).Error; err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
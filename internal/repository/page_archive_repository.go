@@ -0,0 +1,46 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm/clause"
+)
+
+// PageArchiveRepository 页面归档索引仓库接口
+type PageArchiveRepository interface {
+	// Save 保存归档记录（同一来源、来源ID和页面类型已存在时更新存储位置和请求时间）
+	Save(archive *model.PageArchive) error
+
+	// FindBatch 按ID升序分批查询指定来源和页面类型的归档记录（ID大于afterID）
+	FindBatch(source, kind string, afterID int64, limit int) ([]*model.PageArchive, error)
+}
+
+// pageArchiveRepository 页面归档索引仓库实现
+type pageArchiveRepository struct{}
+
+// NewPageArchiveRepository 创建页面归档索引仓库实例
+func NewPageArchiveRepository() PageArchiveRepository {
+	return &pageArchiveRepository{}
+}
+
+// Save 保存归档记录（同一来源、来源ID和页面类型已存在时更新存储位置和请求时间）
+func (r *pageArchiveRepository) Save(archive *model.PageArchive) error {
+	return database.DB.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"storage_key", "content_type", "size", "compressed_size", "fetched_at", "updated_at"}),
+	}).Create(archive).Error
+}
+
+// FindBatch 按ID升序分批查询指定来源和页面类型的归档记录（ID大于afterID）
+func (r *pageArchiveRepository) FindBatch(source, kind string, afterID int64, limit int) ([]*model.PageArchive, error) {
+	var archives []*model.PageArchive
+	err := database.DB.Where("source = ? AND kind = ? AND id > ?", source, kind, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&archives).Error
+	if err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
@@ -0,0 +1,169 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// PersonCredit 人物作品（作品视频信息 + 担任的角色）
+type PersonCredit struct {
+	VideoID      int64      `json:"video_id"`
+	Title        string     `json:"title"`
+	Type         string     `json:"type"`
+	CoverURL     string     `json:"cover_url"`
+	ReleaseDate  *time.Time `json:"release_date"`
+	Score        *float64   `json:"score"`
+	Role         string     `json:"role"`
+	BillingOrder int        `json:"billing_order"`
+}
+
+// PersonRepository 人物仓库接口
+type PersonRepository interface {
+	// FindByID 根据ID查找人物
+	FindByID(personID int64) (*model.Person, error)
+
+	// FindByDoubanID 根据豆瓣影人ID查找人物
+	FindByDoubanID(doubanID int64) (*model.Person, error)
+
+	// FindByDoubanPersonageID 根据豆瓣人物ID查找人物
+	FindByDoubanPersonageID(personageID int64) (*model.Person, error)
+
+	// FindByNameWithoutSourceID 根据姓名查找没有豆瓣ID的人物
+	FindByNameWithoutSourceID(name string) (*model.Person, error)
+
+	// Create 创建人物记录
+	Create(person *model.Person) error
+
+	// UpdateName 更新人物姓名
+	UpdateName(personID int64, name string) error
+
+	// ReplaceCredits 替换视频的全部演职员记录
+	ReplaceCredits(videoID int64, credits []*model.VideoCredit) error
+
+	// FindFilmography 查询人物参与的已发布视频（按上映日期降序）
+	FindFilmography(personID int64) ([]*PersonCredit, error)
+
+	// FindVideosWithoutCredits 查找已补充详情但还没有演职员记录的豆瓣视频（返回 id、source_id、type、title）
+	// 在 checkedBefore 之后已请求过详情页的视频跳过（详情页没有演职员时不会每次同步都重复请求）
+	FindVideosWithoutCredits(limit int, checkedBefore time.Time) ([]*model.Video, error)
+
+	// MarkCreditsChecked 记录视频为补充演职员请求详情页的时间（不修改 updated_at）
+	MarkCreditsChecked(videoID int64) error
+}
+
+// personRepository 人物仓库实现
+type personRepository struct{}
+
+// NewPersonRepository 创建人物仓库实例
+func NewPersonRepository() PersonRepository {
+	return &personRepository{}
+}
+
+// FindByID 根据ID查找人物
+func (r *personRepository) FindByID(personID int64) (*model.Person, error) {
+	var person model.Person
+	err := database.DB.Where("id = ?", personID).First(&person).Error
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// FindByDoubanID 根据豆瓣影人ID查找人物
+func (r *personRepository) FindByDoubanID(doubanID int64) (*model.Person, error) {
+	var person model.Person
+	err := database.DB.Where("douban_id = ?", doubanID).First(&person).Error
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// FindByDoubanPersonageID 根据豆瓣人物ID查找人物
+func (r *personRepository) FindByDoubanPersonageID(personageID int64) (*model.Person, error) {
+	var person model.Person
+	err := database.DB.Where("douban_personage_id = ?", personageID).First(&person).Error
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// FindByNameWithoutSourceID 根据姓名查找没有豆瓣ID的人物
+func (r *personRepository) FindByNameWithoutSourceID(name string) (*model.Person, error) {
+	var person model.Person
+	err := database.DB.Where("name = ? AND douban_id IS NULL AND douban_personage_id IS NULL", name).
+		Order("id ASC").
+		First(&person).Error
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// Create 创建人物记录
+func (r *personRepository) Create(person *model.Person) error {
+	return database.DB.Create(person).Error
+}
+
+// UpdateName 更新人物姓名
+func (r *personRepository) UpdateName(personID int64, name string) error {
+	return database.DB.Model(&model.Person{}).Where("id = ?", personID).Update("name", name).Error
+}
+
+// ReplaceCredits 替换视频的全部演职员记录（事务内先删除再插入）
+func (r *personRepository) ReplaceCredits(videoID int64, credits []*model.VideoCredit) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("video_id = ?", videoID).Delete(&model.VideoCredit{}).Error; err != nil {
+			return err
+		}
+		if len(credits) == 0 {
+			return nil
+		}
+		return tx.Create(&credits).Error
+	})
+}
+
+// FindFilmography 查询人物参与的已发布视频（按上映日期降序）
+func (r *personRepository) FindFilmography(personID int64) ([]*PersonCredit, error) {
+	var credits []*PersonCredit
+	err := database.DB.Table("video_credits AS c").
+		Select("v.id AS video_id, v.title, v.type, v.cover_url, v.release_date, v.score, c.role, c.billing_order").
+		Joins("JOIN videos AS v ON v.id = c.video_id").
+		Where("c.person_id = ? AND v.status = ?", personID, "1").
+		Order("v.release_date IS NULL, v.release_date DESC, v.id DESC, c.role ASC").
+		Scan(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// FindVideosWithoutCredits 查找已补充详情但还没有演职员记录的豆瓣视频（从未请求过的优先，其次新视频优先）
+// 纪录片详情页通常没有导演和主演，不在此列；checkedBefore 之后请求过详情页的视频跳过
+func (r *personRepository) FindVideosWithoutCredits(limit int, checkedBefore time.Time) ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Select("id", "source_id", "type", "title").
+		Where("source = ? AND source_id IS NOT NULL AND source_id != 0", "douban").
+		Where("((director_json IS NOT NULL AND director_json != JSON_ARRAY()) OR (actors_json IS NOT NULL AND actors_json != JSON_ARRAY()))").
+		Where("NOT EXISTS (SELECT 1 FROM video_credits c WHERE c.video_id = videos.id)").
+		Where("credits_checked_at IS NULL OR credits_checked_at < ?", checkedBefore).
+		Order("credits_checked_at IS NOT NULL, id DESC").
+		Limit(limit).
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// MarkCreditsChecked 记录视频为补充演职员请求详情页的时间（不修改 updated_at）
+func (r *personRepository) MarkCreditsChecked(videoID int64) error {
+	return database.DB.Model(&model.Video{}).Where("id = ?", videoID).
+		UpdateColumn("credits_checked_at", time.Now()).Error
+}
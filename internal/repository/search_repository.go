@@ -0,0 +1,118 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"strings"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchHit 搜索命中的视频及相关度
+type SearchHit struct {
+	model.Video
+	Relevance float64 `gorm:"column:relevance"`
+}
+
+// SearchRepository 站内搜索仓库接口
+type SearchRepository interface {
+	// SearchPublished 使用全文索引搜索已发布的视频（标题、别名、简介、导演、演员），返回当前页数据和总数
+	SearchPublished(keyword, videoType string, page, pageSize int) ([]*SearchHit, int64, error)
+
+	// SearchPublishedByLike 按标题和别名模糊搜索已发布的视频，用于短于ngram分词长度、全文索引无法匹配的关键词
+	SearchPublishedByLike(keyword, videoType string, page, pageSize int) ([]*SearchHit, int64, error)
+}
+
+// searchRepository 站内搜索仓库实现
+type searchRepository struct{}
+
+// NewSearchRepository 创建站内搜索仓库实例
+func NewSearchRepository() SearchRepository {
+	return &searchRepository{}
+}
+
+// 各字段的全文匹配条件（BOOLEAN MODE短语匹配，ngram分词后要求所有词元按顺序出现，相当于子串匹配）
+// 索引见 migrations/init.sql（ft_videos_title、ft_videos_description、ft_videos_credits、ft_video_titles_title）
+const (
+	matchTitle       = "MATCH(videos.title) AGAINST(? IN BOOLEAN MODE)"
+	matchCredits     = "MATCH(videos.credits_text) AGAINST(? IN BOOLEAN MODE)"
+	matchDescription = "MATCH(videos.description) AGAINST(? IN BOOLEAN MODE)"
+)
+
+// searchRelevance 相关度：标题 > 别名 > 导演/演员 > 简介
+const searchRelevance = matchTitle + " * 4 + IFNULL(alt.relevance, 0) * 3 + " + matchCredits + " * 2 + " + matchDescription
+
+// SearchPublished 使用ngram全文索引搜索已发布的视频
+// 排序：标题完全相同的在前，其次按相关度、评分、上映日期降序
+func (r *searchRepository) SearchPublished(keyword, videoType string, page, pageSize int) ([]*SearchHit, int64, error) {
+	phrase := booleanPhrase(keyword)
+	query := database.DB.Model(&model.Video{}).
+		Joins(`LEFT JOIN (
+			SELECT video_id, MAX(MATCH(title) AGAINST(? IN BOOLEAN MODE)) AS relevance
+			FROM video_titles
+			WHERE MATCH(title) AGAINST(? IN BOOLEAN MODE)
+			GROUP BY video_id
+		) AS alt ON alt.video_id = videos.id`, phrase, phrase).
+		Where("videos.status = ?", "1").
+		Where("("+matchTitle+" OR "+matchCredits+" OR "+matchDescription+" OR alt.video_id IS NOT NULL)", phrase, phrase, phrase)
+	if videoType != "" {
+		query = query.Where("videos.type = ?", videoType)
+	}
+
+	return r.findPage(query,
+		"videos.*, ("+searchRelevance+") AS relevance", []interface{}{phrase, phrase, phrase},
+		keyword, page, pageSize)
+}
+
+// SearchPublishedByLike 按标题和别名（video_titles）模糊搜索已发布的视频，相关度为0
+// 排序：标题完全相同的在前，其次按评分、上映日期降序
+func (r *searchRepository) SearchPublishedByLike(keyword, videoType string, page, pageSize int) ([]*SearchHit, int64, error) {
+	like := "%" + escapeLike(keyword) + "%"
+	query := database.DB.Model(&model.Video{}).
+		Where("videos.status = ?", "1").
+		Where("(videos.title LIKE ? OR videos.id IN (SELECT video_id FROM video_titles WHERE title LIKE ?))", like, like)
+	if videoType != "" {
+		query = query.Where("videos.type = ?", videoType)
+	}
+
+	return r.findPage(query, "videos.*, 0 AS relevance", nil, keyword, page, pageSize)
+}
+
+// findPage 统计总数并查询当前页（两次查询使用各自的会话，互不影响）
+func (r *searchRepository) findPage(query *gorm.DB, selectSQL string, selectArgs []interface{}, keyword string, page, pageSize int) ([]*SearchHit, int64, error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []*SearchHit
+	err := query.
+		Select(selectSQL, selectArgs...).
+		Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "videos.title = ? DESC, relevance DESC, videos.score IS NULL, videos.score DESC, videos.release_date DESC, videos.id DESC",
+			Vars:               []interface{}{keyword},
+			WithoutParentheses: true,
+		}}).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+// booleanPhrase 将关键词转换为BOOLEAN MODE的短语（去掉双引号，避免破坏短语语法）
+func booleanPhrase(keyword string) string {
+	return `"` + strings.ReplaceAll(keyword, `"`, " ") + `"`
+}
+
+// escapeLike 转义LIKE模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,132 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+)
+
+// SeriesRepository 系列仓库接口
+type SeriesRepository interface {
+	// Create 创建系列记录
+	Create(series *model.Series) error
+
+	// FindByID 根据ID查找系列
+	FindByID(seriesID int64) (*model.Series, error)
+
+	// FindByTitleAndType 根据系列标题和类型查找系列
+	FindByTitleAndType(title, videoType string) (*model.Series, error)
+
+	// FindSeasons 查找系列下的所有视频（按季数升序）
+	FindSeasons(seriesID int64) ([]*model.Video, error)
+
+	// FindUnlinkedVideoByTitle 根据标题和类型查找未关联系列且未锁定的视频
+	FindUnlinkedVideoByTitle(title, videoType string) (*model.Video, error)
+
+	// FindUnlinkedVideos 查找所有未关联系列且未锁定的视频（返回 id、title、type、cover_url、description）
+	FindUnlinkedVideos() ([]*model.Video, error)
+
+	// ExistsSeason 判断系列中是否已存在指定季数的视频
+	ExistsSeason(seriesID, seasonNumber int64) (bool, error)
+
+	// LinkVideo 设置视频的系列关联（seriesID和seasonNumber为nil时表示取消关联）
+	LinkVideo(videoID int64, seriesID, seasonNumber *int64, locked bool) error
+}
+
+// seriesRepository 系列仓库实现
+type seriesRepository struct{}
+
+// NewSeriesRepository 创建系列仓库实例
+func NewSeriesRepository() SeriesRepository {
+	return &seriesRepository{}
+}
+
+// Create 创建系列记录
+func (r *seriesRepository) Create(series *model.Series) error {
+	return database.DB.Create(series).Error
+}
+
+// FindByID 根据ID查找系列
+func (r *seriesRepository) FindByID(seriesID int64) (*model.Series, error) {
+	var series model.Series
+	err := database.DB.Where("id = ?", seriesID).First(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindByTitleAndType 根据系列标题和类型查找系列
+func (r *seriesRepository) FindByTitleAndType(title, videoType string) (*model.Series, error) {
+	var series model.Series
+	err := database.DB.Where("title = ? AND type = ?", title, videoType).First(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindSeasons 查找系列下的所有视频（按季数升序，季数为空的排在最后）
+func (r *seriesRepository) FindSeasons(seriesID int64) ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Where("series_id = ?", seriesID).
+		Order("season_number IS NULL, season_number ASC, id ASC").
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// FindUnlinkedVideoByTitle 根据标题和类型查找未关联系列且未锁定的视频
+func (r *seriesRepository) FindUnlinkedVideoByTitle(title, videoType string) (*model.Video, error) {
+	var video model.Video
+	err := database.DB.Where("title = ? AND type = ? AND series_id IS NULL AND series_locked = ?", title, videoType, false).
+		Order("id ASC").
+		First(&video).Error
+	if err != nil {
+		return nil, err
+	}
+	return &video, nil
+}
+
+// FindUnlinkedVideos 查找所有未关联系列且未锁定的视频（返回 id、title、type、cover_url、description）
+func (r *seriesRepository) FindUnlinkedVideos() ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Select("id", "title", "type", "cover_url", "description").
+		Where("series_id IS NULL AND series_locked = ?", false).
+		Order("id ASC").
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// ExistsSeason 判断系列中是否已存在指定季数的视频
+func (r *seriesRepository) ExistsSeason(seriesID, seasonNumber int64) (bool, error) {
+	var count int64
+	err := database.DB.Model(&model.Video{}).
+		Where("series_id = ? AND season_number = ?", seriesID, seasonNumber).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// LinkVideo 设置视频的系列关联（seriesID和seasonNumber为nil时表示取消关联）
+func (r *seriesRepository) LinkVideo(videoID int64, seriesID, seasonNumber *int64, locked bool) error {
+	err := database.DB.Model(&model.Video{}).
+		Where("id = ?", videoID).
+		Updates(map[string]interface{}{
+			"series_id":     seriesID,
+			"season_number": seasonNumber,
+			"series_locked": locked,
+		}).Error
+	if err != nil {
+		return err
+	}
+	InvalidateVideoCache(videoID)
+	return nil
+}
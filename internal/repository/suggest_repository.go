@@ -0,0 +1,84 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+)
+
+// SuggestRepository 搜索建议索引的数据来源仓库接口
+type SuggestRepository interface {
+	// FindVideosBatch 按ID升序分批查询视频（ID大于afterID，返回 id、title、type、cover_url、score、status）
+	// since不为nil时只返回 updated_at 不早于since的视频，publishedOnly为true时只返回已发布的视频
+	FindVideosBatch(since *time.Time, publishedOnly bool, afterID int64, limit int) ([]*model.Video, error)
+
+	// FindVideosByIDs 查询指定ID的视频（返回字段同 FindVideosBatch），不存在的ID不返回
+	FindVideosByIDs(videoIDs []int64) ([]*model.Video, error)
+
+	// FindTitlesByVideoIDs 查询视频的别名，按视频ID分组
+	FindTitlesByVideoIDs(videoIDs []int64) (map[int64][]string, error)
+}
+
+// suggestRepository 搜索建议索引的数据来源仓库实现
+type suggestRepository struct{}
+
+// NewSuggestRepository 创建搜索建议索引的数据来源仓库实例
+func NewSuggestRepository() SuggestRepository {
+	return &suggestRepository{}
+}
+
+// suggestVideoColumns 建立搜索建议索引需要的视频字段
+var suggestVideoColumns = []string{"id", "title", "type", "cover_url", "score", "status"}
+
+// FindVideosBatch 按ID升序分批查询视频
+func (r *suggestRepository) FindVideosBatch(since *time.Time, publishedOnly bool, afterID int64, limit int) ([]*model.Video, error) {
+	query := database.DB.Select(suggestVideoColumns).Where("id > ?", afterID)
+	if since != nil {
+		query = query.Where("updated_at >= ?", *since)
+	}
+	if publishedOnly {
+		query = query.Where("status = ?", "1")
+	}
+
+	var videos []*model.Video
+	if err := query.Order("id ASC").Limit(limit).Find(&videos).Error; err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// FindVideosByIDs 查询指定ID的视频
+func (r *suggestRepository) FindVideosByIDs(videoIDs []int64) ([]*model.Video, error) {
+	var videos []*model.Video
+	if len(videoIDs) == 0 {
+		return videos, nil
+	}
+	err := database.DB.Select(suggestVideoColumns).Where("id IN ?", videoIDs).Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// FindTitlesByVideoIDs 查询视频的别名，按视频ID分组
+func (r *suggestRepository) FindTitlesByVideoIDs(videoIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string)
+	if len(videoIDs) == 0 {
+		return result, nil
+	}
+
+	var titles []*model.VideoTitle
+	err := database.DB.Select("video_id", "title").
+		Where("video_id IN ?", videoIDs).
+		Order("id ASC").
+		Find(&titles).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range titles {
+		result[t.VideoID] = append(result[t.VideoID], t.Title)
+	}
+	return result, nil
+}
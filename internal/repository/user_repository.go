@@ -0,0 +1,63 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	stderrors "errors"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry MySQL唯一键冲突的错误码
+const mysqlErrDuplicateEntry = 1062
+
+// UserRepository 用户仓库接口
+type UserRepository interface {
+	// FindByUsername 根据用户名查找用户
+	FindByUsername(username string) (*model.User, error)
+
+	// FindByID 根据用户ID查找用户
+	FindByID(id int64) (*model.User, error)
+
+	// Create 创建用户，用户名已存在时返回的错误满足 IsDuplicateKey
+	Create(user *model.User) error
+}
+
+// userRepository 用户仓库实现
+type userRepository struct{}
+
+// NewUserRepository 创建用户仓库实例
+func NewUserRepository() UserRepository {
+	return &userRepository{}
+}
+
+// FindByUsername 根据用户名查找用户
+func (r *userRepository) FindByUsername(username string) (*model.User, error) {
+	var user model.User
+	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByID 根据用户ID查找用户
+func (r *userRepository) FindByID(id int64) (*model.User, error) {
+	var user model.User
+	if err := database.DB.Where("id = ?", id).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Create 创建用户
+func (r *userRepository) Create(user *model.User) error {
+	return database.DB.Create(user).Error
+}
+
+// IsDuplicateKey 判断错误是否为唯一键冲突
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return stderrors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
@@ -0,0 +1,85 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+)
+
+// UserTokenRepository 用户登录令牌仓库接口
+type UserTokenRepository interface {
+	// Create 保存登录令牌，令牌已存在时返回的错误满足 IsDuplicateKey
+	Create(token *model.UserToken) error
+
+	// FindByToken 根据令牌查找记录
+	FindByToken(token string) (*model.UserToken, error)
+
+	// FindActiveIDs 查询用户有效（未停用且未过期）的令牌ID，最新登录的在前
+	FindActiveIDs(userID int64) ([]int64, error)
+
+	// Deactivate 停用指定的令牌
+	Deactivate(ids []int64) error
+
+	// DeactivateToken 停用指定令牌字符串对应的记录，返回是否有记录被停用
+	DeactivateToken(token string) (bool, error)
+}
+
+// userTokenRepository 用户登录令牌仓库实现
+type userTokenRepository struct{}
+
+// NewUserTokenRepository 创建用户登录令牌仓库实例
+func NewUserTokenRepository() UserTokenRepository {
+	return &userTokenRepository{}
+}
+
+// Create 保存登录令牌
+func (r *userTokenRepository) Create(token *model.UserToken) error {
+	return database.DB.Create(token).Error
+}
+
+// FindByToken 根据令牌查找记录
+func (r *userTokenRepository) FindByToken(token string) (*model.UserToken, error) {
+	var record model.UserToken
+	if err := database.DB.Where("token = ?", token).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindActiveIDs 查询用户有效的令牌ID（按登录时间降序，时间相同时按ID降序）
+func (r *userTokenRepository) FindActiveIDs(userID int64) ([]int64, error) {
+	var ids []int64
+	err := database.DB.Model(&model.UserToken{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Order("id DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Deactivate 停用指定的令牌
+func (r *userTokenRepository) Deactivate(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return database.DB.Model(&model.UserToken{}).
+		Where("id IN ?", ids).
+		Update("is_active", false).Error
+}
+
+// DeactivateToken 停用指定令牌字符串对应的记录
+func (r *userTokenRepository) DeactivateToken(token string) (bool, error) {
+	result := database.DB.Model(&model.UserToken{}).
+		Where("token = ? AND is_active = ?", token, true).
+		Update("is_active", false)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,444 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/cache"
+	"video-service/pkg/infrastructure/config"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+// 视频缓存的Redis键
+// cache:video:{id} 为视频记录，cache:episodes:{video_id} 为视频的剧集列表，
+// cache:video_list:{gen}:{hash} 为列表查询结果，gen 保存在 cache:video_list:gen 中，任何视频变化时加1使全部列表缓存失效
+const (
+	videoCacheKeyPrefix     = "cache:video:"
+	episodesCacheKeyPrefix  = "cache:episodes:"
+	videoListCacheKeyPrefix = "cache:video_list:"
+	videoListCacheGenKey    = "cache:video_list:gen"
+)
+
+// 缓存时间默认值
+const (
+	defaultVideoCacheTTL     = 10 * time.Minute
+	defaultEpisodesCacheTTL  = 10 * time.Minute
+	defaultVideoListCacheTTL = time.Minute
+	defaultMissingCacheTTL   = 30 * time.Second
+)
+
+// videoCacheMissing 不存在的记录缓存为该值（不是合法的JSON，不会与查询结果混淆），避免反复查询MySQL
+const videoCacheMissing = "-"
+
+// videoCacheTimeout 单次读写Redis的超时时间，超时后直接查询MySQL
+const videoCacheTimeout = 200 * time.Millisecond
+
+// videoCacheScanCount 清除全部缓存时每次SCAN的数量
+const videoCacheScanCount = 500
+
+// videoCacheGroup 合并同一个键的并发回源查询（缓存失效时只有一个请求查询MySQL）
+// 服务实例按请求创建，因此使用包级变量在所有实例间共享
+var videoCacheGroup singleflight.Group
+
+// VideoCache 视频、剧集和列表查询的读穿透缓存
+type VideoCache struct {
+	rdb         *redis.Client
+	videoTTL    time.Duration
+	episodesTTL time.Duration
+	listTTL     time.Duration
+	missingTTL  time.Duration
+}
+
+// NewVideoCache 创建视频缓存实例，未配置Redis（redis.addr）时返回nil
+// 配置项（均为可选）：
+//   - cache.video_ttl: 视频记录缓存时间（默认10m）
+//   - cache.episodes_ttl: 剧集列表缓存时间（默认10m）
+//   - cache.video_list_ttl: 列表查询缓存时间（默认1m）
+//   - cache.missing_ttl: 不存在的记录的缓存时间（默认30s）
+func NewVideoCache() *VideoCache {
+	if cache.Rdb == nil {
+		return nil
+	}
+	return &VideoCache{
+		rdb:         cache.Rdb,
+		videoTTL:    cacheTTL("cache.video_ttl", defaultVideoCacheTTL),
+		episodesTTL: cacheTTL("cache.episodes_ttl", defaultEpisodesCacheTTL),
+		listTTL:     cacheTTL("cache.video_list_ttl", defaultVideoListCacheTTL),
+		missingTTL:  cacheTTL("cache.missing_ttl", defaultMissingCacheTTL),
+	}
+}
+
+// cacheTTL 读取缓存时间配置，未配置或不大于0时使用默认值
+func cacheTTL(key string, def time.Duration) time.Duration {
+	if ttl := config.Cfg.GetDuration(key); ttl > 0 {
+		return ttl
+	}
+	return def
+}
+
+// InvalidateVideos 清除指定视频的记录和剧集缓存，并使全部列表缓存失效
+func (c *VideoCache) InvalidateVideos(videoIDs ...int64) error {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), videoCacheTimeout)
+	defer cancel()
+
+	keys := make([]string, 0, len(videoIDs)*2)
+	for _, id := range videoIDs {
+		idStr := strconv.FormatInt(id, 10)
+		keys = append(keys, videoCacheKeyPrefix+idStr, episodesCacheKeyPrefix+idStr)
+	}
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.Incr(ctx, videoListCacheGenKey)
+		return nil
+	})
+	return err
+}
+
+// InvalidateAll 清除全部视频、剧集和列表缓存，用于批量更新（无法确定具体视频）之后
+func (c *VideoCache) InvalidateAll() error {
+	ctx := context.Background()
+	if err := c.rdb.Incr(ctx, videoListCacheGenKey).Err(); err != nil {
+		return err
+	}
+	for _, prefix := range []string{videoCacheKeyPrefix, episodesCacheKeyPrefix} {
+		iter := c.rdb.Scan(ctx, 0, prefix+"*", videoCacheScanCount).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if len(keys) >= videoCacheScanCount {
+				if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+					return err
+				}
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// load 读穿透：缓存命中时解码到dest，否则通过singleflight调用query查询并写入缓存
+// query返回 gorm.ErrRecordNotFound 时缓存空值；读写Redis失败只记录日志，直接使用查询结果
+func (c *VideoCache) load(key string, ttl time.Duration, dest interface{}, query func() (interface{}, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), videoCacheTimeout)
+	defer cancel()
+
+	data, err := c.rdb.Get(ctx, key).Bytes()
+	if err == nil {
+		if string(data) == videoCacheMissing {
+			return gorm.ErrRecordNotFound
+		}
+		if err := json.Unmarshal(data, dest); err == nil {
+			return nil
+		}
+		zap.L().Warn("缓存格式错误，重新查询", zap.String("key", key))
+	} else if err != redis.Nil {
+		zap.L().Warn("读取缓存失败", zap.String("key", key), zap.Error(err))
+	}
+
+	v, err, _ := videoCacheGroup.Do(key, func() (interface{}, error) {
+		result, err := query()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.set(key, []byte(videoCacheMissing), c.missingTTL)
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, data, ttl)
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	// 共享同一次查询的调用方各自解码一份，避免共用同一个对象
+	return json.Unmarshal(v.([]byte), dest)
+}
+
+// set 写入缓存，过期时间增加最多10%的随机抖动，避免同时写入的键同时过期
+func (c *VideoCache) set(key string, data []byte, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), videoCacheTimeout)
+	defer cancel()
+
+	if jitter := int64(ttl / 10); jitter > 0 {
+		ttl += time.Duration(rand.Int63n(jitter))
+	}
+	if err := c.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
+		zap.L().Warn("写入缓存失败", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// listGeneration 返回当前列表缓存的版本号
+func (c *VideoCache) listGeneration() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), videoCacheTimeout)
+	defer cancel()
+
+	gen, err := c.rdb.Get(ctx, videoListCacheGenKey).Result()
+	if err == redis.Nil {
+		return "0", nil
+	}
+	return gen, err
+}
+
+// InvalidateVideoCache 清除指定视频的缓存（未配置Redis时不处理），失败只记录日志
+// 用于不经过缓存装饰器修改视频或剧集的仓库（封面、系列、合并、回滚等）
+func InvalidateVideoCache(videoIDs ...int64) {
+	c := NewVideoCache()
+	if c == nil {
+		return
+	}
+	if err := c.InvalidateVideos(videoIDs...); err != nil {
+		zap.L().Warn("清除视频缓存失败", zap.Int64s("video_ids", videoIDs), zap.Error(err))
+	}
+}
+
+// InvalidateAllVideoCache 清除全部视频缓存（未配置Redis时不处理），失败只记录日志
+func InvalidateAllVideoCache() {
+	c := NewVideoCache()
+	if c == nil {
+		return
+	}
+	if err := c.InvalidateAll(); err != nil {
+		zap.L().Warn("清除全部视频缓存失败", zap.Error(err))
+	}
+}
+
+// cachedVideoRepository 带缓存的视频仓库：FindByID 读穿透缓存（writeOnly时直接查询MySQL），写操作成功后清除对应视频的缓存
+type cachedVideoRepository struct {
+	VideoRepository
+	cache     *VideoCache
+	writeOnly bool
+}
+
+// NewCachedVideoRepository 为视频仓库添加缓存，未配置Redis时直接返回repo
+func NewCachedVideoRepository(repo VideoRepository) VideoRepository {
+	c := NewVideoCache()
+	if c == nil {
+		return repo
+	}
+	return &cachedVideoRepository{VideoRepository: repo, cache: c}
+}
+
+// NewInvalidatingVideoRepository 为视频仓库添加写后清除缓存，读取不经过缓存，未配置Redis时直接返回repo
+// 用于同步、重新解析等写路径：读到的记录要写回MySQL或作为审计的修改前快照，
+// 缓存的记录可能已过期，且经过JSON序列化丢失了 json:"-" 的字段（cover_mirror_failures）
+func NewInvalidatingVideoRepository(repo VideoRepository) VideoRepository {
+	c := NewVideoCache()
+	if c == nil {
+		return repo
+	}
+	return &cachedVideoRepository{VideoRepository: repo, cache: c, writeOnly: true}
+}
+
+// FindByID 根据ID查找视频，优先读取缓存
+// 缓存的记录经过JSON序列化，不包含 json:"-" 的字段（cover_mirror_failures），需要完整记录时使用 NewInvalidatingVideoRepository
+func (r *cachedVideoRepository) FindByID(videoID int64) (*model.Video, error) {
+	if r.writeOnly {
+		return r.VideoRepository.FindByID(videoID)
+	}
+	var video model.Video
+	err := r.cache.load(videoCacheKeyPrefix+strconv.FormatInt(videoID, 10), r.cache.videoTTL, &video, func() (interface{}, error) {
+		return r.VideoRepository.FindByID(videoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &video, nil
+}
+
+// Create 创建视频记录（清除可能存在的空值缓存）
+func (r *cachedVideoRepository) Create(video *model.Video) error {
+	if err := r.VideoRepository.Create(video); err != nil {
+		return err
+	}
+	r.invalidate(video.ID)
+	return nil
+}
+
+// Update 更新视频记录
+func (r *cachedVideoRepository) Update(video *model.Video) error {
+	if err := r.VideoRepository.Update(video); err != nil {
+		return err
+	}
+	r.invalidate(video.ID)
+	return nil
+}
+
+// UpdateDetails 更新视频详情字段
+func (r *cachedVideoRepository) UpdateDetails(video *model.Video) error {
+	if err := r.VideoRepository.UpdateDetails(video); err != nil {
+		return err
+	}
+	r.invalidate(video.ID)
+	return nil
+}
+
+// UpdateVideosStatusByEpisodes 批量更新视频状态（无法确定具体视频，清除全部缓存）
+func (r *cachedVideoRepository) UpdateVideosStatusByEpisodes(status string) error {
+	if err := r.VideoRepository.UpdateVideosStatusByEpisodes(status); err != nil {
+		return err
+	}
+	if err := r.cache.InvalidateAll(); err != nil {
+		zap.L().Warn("清除全部视频缓存失败", zap.Error(err))
+	}
+	return nil
+}
+
+// UpdateVideoStatus 更新指定视频的status
+func (r *cachedVideoRepository) UpdateVideoStatus(videoID int64, status string) error {
+	if err := r.VideoRepository.UpdateVideoStatus(videoID, status); err != nil {
+		return err
+	}
+	r.invalidate(videoID)
+	return nil
+}
+
+// UpdateVideoIsUpdate 更新指定视频的is_update字段
+func (r *cachedVideoRepository) UpdateVideoIsUpdate(videoID int64, isUpdate bool) error {
+	if err := r.VideoRepository.UpdateVideoIsUpdate(videoID, isUpdate); err != nil {
+		return err
+	}
+	r.invalidate(videoID)
+	return nil
+}
+
+// UpdateVideoIsCompleted 更新指定视频的is_completed字段
+func (r *cachedVideoRepository) UpdateVideoIsCompleted(videoID int64, isCompleted bool) error {
+	if err := r.VideoRepository.UpdateVideoIsCompleted(videoID, isCompleted); err != nil {
+		return err
+	}
+	r.invalidate(videoID)
+	return nil
+}
+
+// TouchUpdatedAt 将指定视频的updated_at更新为当前时间
+func (r *cachedVideoRepository) TouchUpdatedAt(videoID int64) error {
+	if err := r.VideoRepository.TouchUpdatedAt(videoID); err != nil {
+		return err
+	}
+	r.invalidate(videoID)
+	return nil
+}
+
+// invalidate 清除视频缓存，失败只记录日志（数据库已更新，缓存最迟在过期后恢复一致）
+func (r *cachedVideoRepository) invalidate(videoID int64) {
+	if err := r.cache.InvalidateVideos(videoID); err != nil {
+		zap.L().Warn("清除视频缓存失败", zap.Int64("video_id", videoID), zap.Error(err))
+	}
+}
+
+// cachedEpisodeRepository 带缓存的剧集仓库：FindByVideoID 读穿透缓存（writeOnly时直接查询MySQL），新增剧集后清除对应视频的缓存
+type cachedEpisodeRepository struct {
+	EpisodeRepository
+	cache     *VideoCache
+	writeOnly bool
+}
+
+// NewCachedEpisodeRepository 为剧集仓库添加缓存，未配置Redis时直接返回repo
+func NewCachedEpisodeRepository(repo EpisodeRepository) EpisodeRepository {
+	c := NewVideoCache()
+	if c == nil {
+		return repo
+	}
+	return &cachedEpisodeRepository{EpisodeRepository: repo, cache: c}
+}
+
+// NewInvalidatingEpisodeRepository 为剧集仓库添加写后清除缓存，读取不经过缓存（用于同步等写路径），未配置Redis时直接返回repo
+func NewInvalidatingEpisodeRepository(repo EpisodeRepository) EpisodeRepository {
+	c := NewVideoCache()
+	if c == nil {
+		return repo
+	}
+	return &cachedEpisodeRepository{EpisodeRepository: repo, cache: c, writeOnly: true}
+}
+
+// FindByVideoID 根据视频ID查找所有剧集，优先读取缓存
+func (r *cachedEpisodeRepository) FindByVideoID(videoID int64) ([]*model.Episode, error) {
+	if r.writeOnly {
+		return r.EpisodeRepository.FindByVideoID(videoID)
+	}
+	var episodes []*model.Episode
+	err := r.cache.load(episodesCacheKeyPrefix+strconv.FormatInt(videoID, 10), r.cache.episodesTTL, &episodes, func() (interface{}, error) {
+		return r.EpisodeRepository.FindByVideoID(videoID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return episodes, nil
+}
+
+// Create 创建剧集记录（同时更新了视频的updated_at，一并清除视频缓存）
+func (r *cachedEpisodeRepository) Create(episode *model.Episode) error {
+	if err := r.EpisodeRepository.Create(episode); err != nil {
+		return err
+	}
+	if err := r.cache.InvalidateVideos(episode.VideoID); err != nil {
+		zap.L().Warn("清除视频缓存失败", zap.Int64("video_id", episode.VideoID), zap.Error(err))
+	}
+	return nil
+}
+
+// cachedVideoListRepository 带缓存的视频列表仓库，缓存键由查询条件的哈希和列表缓存版本号组成
+type cachedVideoListRepository struct {
+	VideoListRepository
+	cache *VideoCache
+}
+
+// NewCachedVideoListRepository 为视频列表仓库添加缓存，未配置Redis时直接返回repo
+func NewCachedVideoListRepository(repo VideoListRepository) VideoListRepository {
+	c := NewVideoCache()
+	if c == nil {
+		return repo
+	}
+	return &cachedVideoListRepository{VideoListRepository: repo, cache: c}
+}
+
+// ListPublished 按条件查询已发布的视频，优先读取缓存；读取版本号失败时直接查询
+func (r *cachedVideoListRepository) ListPublished(filter *VideoListFilter) ([]*model.Video, error) {
+	gen, err := r.cache.listGeneration()
+	if err != nil {
+		zap.L().Warn("读取列表缓存版本失败", zap.Error(err))
+		return r.VideoListRepository.ListPublished(filter)
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return r.VideoListRepository.ListPublished(filter)
+	}
+	sum := sha1.Sum(data)
+	key := videoListCacheKeyPrefix + gen + ":" + hex.EncodeToString(sum[:])
+
+	var videos []*model.Video
+	err = r.cache.load(key, r.cache.listTTL, &videos, func() (interface{}, error) {
+		return r.VideoListRepository.ListPublished(filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
@@ -0,0 +1,169 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// 视频列表排序方式
+const (
+	VideoSortReleaseDate = "release_date" // 上映日期降序（默认）
+	VideoSortScore       = "score"        // 评分降序
+	VideoSortUpdated     = "updated"      // 最近更新降序
+)
+
+// videoSortColumns 排序方式对应的排序列（均为降序，相同时按id降序）
+var videoSortColumns = map[string]string{
+	VideoSortReleaseDate: "release_date",
+	VideoSortScore:       "score",
+	VideoSortUpdated:     "updated_at",
+}
+
+// IsValidVideoSort 判断是否为支持的排序方式
+func IsValidVideoSort(sort string) bool {
+	_, ok := videoSortColumns[sort]
+	return ok
+}
+
+// VideoListCursor 键集分页游标：上一页最后一条视频的排序列取值和ID
+// 排序列取值为nil表示上一页停在该列为NULL的视频中（NULL排在最后）
+type VideoListCursor struct {
+	ReleaseDate *time.Time
+	Score       *float64
+	UpdatedAt   *time.Time
+	ID          int64
+}
+
+// VideoListFilter 视频列表查询条件，零值字段表示不筛选
+type VideoListFilter struct {
+	Type          string
+	Country       string   // 国家/地区代码，使用多值索引 idx_country_mv
+	Tag           string   // 类型代码，使用多值索引 idx_tags_mv
+	Director      string   // 导演姓名，使用多值索引 idx_director_mv
+	Actor         string   // 演员姓名，使用多值索引 idx_actors_mv
+	YearFrom      int      // 上映年份下限（含）
+	YearTo        int      // 上映年份上限（含）
+	ScoreMin      *float64 // 评分下限（含）
+	ScoreMax      *float64 // 评分上限（含）
+	IsCompleted   *bool
+	IsUpdate      *bool
+	ReleasedSince *time.Time       // 上映日期下限（含）
+	CreatedSince  *time.Time       // 入库时间下限（含）
+	Sort          string           // 排序方式，见 VideoSort* 常量
+	Cursor        *VideoListCursor // 为nil时从第一条开始
+	Limit         int
+}
+
+// VideoListRepository 视频列表仓库接口
+type VideoListRepository interface {
+	// ListPublished 按条件查询已发布的视频，按排序方式和游标返回最多 Limit 条
+	ListPublished(filter *VideoListFilter) ([]*model.Video, error)
+}
+
+// videoListRepository 视频列表仓库实现
+type videoListRepository struct{}
+
+// NewVideoListRepository 创建视频列表仓库实例
+func NewVideoListRepository() VideoListRepository {
+	return &videoListRepository{}
+}
+
+// ListPublished 按条件查询已发布（status = 1）的视频
+// 排序列降序、id降序，排序列为NULL的视频排在最后；游标条件与排序一致，翻页期间新增的视频不会导致重复或遗漏
+func (r *videoListRepository) ListPublished(filter *VideoListFilter) ([]*model.Video, error) {
+	column, ok := videoSortColumns[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("不支持的排序方式: %q", filter.Sort)
+	}
+
+	query := database.DB.Model(&model.Video{}).Where("status = ?", "1")
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	// MEMBER OF 可以使用 CAST(... AS CHAR(32) ARRAY) 多值索引
+	if filter.Country != "" {
+		query = query.Where("? MEMBER OF(country_json)", filter.Country)
+	}
+	if filter.Tag != "" {
+		query = query.Where("? MEMBER OF(tags_json)", filter.Tag)
+	}
+	if filter.Director != "" {
+		query = query.Where("? MEMBER OF(director_json)", filter.Director)
+	}
+	if filter.Actor != "" {
+		query = query.Where("? MEMBER OF(actors_json)", filter.Actor)
+	}
+	// 年份范围转换为日期范围，可以使用 release_date 索引
+	if filter.YearFrom > 0 {
+		query = query.Where("release_date >= ?", time.Date(filter.YearFrom, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+	}
+	if filter.YearTo > 0 {
+		query = query.Where("release_date < ?", time.Date(filter.YearTo+1, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+	}
+	if filter.ScoreMin != nil {
+		query = query.Where("score >= ?", *filter.ScoreMin)
+	}
+	if filter.ScoreMax != nil {
+		query = query.Where("score <= ?", *filter.ScoreMax)
+	}
+	if filter.IsCompleted != nil {
+		query = query.Where("is_completed = ?", *filter.IsCompleted)
+	}
+	if filter.IsUpdate != nil {
+		query = query.Where("is_update = ?", *filter.IsUpdate)
+	}
+	if filter.ReleasedSince != nil {
+		query = query.Where("release_date >= ?", filter.ReleasedSince.Format("2006-01-02"))
+	}
+	if filter.CreatedSince != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedSince)
+	}
+	if filter.Cursor != nil {
+		query = applyVideoCursor(query, column, cursorValue(filter.Sort, filter.Cursor), filter.Cursor.ID)
+	}
+
+	var videos []*model.Video
+	err := query.
+		Order(column + " DESC").
+		Order("id DESC").
+		Limit(filter.Limit).
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// cursorValue 返回游标中排序方式对应的排序列取值，为NULL时返回nil
+func cursorValue(sort string, cursor *VideoListCursor) interface{} {
+	switch sort {
+	case VideoSortScore:
+		if cursor.Score != nil {
+			return *cursor.Score
+		}
+	case VideoSortUpdated:
+		if cursor.UpdatedAt != nil {
+			return *cursor.UpdatedAt
+		}
+	default:
+		if cursor.ReleaseDate != nil {
+			return cursor.ReleaseDate.Format("2006-01-02")
+		}
+	}
+	return nil
+}
+
+// applyVideoCursor 添加键集分页条件：(column, id) 排在游标之后
+// MySQL降序排序时NULL在最后，因此游标取值不为NULL时后续视频还包括该列为NULL的视频
+func applyVideoCursor(query *gorm.DB, column string, value interface{}, id int64) *gorm.DB {
+	if value == nil {
+		return query.Where(column+" IS NULL AND id < ?", id)
+	}
+	return query.Where("("+column+" < ? OR ("+column+" = ? AND id < ?) OR "+column+" IS NULL)", value, value, id)
+}
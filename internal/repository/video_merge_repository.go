@@ -0,0 +1,296 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"strings"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// VideoMergeRepository 视频合并仓库接口（跨来源去重）
+type VideoMergeRepository interface {
+	// FindDedupCandidates 查找参与去重的视频（返回去重和合并所需的字段）
+	FindDedupCandidates() ([]*model.Video, error)
+
+	// Merge 在一个事务中将重复视频合并到规范视频，columns 为规范视频需要更新的列（补全的空字段）
+	Merge(canonical *model.Video, columns map[string]interface{}, duplicates []*model.Video, reason string) (*VideoMergeResult, error)
+}
+
+// VideoMergeResult 合并时转移和删除的剧集（用于记录目录变更）
+type VideoMergeResult struct {
+	MovedEpisodes   []*model.Episode // 改挂到规范视频的剧集，VideoID 为原所属的重复视频
+	DeletedEpisodes []*model.Episode // 集数与规范视频已有剧集重复而删除的剧集
+}
+
+// videoMergeRepository 视频合并仓库实现
+type videoMergeRepository struct{}
+
+// NewVideoMergeRepository 创建视频合并仓库实例
+func NewVideoMergeRepository() VideoMergeRepository {
+	return &videoMergeRepository{}
+}
+
+// FindDedupCandidates 查找参与去重的视频（返回完整信息，合并时需要补全规范视频的空字段）
+func (r *videoMergeRepository) FindDedupCandidates() ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Order("id ASC").Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// Merge 在一个事务中将重复视频合并到规范视频：
+//  1. 更新规范视频补全的列：只更新columns中的列，canonical 是去重扫描开始时的快照，整行保存会覆盖期间的并发修改
+//  2. 剧集：按集数合并，规范视频已有的集数将弹幕转移到已有剧集后删除；规范视频缺少的集数改挂到规范视频
+//  3. 收藏：改挂到规范视频，同一用户只保留一条
+//  4. 演职员：规范视频没有演职员时，采用第一个有演职员的重复视频的记录；其余删除
+//  5. 别名：改挂到规范视频（重复的删除），重复视频的标题与规范视频不同时作为又名保留
+//  6. 各地区上映日期：与演职员相同，规范视频没有时采用第一个有记录的重复视频的记录
+//  7. 写入重定向记录，并将指向重复视频的旧重定向改指向规范视频
+//  8. 删除重复视频
+//
+// 弹幕通过 episode_id 关联剧集，随剧集一起转移
+func (r *videoMergeRepository) Merge(canonical *model.Video, columns map[string]interface{}, duplicates []*model.Video, reason string) (*VideoMergeResult, error) {
+	result := &VideoMergeResult{}
+	duplicateIDs := make([]int64, 0, len(duplicates))
+	for _, dup := range duplicates {
+		duplicateIDs = append(duplicateIDs, dup.ID)
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if len(columns) > 0 {
+			if err := tx.Model(&model.Video{}).Where("id = ?", canonical.ID).Updates(columns).Error; err != nil {
+				return err
+			}
+		}
+
+		moved, deleted, err := mergeEpisodes(tx, canonical.ID, duplicateIDs)
+		if err != nil {
+			return err
+		}
+		result.MovedEpisodes, result.DeletedEpisodes = moved, deleted
+
+		if err := mergeFavorites(tx, canonical.ID, duplicateIDs); err != nil {
+			return err
+		}
+
+		if err := adoptChildRows(tx, &model.VideoCredit{}, canonical.ID, duplicateIDs); err != nil {
+			return err
+		}
+
+		if err := adoptChildRows(tx, &model.VideoReleaseDate{}, canonical.ID, duplicateIDs); err != nil {
+			return err
+		}
+
+		if err := mergeTitles(tx, canonical, duplicates); err != nil {
+			return err
+		}
+
+		// 已合并到重复视频的旧ID改为直接指向规范视频，避免出现重定向链
+		if err := tx.Model(&model.VideoRedirect{}).
+			Where("to_video_id IN ?", duplicateIDs).
+			Update("to_video_id", canonical.ID).Error; err != nil {
+			return err
+		}
+
+		for _, dup := range duplicates {
+			redirect := &model.VideoRedirect{
+				FromVideoID: dup.ID,
+				ToVideoID:   canonical.ID,
+				Source:      dup.Source,
+				SourceID:    dup.SourceID,
+				Reason:      reason,
+			}
+			if err := tx.Create(redirect).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("id IN ?", duplicateIDs).Delete(&model.Video{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	InvalidateVideoCache(append([]int64{canonical.ID}, duplicateIDs...)...)
+	return result, nil
+}
+
+// mergeEpisodes 将重复视频的剧集按集数合并到规范视频：
+// 不同来源的同一集播放地址不同，只能按集数判断是否重复。规范视频已有的集数保留规范视频的剧集，
+// 重复视频的该集弹幕转移过去后删除；规范视频缺少的集数改挂到规范视频（多个重复视频都有时取ID最小的一个），
+// 保证合并后每个集数只有一条剧集，同步按剧集数量判断的增量和完结逻辑不受影响
+// 返回改挂的剧集和删除的剧集（转移前的完整记录）
+func mergeEpisodes(tx *gorm.DB, canonicalID int64, duplicateIDs []int64) ([]*model.Episode, []*model.Episode, error) {
+	var existing []*model.Episode
+	if err := tx.Select("id", "episode_number").Where("video_id = ?", canonicalID).Order("id ASC").Find(&existing).Error; err != nil {
+		return nil, nil, err
+	}
+	byNumber := make(map[int64]int64, len(existing))
+	for _, ep := range existing {
+		if _, ok := byNumber[episodeNumberOf(ep)]; !ok {
+			byNumber[episodeNumberOf(ep)] = ep.ID
+		}
+	}
+
+	var episodes []*model.Episode
+	if err := tx.Where("video_id IN ?", duplicateIDs).Order("id ASC").Find(&episodes).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var moved, deleted []*model.Episode
+	var moveIDs []int64
+	for _, ep := range episodes {
+		number := episodeNumberOf(ep)
+		targetID, ok := byNumber[number]
+		if !ok {
+			byNumber[number] = ep.ID
+			moved = append(moved, ep)
+			moveIDs = append(moveIDs, ep.ID)
+			continue
+		}
+		// 集数已存在的重复剧集：弹幕转移到保留的剧集后删除
+		if err := tx.Model(&model.Danmaku{}).Where("episode_id = ?", ep.ID).Update("episode_id", targetID).Error; err != nil {
+			return nil, nil, err
+		}
+		if err := tx.Delete(&model.Episode{}, ep.ID).Error; err != nil {
+			return nil, nil, err
+		}
+		deleted = append(deleted, ep)
+	}
+
+	if len(moveIDs) == 0 {
+		return moved, deleted, nil
+	}
+	if err := tx.Model(&model.Episode{}).Where("id IN ?", moveIDs).Update("video_id", canonicalID).Error; err != nil {
+		return nil, nil, err
+	}
+	return moved, deleted, nil
+}
+
+// episodeNumberOf 返回剧集的集数，未设置时按列默认值1处理
+func episodeNumberOf(ep *model.Episode) int64 {
+	if ep.EpisodeNumber == nil {
+		return 1
+	}
+	return *ep.EpisodeNumber
+}
+
+// mergeFavorites 将重复视频的收藏改挂到规范视频，同一用户只保留最早的一条
+func mergeFavorites(tx *gorm.DB, canonicalID int64, duplicateIDs []int64) error {
+	videoIDs := append([]int64{canonicalID}, duplicateIDs...)
+
+	var favorites []*model.UserFavorite
+	if err := tx.Where("video_id IN ?", videoIDs).Order("created_at ASC, id ASC").Find(&favorites).Error; err != nil {
+		return err
+	}
+
+	kept := make(map[int64]bool, len(favorites))
+	var moveIDs, deleteIDs []int
+	for _, fav := range favorites {
+		if kept[fav.UserID] {
+			deleteIDs = append(deleteIDs, fav.ID)
+			continue
+		}
+		kept[fav.UserID] = true
+		if fav.VideoID != canonicalID {
+			moveIDs = append(moveIDs, fav.ID)
+		}
+	}
+
+	if len(deleteIDs) > 0 {
+		if err := tx.Where("id IN ?", deleteIDs).Delete(&model.UserFavorite{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(moveIDs) > 0 {
+		if err := tx.Model(&model.UserFavorite{}).Where("id IN ?", moveIDs).Update("video_id", canonicalID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adoptChildRows 合并按视频整体保存的子记录（演职员、各地区上映日期等）：
+// 规范视频已有记录时直接删除重复视频的记录，否则改挂第一个有记录的重复视频的记录（避免不同来源的记录混在一起）
+func adoptChildRows(tx *gorm.DB, rowModel interface{}, canonicalID int64, duplicateIDs []int64) error {
+	var count int64
+	if err := tx.Model(rowModel).Where("video_id = ?", canonicalID).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		for _, dupID := range duplicateIDs {
+			result := tx.Model(rowModel).Where("video_id = ?", dupID).Update("video_id", canonicalID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				break
+			}
+		}
+	}
+
+	return tx.Where("video_id IN ?", duplicateIDs).Delete(rowModel).Error
+}
+
+// mergeTitles 将重复视频的别名改挂到规范视频，与规范视频标题或已有别名相同的删除；
+// 重复视频的标题与规范视频不同时作为又名保留，便于按旧标题搜索
+func mergeTitles(tx *gorm.DB, canonical *model.Video, duplicates []*model.Video) error {
+	var existing []*model.VideoTitle
+	if err := tx.Select("title").Where("video_id = ?", canonical.ID).Find(&existing).Error; err != nil {
+		return err
+	}
+	// 按小写去重（title列的排序规则不区分大小写，唯一索引同样如此）
+	seen := map[string]bool{strings.ToLower(canonical.Title): true}
+	for _, title := range existing {
+		seen[strings.ToLower(title.Title)] = true
+	}
+
+	duplicateIDs := make([]int64, 0, len(duplicates))
+	for _, dup := range duplicates {
+		duplicateIDs = append(duplicateIDs, dup.ID)
+	}
+
+	var titles []*model.VideoTitle
+	if err := tx.Where("video_id IN ?", duplicateIDs).Order("id ASC").Find(&titles).Error; err != nil {
+		return err
+	}
+
+	var moveIDs, deleteIDs []int64
+	for _, title := range titles {
+		key := strings.ToLower(title.Title)
+		if seen[key] {
+			deleteIDs = append(deleteIDs, title.ID)
+			continue
+		}
+		seen[key] = true
+		moveIDs = append(moveIDs, title.ID)
+	}
+
+	if len(deleteIDs) > 0 {
+		if err := tx.Where("id IN ?", deleteIDs).Delete(&model.VideoTitle{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(moveIDs) > 0 {
+		if err := tx.Model(&model.VideoTitle{}).Where("id IN ?", moveIDs).Update("video_id", canonical.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, dup := range duplicates {
+		key := strings.ToLower(dup.Title)
+		if dup.Title == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := tx.Create(&model.VideoTitle{VideoID: canonical.ID, Title: dup.Title, Kind: model.VideoTitleKindAlias}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
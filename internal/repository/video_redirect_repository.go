@@ -0,0 +1,46 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+)
+
+// VideoRedirectRepository 视频重定向仓库接口
+type VideoRedirectRepository interface {
+	// FindByFromID 根据被合并的视频ID查找重定向记录
+	FindByFromID(videoID int64) (*model.VideoRedirect, error)
+
+	// ExistsBySourceID 判断指定来源和来源ID的视频是否已被合并（同步时据此跳过，避免重新创建重复视频）
+	ExistsBySourceID(source string, sourceID int64) (bool, error)
+}
+
+// videoRedirectRepository 视频重定向仓库实现
+type videoRedirectRepository struct{}
+
+// NewVideoRedirectRepository 创建视频重定向仓库实例
+func NewVideoRedirectRepository() VideoRedirectRepository {
+	return &videoRedirectRepository{}
+}
+
+// FindByFromID 根据被合并的视频ID查找重定向记录
+func (r *videoRedirectRepository) FindByFromID(videoID int64) (*model.VideoRedirect, error) {
+	var redirect model.VideoRedirect
+	err := database.DB.Where("from_video_id = ?", videoID).First(&redirect).Error
+	if err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// ExistsBySourceID 判断指定来源和来源ID的视频是否已被合并（不同来源的ID可能相同，必须同时匹配来源）
+func (r *videoRedirectRepository) ExistsBySourceID(source string, sourceID int64) (bool, error) {
+	var count int64
+	err := database.DB.Model(&model.VideoRedirect{}).
+		Where("source = ? AND source_id = ?", source, sourceID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,48 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// VideoReleaseDateRepository 视频各地区上映日期仓库接口
+type VideoReleaseDateRepository interface {
+	// FindByVideoID 查询视频的全部上映日期（按日期升序）
+	FindByVideoID(videoID int64) ([]*model.VideoReleaseDate, error)
+
+	// ReplaceReleaseDates 替换视频的全部上映日期（事务内先删除后插入）
+	ReplaceReleaseDates(videoID int64, dates []*model.VideoReleaseDate) error
+}
+
+// videoReleaseDateRepository 视频各地区上映日期仓库实现
+type videoReleaseDateRepository struct{}
+
+// NewVideoReleaseDateRepository 创建视频各地区上映日期仓库实例
+func NewVideoReleaseDateRepository() VideoReleaseDateRepository {
+	return &videoReleaseDateRepository{}
+}
+
+// FindByVideoID 查询视频的全部上映日期（按日期升序）
+func (r *videoReleaseDateRepository) FindByVideoID(videoID int64) ([]*model.VideoReleaseDate, error) {
+	var dates []*model.VideoReleaseDate
+	if err := database.DB.Where("video_id = ?", videoID).Order("release_date ASC, id ASC").Find(&dates).Error; err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// ReplaceReleaseDates 替换视频的全部上映日期（事务内先删除后插入）
+func (r *videoReleaseDateRepository) ReplaceReleaseDates(videoID int64, dates []*model.VideoReleaseDate) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("video_id = ?", videoID).Delete(&model.VideoReleaseDate{}).Error; err != nil {
+			return err
+		}
+		if len(dates) == 0 {
+			return nil
+		}
+		return tx.Create(&dates).Error
+	})
+}
@@ -2,6 +2,7 @@
 package repository
 
 import (
+	"time"
 	"video-service/internal/model"
 	"video-service/pkg/infrastructure/database"
 )
@@ -32,9 +33,12 @@ type VideoRepository interface {
 	// FindVideosByStatusNotEqual 查找 status 不等于指定值的视频（返回 id、type、title）
 	FindVideosByStatusNotEqual(status string) ([]*model.Video, error)
 
-	// FindVideosNeedUpdateEpisodes 查找需要更新episodes的视频（status不等于0和1，返回 id、type、title）
+	// FindVideosNeedUpdateEpisodes 查找需要更新episodes的视频（status不等于0和1，返回 id、source、source_id、type、title、status）
 	FindVideosNeedUpdateEpisodes() ([]*model.Video, error)
 
+	// FindVideosWithEpisodesByStatusNotEqual 查找存在 episodes 记录且 status 不等于指定值的视频（返回 id、source、source_id、type、title、status）
+	FindVideosWithEpisodesByStatusNotEqual(status string) ([]*model.Video, error)
+
 	// UpdateVideosStatusByEpisodes 更新存在 episodes 记录的 videos 的 status
 	UpdateVideosStatusByEpisodes(status string) error
 
@@ -47,6 +51,9 @@ type VideoRepository interface {
 	// UpdateVideoIsCompleted 更新指定视频的is_completed字段
 	UpdateVideoIsCompleted(videoID int64, isCompleted bool) error
 
+	// TouchUpdatedAt 将指定视频的updated_at更新为当前时间
+	TouchUpdatedAt(videoID int64) error
+
 	// FindByID 根据ID查找视频（返回完整信息）
 	FindByID(videoID int64) (*model.Video, error)
 }
@@ -163,12 +170,12 @@ func (r *videoRepository) FindVideosByStatusNotEqual(status string) ([]*model.Vi
 	return videos, nil
 }
 
-// FindVideosNeedUpdateEpisodes 查找需要更新episodes的视频（status不等于0且is_completed不等于1，返回 id、type、title）
+// FindVideosNeedUpdateEpisodes 查找需要更新episodes的视频（status不等于0且is_completed不等于1，返回 id、source、source_id、type、title、status）
 func (r *videoRepository) FindVideosNeedUpdateEpisodes() ([]*model.Video, error) {
 	var videos []*model.Video
 	// 查询条件：status != '0'（包括NULL）且 is_completed != 1（包括NULL）
 	// 明确处理NULL值，确保查询结果一致
-	err := database.DB.Select("id", "type", "title").
+	err := database.DB.Select("id", "source", "source_id", "type", "title", "status").
 		Where("(status IS NULL OR status != ?) AND (is_completed IS NULL OR is_completed != ?)", "0", true).
 		Find(&videos).Error
 	if err != nil {
@@ -177,19 +184,32 @@ func (r *videoRepository) FindVideosNeedUpdateEpisodes() ([]*model.Video, error)
 	return videos, nil
 }
 
+// FindVideosWithEpisodesByStatusNotEqual 查找存在 episodes 记录且 status 不等于指定值的视频（返回 id、source、source_id、type、title、status）
+// 与 UpdateVideosStatusByEpisodes 的更新范围一致，可用于在批量更新前获取受影响的视频
+func (r *videoRepository) FindVideosWithEpisodesByStatusNotEqual(status string) ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Select("id", "source", "source_id", "type", "title", "status").
+		Where("(status != ? OR status IS NULL) AND id IN (SELECT DISTINCT video_id FROM episodes)", status).
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
 // UpdateVideosStatusByEpisodes 更新存在 episodes 记录的 videos 的 status
 func (r *videoRepository) UpdateVideosStatusByEpisodes(status string) error {
-	// 执行 SQL: UPDATE videos v JOIN (SELECT DISTINCT video_id FROM episodes) e ON v.id = e.video_id SET v.status = ? WHERE v.status != ? OR v.status IS NULL
-	// 更新所有 status 不等于目标值的视频（包括 NULL 和其他非目标值）
+	// 执行 SQL: UPDATE videos v JOIN (SELECT DISTINCT video_id FROM episodes) e ON v.id = e.video_id SET v.status = ?, v.updated_at = ? WHERE v.status != ? OR v.status IS NULL
+	// 更新所有 status 不等于目标值的视频（包括 NULL 和其他非目标值），同时更新 updated_at 以便增量索引感知状态变化
 	err := database.DB.Exec(`
 		UPDATE videos v
 		JOIN (
 			SELECT DISTINCT video_id
 			FROM episodes
 		) e ON v.id = e.video_id
-		SET v.status = ?
+		SET v.status = ?, v.updated_at = ?
 		WHERE v.status != ? OR v.status IS NULL
-	`, status, status).Error
+	`, status, time.Now(), status).Error
 	return err
 }
 
@@ -214,6 +234,13 @@ func (r *videoRepository) UpdateVideoIsCompleted(videoID int64, isCompleted bool
 		Update("is_completed", isCompleted).Error
 }
 
+// TouchUpdatedAt 将指定视频的updated_at更新为当前时间
+func (r *videoRepository) TouchUpdatedAt(videoID int64) error {
+	return database.DB.Model(&model.Video{}).
+		Where("id = ?", videoID).
+		Update("updated_at", time.Now()).Error
+}
+
 // FindByID 根据ID查找视频（返回完整信息）
 func (r *videoRepository) FindByID(videoID int64) (*model.Video, error) {
 	var video model.Video
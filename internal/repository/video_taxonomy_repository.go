@@ -0,0 +1,55 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/datatypes"
+)
+
+// VideoTaxonomyRepository 视频国家/地区和类型标签的批量读写（用于规范化回填）
+type VideoTaxonomyRepository interface {
+	// FindBatch 按ID升序查询ID大于afterID的视频（只返回 id、country_json、tags_json）
+	FindBatch(afterID int64, limit int) ([]*model.Video, error)
+
+	// UpdateTaxonomy 更新视频的 country_json 和 tags_json
+	UpdateTaxonomy(videoID int64, countryJSON, tagsJSON datatypes.JSON) error
+}
+
+// videoTaxonomyRepository 视频国家/地区和类型标签仓库实现
+type videoTaxonomyRepository struct{}
+
+// NewVideoTaxonomyRepository 创建视频国家/地区和类型标签仓库实例
+func NewVideoTaxonomyRepository() VideoTaxonomyRepository {
+	return &videoTaxonomyRepository{}
+}
+
+// FindBatch 按ID升序查询ID大于afterID的视频
+func (r *videoTaxonomyRepository) FindBatch(afterID int64, limit int) ([]*model.Video, error) {
+	var videos []*model.Video
+	err := database.DB.Select("id", "country_json", "tags_json").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&videos).Error
+	if err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// UpdateTaxonomy 更新视频的 country_json 和 tags_json（不修改 updated_at）
+func (r *videoTaxonomyRepository) UpdateTaxonomy(videoID int64, countryJSON, tagsJSON datatypes.JSON) error {
+	err := database.DB.Model(&model.Video{}).
+		Where("id = ?", videoID).
+		UpdateColumns(map[string]interface{}{
+			"country_json": countryJSON,
+			"tags_json":    tagsJSON,
+		}).Error
+	if err != nil {
+		return err
+	}
+	InvalidateVideoCache(videoID)
+	return nil
+}
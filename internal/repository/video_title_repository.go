@@ -0,0 +1,51 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+// VideoTitleRepository 视频别名仓库接口
+type VideoTitleRepository interface {
+	// FindByVideoID 查询视频的全部别名（原名在前）
+	FindByVideoID(videoID int64) ([]*model.VideoTitle, error)
+
+	// ReplaceTitles 替换视频的全部别名（事务内先删除后插入）
+	ReplaceTitles(videoID int64, titles []*model.VideoTitle) error
+}
+
+// videoTitleRepository 视频别名仓库实现
+type videoTitleRepository struct{}
+
+// NewVideoTitleRepository 创建视频别名仓库实例
+func NewVideoTitleRepository() VideoTitleRepository {
+	return &videoTitleRepository{}
+}
+
+// FindByVideoID 查询视频的全部别名（原名在前）
+func (r *videoTitleRepository) FindByVideoID(videoID int64) ([]*model.VideoTitle, error) {
+	var titles []*model.VideoTitle
+	err := database.DB.Where("video_id = ?", videoID).
+		Order("kind = 'original' DESC, id ASC").
+		Find(&titles).Error
+	if err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+// ReplaceTitles 替换视频的全部别名（事务内先删除后插入）
+func (r *videoTitleRepository) ReplaceTitles(videoID int64, titles []*model.VideoTitle) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("video_id = ?", videoID).Delete(&model.VideoTitle{}).Error; err != nil {
+			return err
+		}
+		if len(titles) == 0 {
+			return nil
+		}
+		return tx.Create(&titles).Error
+	})
+}
@@ -0,0 +1,139 @@
+// repository 包提供数据访问层，封装数据库操作
+package repository
+
+import (
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/database"
+)
+
+// WebhookDeliveryFilter Webhook投递记录查询条件（为空的字段不参与过滤）
+type WebhookDeliveryFilter struct {
+	Event    string
+	Endpoint string
+	Status   string
+}
+
+// WebhookDeliveryRepository Webhook投递记录仓库接口
+type WebhookDeliveryRepository interface {
+	// Create 创建投递记录
+	Create(delivery *model.WebhookDelivery) error
+
+	// Update 更新投递记录（更新所有字段）
+	Update(delivery *model.WebhookDelivery) error
+
+	// ResetFinished 将已结束（success/failed）的投递记录重置为 pending 并清零尝试次数，next_retry_at 设为 leaseUntil（由调用方占用）
+	// 记录不存在或仍处于 pending 状态时不修改，返回false
+	ResetFinished(id int64, leaseUntil time.Time) (bool, error)
+
+	// Claim 占用到期的 pending 投递记录：next_retry_at 为空或不晚于now时改为 leaseUntil，返回是否占用成功
+	// 多个进程（服务和 cmd/sync）同时继续投递同一条记录时只有一个能占用成功
+	Claim(id int64, now, leaseUntil time.Time) (bool, error)
+
+	// FindByID 根据ID查找投递记录
+	FindByID(id int64) (*model.WebhookDelivery, error)
+
+	// FindPending 查找未完成的投递记录（用于服务重启后继续投递）
+	FindPending(limit int) ([]*model.WebhookDelivery, error)
+
+	// List 按条件分页查询投递记录（按ID降序），返回记录列表和总数
+	List(filter WebhookDeliveryFilter, page, pageSize int) ([]*model.WebhookDelivery, int64, error)
+}
+
+// webhookDeliveryRepository Webhook投递记录仓库实现
+type webhookDeliveryRepository struct{}
+
+// NewWebhookDeliveryRepository 创建Webhook投递记录仓库实例
+func NewWebhookDeliveryRepository() WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{}
+}
+
+// Create 创建投递记录
+func (r *webhookDeliveryRepository) Create(delivery *model.WebhookDelivery) error {
+	return database.DB.Create(delivery).Error
+}
+
+// Update 更新投递记录（更新所有字段）
+func (r *webhookDeliveryRepository) Update(delivery *model.WebhookDelivery) error {
+	return database.DB.Save(delivery).Error
+}
+
+// ResetFinished 将已结束（success/failed）的投递记录重置为 pending 并清零尝试次数，next_retry_at 设为 leaseUntil
+// 记录不存在或仍处于 pending 状态时不修改，返回false
+func (r *webhookDeliveryRepository) ResetFinished(id int64, leaseUntil time.Time) (bool, error) {
+	result := database.DB.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status IN ?", id, []string{model.WebhookStatusSuccess, model.WebhookStatusFailed}).
+		Updates(map[string]interface{}{
+			"status":        model.WebhookStatusPending,
+			"attempts":      0,
+			"next_retry_at": leaseUntil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Claim 占用到期的 pending 投递记录，返回是否占用成功
+func (r *webhookDeliveryRepository) Claim(id int64, now, leaseUntil time.Time) (bool, error) {
+	result := database.DB.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND (next_retry_at IS NULL OR next_retry_at <= ?)", id, model.WebhookStatusPending, now).
+		Update("next_retry_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindByID 根据ID查找投递记录
+func (r *webhookDeliveryRepository) FindByID(id int64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := database.DB.Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindPending 查找未完成的投递记录（用于服务重启后继续投递）
+func (r *webhookDeliveryRepository) FindPending(limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := database.DB.Where("status = ?", model.WebhookStatusPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// List 按条件分页查询投递记录（按ID降序），返回记录列表和总数
+func (r *webhookDeliveryRepository) List(filter WebhookDeliveryFilter, page, pageSize int) ([]*model.WebhookDelivery, int64, error) {
+	query := database.DB.Model(&model.WebhookDelivery{})
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if filter.Endpoint != "" {
+		query = query.Where("endpoint = ?", filter.Endpoint)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []*model.WebhookDelivery
+	err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
@@ -3,17 +3,23 @@
 package router
 
 import (
+	"net/http"
+
 	"video-service/internal/handler"
 	"video-service/internal/middleware"
+	"video-service/internal/service"
+	"video-service/pkg/infrastructure/blobstore"
+	"video-service/pkg/infrastructure/tracing"
 
 	"github.com/gin-gonic/gin"
 	ginprom "github.com/zsais/go-gin-prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
 // SetupRouter 配置并返回Gin路由引擎
 // 功能包括：
-// 1. 注册全局中间件（Trace、Recovery、Logger）
+// 1. 注册全局中间件（OpenTelemetry、Trace、Recovery、Logger、Metrics）
 // 2. 集成Prometheus监控
 // 3. 注册公开API端点（健康检查）
 // 4. 注册监控指标端点（由go-gin-prometheus自动注册）
@@ -34,12 +40,18 @@ func SetupRouter() *gin.Engine {
 	}
 
 	// 注册全局中间件（按顺序执行）
+	// otelgin: 为每个请求创建OpenTelemetry span（读取请求头中的W3C traceparent）
+	r.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+		return req.URL.Path != "/metrics" // 不追踪 Prometheus 抓取请求
+	})))
 	// Trace: 生成请求追踪ID
 	r.Use(middleware.Trace())
 	// RecoveryWithZap: 捕获panic并记录日志
 	r.Use(middleware.RecoveryWithZap(log))
 	// LoggerWithZap: 记录HTTP请求日志
 	r.Use(middleware.LoggerWithZap(log))
+	// Metrics: 记录HTTP请求数和耗时指标
+	r.Use(middleware.Metrics())
 
 	// 集成Prometheus监控中间件，服务名称为"video_service"
 	p := ginprom.NewPrometheus("video_service")
@@ -48,6 +60,14 @@ func SetupRouter() *gin.Engine {
 	// 注册公开API端点（无需认证）
 	r.GET("/ping", handler.Ping) // 健康检查
 
+	// 本地对象存储的静态文件（镜像的封面图片等）
+	if local, ok := blobstore.Blob.(*blobstore.LocalStore); ok {
+		r.Static(local.URLPrefix(), local.Dir())
+	}
+
+	// token停用检查和用户当前角色查询（查询登录记录和用户），所有需要认证的路由共用一个实例
+	tokenChecker := service.NewAuthService()
+
 	// API路由组
 	apiGroup := r.Group("/api")
 	{
@@ -56,6 +76,66 @@ func SetupRouter() *gin.Engine {
 		{
 			// 豆瓣电影同步接口（手动触发）
 			syncGroup.POST("/douban/movies", handler.SyncDoubanMovies)
+			// 豆瓣同步预演接口（不写入数据库，生成变更预览报告）
+			syncGroup.POST("/douban/dry-run", handler.SyncDoubanDryRun)
+			syncGroup.GET("/douban/dry-run/:run_id", handler.GetDoubanDryRun)
+		}
+
+		// 用户认证接口
+		authGroup := apiGroup.Group("/auth")
+		{
+			authGroup.POST("/register", handler.Register)
+			authGroup.POST("/login", handler.Login)
+			// 退出登录（需要JWT认证）
+			authGroup.POST("/logout", middleware.JWTAuth(tokenChecker), handler.Logout)
+		}
+
+		// 首页推荐位
+		apiGroup.GET("/home", handler.GetHome)
+
+		// 视频相关接口
+		apiGroup.GET("/videos", handler.ListVideos)
+		apiGroup.GET("/videos/:id", handler.GetVideo)
+		apiGroup.GET("/videos/:id/resolve", handler.ResolveVideo)
+
+		// 系列相关接口
+		apiGroup.GET("/series/:id", handler.GetSeries)
+
+		// 人物相关接口
+		apiGroup.GET("/people/:id", handler.GetPerson)
+
+		// 筛选项接口
+		apiGroup.GET("/filters", handler.GetFilters)
+		apiGroup.GET("/dictionary", handler.GetDictionary)
+
+		// 搜索接口
+		apiGroup.GET("/search", handler.SearchVideos)
+		apiGroup.GET("/search/suggest", handler.SuggestVideos)
+
+		// 管理接口（需要JWT认证，且token的角色为管理员）
+		adminGroup := apiGroup.Group("/admin", middleware.JWTAuth(tokenChecker), middleware.AdminAuth())
+		{
+			// 视频系列关联的手动覆盖
+			adminGroup.PUT("/videos/:id/series", handler.SetVideoSeries)
+			adminGroup.DELETE("/videos/:id/series", handler.UnlinkVideoSeries)
+			adminGroup.POST("/videos/:id/series/unlock", handler.UnlockVideoSeries)
+			// 存量视频的系列关联回填
+			adminGroup.POST("/series/relink", handler.RelinkSeries)
+			// 跨来源视频去重
+			adminGroup.POST("/videos/dedup", handler.DedupVideos)
+			// 封面镜像（存量回填）
+			adminGroup.POST("/covers/mirror", handler.MirrorCovers)
+			// 重新统计筛选项
+			adminGroup.POST("/filters/refresh", handler.RefreshFilters)
+			// 重建搜索建议索引
+			adminGroup.POST("/search/suggest/rebuild", handler.RebuildSuggestions)
+			// 视频字段变更历史和回滚
+			adminGroup.GET("/videos/:id/changes", handler.ListVideoChanges)
+			adminGroup.POST("/changes/:id/revert", handler.RevertCatalogChange)
+			// Webhook投递记录查询和重新投递
+			adminGroup.GET("/webhooks/deliveries", handler.ListWebhookDeliveries)
+			adminGroup.GET("/webhooks/deliveries/:id", handler.GetWebhookDelivery)
+			adminGroup.POST("/webhooks/deliveries/:id/redeliver", handler.RedeliverWebhook)
 		}
 	}
 
@@ -0,0 +1,265 @@
+// service 包提供业务逻辑层
+// auth_service.go 提供用户注册、登录和退出登录：密码使用bcrypt加密，每次登录记录一条令牌（设备、IP），超过最大设备数时停用最早登录的令牌
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+	"unicode/utf8"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/auth"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/utils"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 用户名和密码长度限制（用户名和密码最小长度为字符数，密码最大长度为字节数）
+const (
+	usernameMinLength = 4
+	usernameMaxLength = 15
+	passwordMinLength = 6
+	passwordMaxBytes  = 72 // bcrypt最多使用72字节，超出时无法加密
+)
+
+// 登录参数默认值
+const (
+	defaultTokenTTL   = 7 * 24 * time.Hour
+	defaultMaxDevices = 5
+	deviceMaxLength   = 100 // 与 user_tokens.device 列长度一致
+)
+
+// RegisterRequest 注册请求
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Device   string `json:"device"` // 设备名称，为空时使用User-Agent
+}
+
+// LoginResult 登录结果
+type LoginResult struct {
+	Token     string      `json:"token"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	User      *model.User `json:"user"`
+}
+
+// AuthService 用户认证服务
+type AuthService struct {
+	userRepo   repository.UserRepository
+	tokenRepo  repository.UserTokenRepository
+	tokenTTL   time.Duration
+	maxDevices int
+}
+
+// NewAuthService 创建用户认证服务实例
+// 配置项：
+//   - auth.token_ttl: 登录令牌有效期（默认168h）
+//   - auth.max_devices: 每个用户同时有效的最大设备（令牌）数，超过时停用最早登录的令牌（默认5）
+func NewAuthService() *AuthService {
+	tokenTTL := config.Cfg.GetDuration("auth.token_ttl")
+	if tokenTTL <= 0 {
+		tokenTTL = defaultTokenTTL
+	}
+	maxDevices := config.Cfg.GetInt("auth.max_devices")
+	if maxDevices <= 0 {
+		maxDevices = defaultMaxDevices
+	}
+	return &AuthService{
+		userRepo:   repository.NewUserRepository(),
+		tokenRepo:  repository.NewUserTokenRepository(),
+		tokenTTL:   tokenTTL,
+		maxDevices: maxDevices,
+	}
+}
+
+// Register 注册用户，用户名为4-15个字母或数字，密码至少6个字符且不超过72字节，注册的用户为普通用户
+func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*model.User, error) {
+	if req.Username == "" || req.Password == "" {
+		return nil, errors.ErrUsernamePasswordEmpty
+	}
+	if err := validateUsername(req.Username); err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(req.Password) < passwordMinLength || len(req.Password) > passwordMaxBytes {
+		return nil, errors.ErrPasswordLengthInvalid
+	}
+
+	if _, err := s.userRepo.FindByUsername(req.Username); err == nil {
+		return nil, errors.ErrUsernameDuplicate
+	} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		logger.FromContext(ctx).Error("查询用户失败", zap.String("username", req.Username), zap.Error(err))
+		return nil, errors.ErrUserQueryFailed
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		logger.FromContext(ctx).Error("密码加密失败", zap.Error(err))
+		return nil, errors.ErrPasswordEncryptFailed
+	}
+
+	user := &model.User{
+		ID:       utils.GenerateUserID(), // 使用雪花算法生成ID
+		Username: req.Username,
+		Password: string(hash),
+		Nickname: req.Nickname,
+		Email:    req.Email,
+		Role:     model.UserRoleUser,
+	}
+	if user.Nickname == "" {
+		user.Nickname = req.Username
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		// 并发注册同一用户名时由唯一索引拦截
+		if repository.IsDuplicateKey(err) {
+			return nil, errors.ErrUsernameDuplicate
+		}
+		logger.FromContext(ctx).Error("创建用户失败", zap.String("username", req.Username), zap.Error(err))
+		return nil, errors.ErrUserCreateFailed
+	}
+
+	logger.FromContext(ctx).Info("用户注册成功", zap.Int64("user_id", user.ID), zap.String("username", user.Username))
+	return user, nil
+}
+
+// Login 校验用户名和密码，生成令牌并记录登录设备和IP
+// 用户有效的令牌超过 auth.max_devices 时，停用最早登录的令牌
+func (s *AuthService) Login(ctx context.Context, req *LoginRequest, userAgent, ip string) (*LoginResult, error) {
+	if req.Username == "" || req.Password == "" {
+		return nil, errors.ErrUsernamePasswordEmpty
+	}
+
+	user, err := s.userRepo.FindByUsername(req.Username)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrUsernamePasswordError
+		}
+		logger.FromContext(ctx).Error("查询用户失败", zap.String("username", req.Username), zap.Error(err))
+		return nil, errors.ErrUserQueryFailed
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return nil, errors.ErrUsernamePasswordError
+	}
+
+	expiresAt := time.Now().Add(s.tokenTTL)
+	token, err := auth.GenerateToken(user.Username, user.Role, expiresAt)
+	if err != nil {
+		logger.FromContext(ctx).Error("生成token失败", zap.String("username", user.Username), zap.Error(err))
+		return nil, errors.ErrTokenGenerateFailed
+	}
+
+	device := req.Device
+	if device == "" {
+		device = userAgent
+	}
+	record := &model.UserToken{
+		UserID:    user.ID,
+		Token:     token,
+		Device:    truncateRunes(device, deviceMaxLength),
+		IPAddress: ip,
+		ExpiresAt: &expiresAt,
+		IsActive:  true,
+	}
+	if err := s.tokenRepo.Create(record); err != nil {
+		if repository.IsDuplicateKey(err) {
+			return nil, errors.ErrTokenDuplicate
+		}
+		logger.FromContext(ctx).Error("保存token失败", zap.Int64("user_id", user.ID), zap.Error(err))
+		return nil, errors.ErrTokenSaveFailed
+	}
+
+	// 先保存新令牌再停用超出数量的旧令牌，同一用户并发登录时最终也只保留最新的 max_devices 个
+	if err := s.deactivateExcessTokens(ctx, user.ID); err != nil {
+		// 新令牌不返回给客户端，一并停用
+		if deactivateErr := s.tokenRepo.Deactivate([]int64{record.ID}); deactivateErr != nil {
+			logger.FromContext(ctx).Error("停用新token失败", zap.Int64("token_id", record.ID), zap.Error(deactivateErr))
+		}
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("用户登录成功",
+		zap.Int64("user_id", user.ID),
+		zap.String("username", user.Username),
+		zap.String("device", record.Device),
+		zap.String("ip", ip))
+	return &LoginResult{Token: token, ExpiresAt: expiresAt, User: user}, nil
+}
+
+// Logout 停用当前令牌（令牌已停用或不存在时同样视为成功）
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if _, err := s.tokenRepo.DeactivateToken(token); err != nil {
+		logger.FromContext(ctx).Error("停用token失败", zap.Error(err))
+		return errors.ErrTokenDeactivateFailed
+	}
+	return nil
+}
+
+// CheckToken 判断令牌是否已停用（退出登录或被新设备挤下线），未停用时返回令牌所属用户在数据库中的当前角色
+// 没有登录记录的令牌和用户已不存在的令牌视为停用
+func (s *AuthService) CheckToken(token string) (string, bool, error) {
+	record, err := s.tokenRepo.FindByToken(token)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return "", true, nil
+		}
+		return "", false, err
+	}
+	if !record.IsActive {
+		return "", true, nil
+	}
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return "", true, nil
+		}
+		return "", false, err
+	}
+	return user.Role, false, nil
+}
+
+// deactivateExcessTokens 停用用户超出最大设备数的令牌（保留最近登录的 maxDevices 个）
+func (s *AuthService) deactivateExcessTokens(ctx context.Context, userID int64) error {
+	ids, err := s.tokenRepo.FindActiveIDs(userID)
+	if err != nil {
+		logger.FromContext(ctx).Error("查询token数量失败", zap.Int64("user_id", userID), zap.Error(err))
+		return errors.ErrTokenQueryFailed
+	}
+	if len(ids) <= s.maxDevices {
+		return nil
+	}
+
+	excess := ids[s.maxDevices:]
+	if err := s.tokenRepo.Deactivate(excess); err != nil {
+		logger.FromContext(ctx).Error("停用旧token失败", zap.Int64("user_id", userID), zap.Int64s("token_ids", excess), zap.Error(err))
+		return errors.ErrTokenDeactivateFailed
+	}
+	logger.FromContext(ctx).Info("超过最大设备数，已停用最早登录的token", zap.Int64("user_id", userID), zap.Int64s("token_ids", excess))
+	return nil
+}
+
+// validateUsername 校验用户名：长度为4-15个字符，只能包含字母和数字
+func validateUsername(username string) error {
+	if n := utf8.RuneCountInString(username); n < usernameMinLength || n > usernameMaxLength {
+		return errors.ErrUsernameLengthInvalid
+	}
+	for _, r := range username {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return errors.ErrUsernameInvalidChars
+		}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/auth"
+	"video-service/internal/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// fakeUserRepository 内存中的用户仓库
+type fakeUserRepository struct {
+	users map[string]*model.User
+}
+
+// FindByUsername 实现 repository.UserRepository
+func (r *fakeUserRepository) FindByUsername(username string) (*model.User, error) {
+	user, ok := r.users[username]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// FindByID 实现 repository.UserRepository
+func (r *fakeUserRepository) FindByID(id int64) (*model.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Create 实现 repository.UserRepository
+func (r *fakeUserRepository) Create(user *model.User) error {
+	copied := *user
+	r.users[user.Username] = &copied
+	return nil
+}
+
+// fakeUserTokenRepository 内存中的登录令牌仓库
+type fakeUserTokenRepository struct {
+	tokens []*model.UserToken
+}
+
+// Create 实现 repository.UserTokenRepository
+func (r *fakeUserTokenRepository) Create(token *model.UserToken) error {
+	token.ID = int64(len(r.tokens) + 1)
+	copied := *token
+	r.tokens = append(r.tokens, &copied)
+	return nil
+}
+
+// FindByToken 实现 repository.UserTokenRepository
+func (r *fakeUserTokenRepository) FindByToken(token string) (*model.UserToken, error) {
+	for _, record := range r.tokens {
+		if record.Token == token {
+			copied := *record
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindActiveIDs 实现 repository.UserTokenRepository，最新登录的在前
+func (r *fakeUserTokenRepository) FindActiveIDs(userID int64) ([]int64, error) {
+	var ids []int64
+	for _, record := range r.tokens {
+		if record.UserID == userID && record.IsActive {
+			ids = append(ids, record.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+	return ids, nil
+}
+
+// Deactivate 实现 repository.UserTokenRepository
+func (r *fakeUserTokenRepository) Deactivate(ids []int64) error {
+	for _, record := range r.tokens {
+		for _, id := range ids {
+			if record.ID == id {
+				record.IsActive = false
+			}
+		}
+	}
+	return nil
+}
+
+// DeactivateToken 实现 repository.UserTokenRepository
+func (r *fakeUserTokenRepository) DeactivateToken(token string) (bool, error) {
+	for _, record := range r.tokens {
+		if record.Token == token && record.IsActive {
+			record.IsActive = false
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newTestAuthService 创建使用内存仓库的认证服务
+func newTestAuthService(maxDevices int) (*AuthService, *fakeUserRepository, *fakeUserTokenRepository) {
+	users := &fakeUserRepository{users: map[string]*model.User{}}
+	tokens := &fakeUserTokenRepository{}
+	return &AuthService{
+		userRepo:   users,
+		tokenRepo:  tokens,
+		tokenTTL:   time.Hour,
+		maxDevices: maxDevices,
+	}, users, tokens
+}
+
+// mustRegister 注册用户，失败时终止测试
+func mustRegister(t *testing.T, s *AuthService, username, password string) *model.User {
+	t.Helper()
+	user, err := s.Register(context.Background(), &RegisterRequest{Username: username, Password: password})
+	if err != nil {
+		t.Fatalf("注册 %s 失败: %v", username, err)
+	}
+	return user
+}
+
+// mustLogin 登录，失败时终止测试
+func mustLogin(t *testing.T, s *AuthService, username, password string) *LoginResult {
+	t.Helper()
+	result, err := s.Login(context.Background(), &LoginRequest{Username: username, Password: password}, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("登录 %s 失败: %v", username, err)
+	}
+	return result
+}
+
+func TestRegister(t *testing.T) {
+	s, users, _ := newTestAuthService(5)
+
+	user := mustRegister(t, s, "tvuser01", "secret123")
+	if user.Role != model.UserRoleUser {
+		t.Errorf("注册用户的角色 = %q, 期望 %q", user.Role, model.UserRoleUser)
+	}
+	if user.Nickname != "tvuser01" {
+		t.Errorf("昵称为空时应使用用户名，实际 %q", user.Nickname)
+	}
+	if stored := users.users["tvuser01"]; stored == nil || stored.Password == "secret123" {
+		t.Errorf("密码应加密保存: %+v", stored)
+	}
+
+	if _, err := s.Register(context.Background(), &RegisterRequest{Username: "tvuser01", Password: "secret123"}); err != errors.ErrUsernameDuplicate {
+		t.Errorf("重复注册错误 = %v, 期望 ErrUsernameDuplicate", err)
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	s, _, _ := newTestAuthService(5)
+
+	cases := []struct {
+		name     string
+		username string
+		password string
+		want     error
+	}{
+		{"空密码", "tvuser01", "", errors.ErrUsernamePasswordEmpty},
+		{"用户名过短", "abc", "secret123", errors.ErrUsernameLengthInvalid},
+		{"用户名含符号", "tv_user", "secret123", errors.ErrUsernameInvalidChars},
+		{"密码过短", "tvuser01", "12345", errors.ErrPasswordLengthInvalid},
+		{"密码超过72字节", "tvuser01", strings.Repeat("a", 73), errors.ErrPasswordLengthInvalid},
+		// 25个汉字为75字节，字符数不多但超过bcrypt的限制
+		{"多字节密码超过72字节", "tvuser01", strings.Repeat("密", 25), errors.ErrPasswordLengthInvalid},
+	}
+	for _, c := range cases {
+		if _, err := s.Register(context.Background(), &RegisterRequest{Username: c.username, Password: c.password}); err != c.want {
+			t.Errorf("%s: 错误 = %v, 期望 %v", c.name, err, c.want)
+		}
+	}
+
+	// 恰好72字节的密码可以注册和登录
+	password := strings.Repeat("密", 24)
+	mustRegister(t, s, "tvuser02", password)
+	mustLogin(t, s, "tvuser02", password)
+}
+
+func TestLogin(t *testing.T) {
+	s, users, tokens := newTestAuthService(5)
+	mustRegister(t, s, "tvuser01", "secret123")
+
+	if _, err := s.Login(context.Background(), &LoginRequest{Username: "tvuser01", Password: "wrong"}, "", ""); err != errors.ErrUsernamePasswordError {
+		t.Errorf("密码错误时错误 = %v, 期望 ErrUsernamePasswordError", err)
+	}
+	if _, err := s.Login(context.Background(), &LoginRequest{Username: "nobody", Password: "secret123"}, "", ""); err != errors.ErrUsernamePasswordError {
+		t.Errorf("用户不存在时错误 = %v, 期望 ErrUsernamePasswordError", err)
+	}
+
+	result := mustLogin(t, s, "tvuser01", "secret123")
+	claims, err := auth.ParseToken(result.Token)
+	if err != nil {
+		t.Fatalf("解析token失败: %v", err)
+	}
+	if claims.Username != "tvuser01" || claims.Role != model.UserRoleUser {
+		t.Errorf("token载荷 = %q/%q", claims.Username, claims.Role)
+	}
+	if len(tokens.tokens) != 1 || tokens.tokens[0].Device != "test-agent" || !tokens.tokens[0].IsActive {
+		t.Errorf("登录记录 = %+v", tokens.tokens)
+	}
+
+	// 数据库中授权为管理员后，重新登录的token带管理员角色
+	users.users["tvuser01"].Role = model.UserRoleAdmin
+	adminToken := mustLogin(t, s, "tvuser01", "secret123").Token
+	claims, err = auth.ParseToken(adminToken)
+	if err != nil || claims.Role != model.UserRoleAdmin {
+		t.Errorf("管理员token角色 = %v (%v)", claims, err)
+	}
+
+	// 取消管理员后，已签发的token按数据库中的当前角色检查
+	users.users["tvuser01"].Role = model.UserRoleUser
+	if role, revoked, err := s.CheckToken(adminToken); err != nil || revoked || role != model.UserRoleUser {
+		t.Errorf("取消管理员后token角色 = %q, 停用 = %v (%v)", role, revoked, err)
+	}
+}
+
+func TestLoginMaxDevices(t *testing.T) {
+	s, _, _ := newTestAuthService(2)
+	mustRegister(t, s, "tvuser01", "secret123")
+
+	first := mustLogin(t, s, "tvuser01", "secret123")
+	second := mustLogin(t, s, "tvuser01", "secret123")
+	third := mustLogin(t, s, "tvuser01", "secret123")
+
+	// 超过最大设备数时停用最早登录的token
+	want := map[string]bool{first.Token: true, second.Token: false, third.Token: false}
+	for token, wantRevoked := range want {
+		_, revoked, err := s.CheckToken(token)
+		if err != nil {
+			t.Fatalf("查询token状态失败: %v", err)
+		}
+		if revoked != wantRevoked {
+			t.Errorf("token停用 = %v, 期望 %v", revoked, wantRevoked)
+		}
+	}
+}
+
+func TestLogoutRevokesToken(t *testing.T) {
+	s, _, _ := newTestAuthService(5)
+	mustRegister(t, s, "tvuser01", "secret123")
+	result := mustLogin(t, s, "tvuser01", "secret123")
+
+	if _, revoked, err := s.CheckToken(result.Token); err != nil || revoked {
+		t.Fatalf("新登录的token不应停用: %v (%v)", revoked, err)
+	}
+	if err := s.Logout(context.Background(), result.Token); err != nil {
+		t.Fatalf("退出登录失败: %v", err)
+	}
+	if _, revoked, err := s.CheckToken(result.Token); err != nil || !revoked {
+		t.Errorf("退出登录后token应停用: %v (%v)", revoked, err)
+	}
+	// 重复退出登录同样视为成功
+	if err := s.Logout(context.Background(), result.Token); err != nil {
+		t.Errorf("重复退出登录失败: %v", err)
+	}
+
+	// 没有登录记录的token视为停用
+	if _, revoked, err := s.CheckToken("unknown"); err != nil || !revoked {
+		t.Errorf("没有登录记录的token = %v (%v), 期望停用", revoked, err)
+	}
+}
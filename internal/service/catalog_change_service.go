@@ -0,0 +1,387 @@
+// service 包提供业务逻辑层
+// catalog_change_service.go 提供目录变更记录：记录 videos/episodes 的字段变更（旧值、新值、操作者、同步运行ID），支持查询视频历史和回滚单条变更
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// catalogAuditor 变更记录器，负责对比字段前后值并写入 catalog_changes
+// 记录失败只输出日志，不影响业务写操作；nil 记录器不做任何处理
+type catalogAuditor struct {
+	repo  repository.CatalogChangeRepository
+	actor string
+	runID string
+	log   *zap.Logger // 带 run_id（同步和重新解析时）的日志记录器
+}
+
+// newCatalogAuditor 创建变更记录器，runID为同步运行ID（非同步操作为空）
+func newCatalogAuditor(actor, runID string) *catalogAuditor {
+	log := zap.L()
+	if runID != "" {
+		log = log.With(zap.String("run_id", runID))
+	}
+	return &catalogAuditor{
+		repo:  repository.NewCatalogChangeRepository(),
+		actor: actor,
+		runID: runID,
+		log:   log,
+	}
+}
+
+// newChange 创建一条变更记录
+func (a *catalogAuditor) newChange(entityType string, entityID, videoID int64, field string, oldValue, newValue interface{}) *model.CatalogChange {
+	return &model.CatalogChange{
+		EntityType: entityType,
+		EntityID:   entityID,
+		VideoID:    videoID,
+		Field:      field,
+		OldValue:   encodeChangeValue(oldValue),
+		NewValue:   encodeChangeValue(newValue),
+		Actor:      a.actor,
+		SyncRunID:  a.runID,
+	}
+}
+
+// recordVideoDiff 逐字段对比视频的前后值，记录有变化的字段
+func (a *catalogAuditor) recordVideoDiff(before, after *model.Video) {
+	if a == nil {
+		return
+	}
+	beforeFields := videoFieldValues(before)
+	afterFields := videoFieldValues(after)
+	var changes []*model.CatalogChange
+	for i := range beforeFields {
+		if reflect.DeepEqual(beforeFields[i].value, afterFields[i].value) {
+			continue
+		}
+		changes = append(changes, a.newChange(model.CatalogEntityVideo, after.ID, after.ID, beforeFields[i].name, beforeFields[i].value, afterFields[i].value))
+	}
+	a.save(changes)
+}
+
+// recordVideoField 记录单个视频字段的变更（值相同时不记录）
+// 值的表示与 videoFieldValues 一致（日期为 YYYY-MM-DD，JSON列为解码后的结构）
+func (a *catalogAuditor) recordVideoField(videoID int64, field string, oldValue, newValue interface{}) {
+	if a == nil || reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+	a.save([]*model.CatalogChange{a.newChange(model.CatalogEntityVideo, videoID, videoID, field, oldValue, newValue)})
+}
+
+// recordCreated 记录新建的视频或剧集，新值为新建时的字段快照
+func (a *catalogAuditor) recordCreated(entityType string, entityID, videoID int64, snapshot map[string]interface{}) {
+	if a == nil {
+		return
+	}
+	a.save([]*model.CatalogChange{a.newChange(entityType, entityID, videoID, model.CatalogFieldCreated, nil, snapshot)})
+}
+
+// save 写入变更记录，失败只记录日志
+func (a *catalogAuditor) save(changes []*model.CatalogChange) {
+	if len(changes) == 0 {
+		return
+	}
+	if err := a.repo.Create(changes); err != nil {
+		a.log.Warn("写入目录变更记录失败",
+			zap.Int64("video_id", changes[0].VideoID),
+			zap.String("actor", a.actor),
+			zap.Int("changes", len(changes)),
+			zap.Error(err))
+	}
+}
+
+// encodeChangeValue 将字段值编码为JSON，nil 编码为 NULL
+func encodeChangeValue(v interface{}) datatypes.JSON {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// changeValueEqual 判断两个JSON值是否相同（MySQL会重新格式化JSON列，不能直接比较文本）
+func changeValueEqual(a, b datatypes.JSON) bool {
+	var av, bv interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &av); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &bv); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// episodeFieldMap 返回剧集新建时记录的字段快照
+func episodeFieldMap(e *model.Episode) map[string]interface{} {
+	return map[string]interface{}{
+		"channel":        e.Channel,
+		"episode_number": int64Value(e.EpisodeNumber),
+		"name":           e.Name,
+		"play_urls":      e.PlayURLs,
+	}
+}
+
+// auditedVideoRepository 记录变更的视频仓库，包装真实仓库，写操作成功后记录字段变更
+// 读操作直接使用被包装的仓库
+type auditedVideoRepository struct {
+	repository.VideoRepository
+	audit *catalogAuditor
+}
+
+// Create 创建视频并记录新建
+func (r *auditedVideoRepository) Create(video *model.Video) error {
+	if err := r.VideoRepository.Create(video); err != nil {
+		return err
+	}
+	r.audit.recordCreated(model.CatalogEntityVideo, video.ID, video.ID, videoFieldMap(video))
+	return nil
+}
+
+// Update 全字段更新视频并记录变更
+func (r *auditedVideoRepository) Update(video *model.Video) error {
+	return r.update(video.ID, func() error { return r.VideoRepository.Update(video) }, func(v *model.Video) {
+		*v = *video
+	})
+}
+
+// UpdateDetails 更新视频详情字段并记录变更
+func (r *auditedVideoRepository) UpdateDetails(video *model.Video) error {
+	return r.update(video.ID, func() error { return r.VideoRepository.UpdateDetails(video) }, func(v *model.Video) {
+		applyVideoDetails(v, video)
+	})
+}
+
+// UpdateVideoStatus 更新视频status并记录变更
+func (r *auditedVideoRepository) UpdateVideoStatus(videoID int64, status string) error {
+	return r.update(videoID, func() error { return r.VideoRepository.UpdateVideoStatus(videoID, status) }, func(v *model.Video) {
+		v.Status = status
+	})
+}
+
+// UpdateVideoIsUpdate 更新视频is_update并记录变更
+func (r *auditedVideoRepository) UpdateVideoIsUpdate(videoID int64, isUpdate bool) error {
+	return r.update(videoID, func() error { return r.VideoRepository.UpdateVideoIsUpdate(videoID, isUpdate) }, func(v *model.Video) {
+		v.IsUpdate = isUpdate
+	})
+}
+
+// UpdateVideoIsCompleted 更新视频is_completed并记录变更
+func (r *auditedVideoRepository) UpdateVideoIsCompleted(videoID int64, isCompleted bool) error {
+	return r.update(videoID, func() error { return r.VideoRepository.UpdateVideoIsCompleted(videoID, isCompleted) }, func(v *model.Video) {
+		v.IsCompleted = isCompleted
+	})
+}
+
+// UpdateVideosStatusByEpisodes 批量更新status，先查出将被更新的视频以便逐个记录变更
+func (r *auditedVideoRepository) UpdateVideosStatusByEpisodes(status string) error {
+	videos, err := r.VideoRepository.FindVideosWithEpisodesByStatusNotEqual(status)
+	if err != nil {
+		r.audit.log.Warn("查询将要更新status的视频失败，本次批量更新不记录变更", zap.Error(err))
+	}
+	if err := r.VideoRepository.UpdateVideosStatusByEpisodes(status); err != nil {
+		return err
+	}
+	for _, v := range videos {
+		r.audit.recordVideoField(v.ID, "status", v.Status, status)
+	}
+	return nil
+}
+
+// update 执行写操作并记录变更：写之前查询原值，写成功后对原值应用相同的修改得到新值
+// 原值查询失败时照常执行写操作，只是不记录变更
+func (r *auditedVideoRepository) update(videoID int64, write func() error, apply func(v *model.Video)) error {
+	before, err := r.VideoRepository.FindByID(videoID)
+	if err != nil {
+		r.audit.log.Warn("查询视频原值失败，本次更新不记录变更", zap.Int64("video_id", videoID), zap.Error(err))
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	if before == nil {
+		return nil
+	}
+	after := cloneVideo(before)
+	apply(after)
+	r.audit.recordVideoDiff(before, after)
+	return nil
+}
+
+// auditedEpisodeRepository 记录变更的剧集仓库，插入剧集后记录新建
+type auditedEpisodeRepository struct {
+	repository.EpisodeRepository
+	audit *catalogAuditor
+}
+
+// Create 创建剧集并记录新建
+func (r *auditedEpisodeRepository) Create(episode *model.Episode) error {
+	if err := r.EpisodeRepository.Create(episode); err != nil {
+		return err
+	}
+	r.audit.recordCreated(model.CatalogEntityEpisode, episode.ID, episode.VideoID, episodeFieldMap(episode))
+	return nil
+}
+
+// CatalogChangeService 目录变更记录服务（查询视频历史、回滚变更）
+type CatalogChangeService struct {
+	repo      repository.CatalogChangeRepository
+	videoRepo repository.VideoRepository
+	suggest   *SuggestService // 搜索建议索引，未配置Redis时为nil
+}
+
+// NewCatalogChangeService 创建目录变更记录服务实例
+func NewCatalogChangeService() *CatalogChangeService {
+	return &CatalogChangeService{
+		repo:      repository.NewCatalogChangeRepository(),
+		videoRepo: repository.NewVideoRepository(),
+		suggest:   NewSuggestService(),
+	}
+}
+
+// ListVideoChanges 分页查询视频（包括其剧集）的变更记录，最新的在前
+func (s *CatalogChangeService) ListVideoChanges(ctx context.Context, videoID int64, page, pageSize int) ([]*model.CatalogChange, int64, error) {
+	changes, total, err := s.repo.ListByVideoID(videoID, page, pageSize)
+	if err != nil {
+		logger.FromContext(ctx).Error("查询目录变更记录失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return nil, 0, errors.ErrCatalogChangeQueryFailed
+	}
+	return changes, total, nil
+}
+
+// RevertChange 回滚一条视频字段变更：将字段恢复为变更前的值，并记录一条新的变更（revert_of 指向被回滚的记录）
+// 字段在该变更之后又被修改过时拒绝回滚，避免覆盖更新的数据
+func (s *CatalogChangeService) RevertChange(ctx context.Context, changeID int64, actor string) (*model.CatalogChange, error) {
+	change, err := s.repo.FindByID(changeID)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrCatalogChangeNotFound
+		}
+		logger.FromContext(ctx).Error("查询目录变更记录失败", zap.Int64("change_id", changeID), zap.Error(err))
+		return nil, errors.ErrCatalogChangeQueryFailed
+	}
+	if change.EntityType != model.CatalogEntityVideo || change.Field == model.CatalogFieldCreated || change.Field == model.CatalogFieldDeleted {
+		return nil, errors.ErrCatalogChangeNotRevertible
+	}
+
+	value, err := decodeVideoFieldValue(change.Field, change.OldValue)
+	if err != nil {
+		logger.FromContext(ctx).Warn("无法回滚目录变更", zap.Int64("change_id", changeID), zap.String("field", change.Field), zap.Error(err))
+		return nil, errors.ErrCatalogChangeNotRevertible
+	}
+
+	video, err := s.videoRepo.FindByID(change.EntityID)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrVideoNotFound
+		}
+		logger.FromContext(ctx).Error("查询视频失败", zap.Int64("video_id", change.EntityID), zap.Error(err))
+		return nil, errors.ErrInternalError
+	}
+
+	current := encodeChangeValue(videoFieldMap(video)[change.Field])
+	if !changeValueEqual(current, change.NewValue) {
+		return nil, errors.ErrCatalogChangeConflict
+	}
+
+	revert := &model.CatalogChange{
+		EntityType: model.CatalogEntityVideo,
+		EntityID:   change.EntityID,
+		VideoID:    change.VideoID,
+		Field:      change.Field,
+		OldValue:   current,
+		NewValue:   change.OldValue,
+		Actor:      actor,
+		RevertOf:   &change.ID,
+	}
+	if err := s.repo.ApplyVideoRevert(revert, value); err != nil {
+		logger.FromContext(ctx).Error("回滚目录变更失败", zap.Int64("change_id", changeID), zap.Error(err))
+		return nil, errors.ErrCatalogChangeRevertFailed
+	}
+
+	logger.FromContext(ctx).Info("已回滚目录变更",
+		zap.Int64("change_id", changeID),
+		zap.Int64("video_id", change.EntityID),
+		zap.String("field", change.Field),
+		zap.String("actor", actor))
+
+	// 标题或状态可能变化，更新搜索建议索引
+	if s.suggest != nil {
+		if err := s.suggest.IndexVideoIDs([]int64{change.EntityID}); err != nil {
+			logger.FromContext(ctx).Warn("更新搜索建议索引失败", zap.Int64("video_id", change.EntityID), zap.Error(err))
+		}
+	}
+	return revert, nil
+}
+
+// decodeVideoFieldValue 将变更记录中的JSON值转换为写入videos列的值（nil表示NULL）
+// 只支持 videoFieldValues 中的字段
+func decodeVideoFieldValue(field string, raw datatypes.JSON) (interface{}, error) {
+	isNull := len(raw) == 0 || string(raw) == "null"
+	switch field {
+	case "title", "type", "cover_url", "cover_source_url", "description", "status", "imdb_id", "resolution":
+		var s string
+		if isNull {
+			return s, nil
+		}
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	case "release_date":
+		if isNull {
+			return nil, nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return time.Parse("2006-01-02", s)
+	case "score":
+		if isNull {
+			return nil, nil
+		}
+		var f float64
+		err := json.Unmarshal(raw, &f)
+		return f, err
+	case "runtime", "episode_count", "series_id", "season_number":
+		if isNull {
+			return nil, nil
+		}
+		var i int64
+		err := json.Unmarshal(raw, &i)
+		return i, err
+	case "is_completed", "is_update", "series_locked":
+		var b bool
+		if isNull {
+			return b, nil
+		}
+		err := json.Unmarshal(raw, &b)
+		return b, err
+	case "country_json", "director_json", "actors_json", "tags_json", "cover_thumbs_json":
+		if isNull {
+			return nil, nil
+		}
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("字段 %s 不支持回滚", field)
+	}
+}
@@ -0,0 +1,171 @@
+// service 包提供业务逻辑层
+// catalog_metrics_service.go 提供目录状态的Prometheus指标：后台定时执行分组计数查询并缓存结果，/metrics 抓取时只读取缓存
+package service
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// defaultCatalogMetricsInterval 目录统计的默认刷新间隔
+const defaultCatalogMetricsInterval = 5 * time.Minute
+
+// 目录状态指标
+var (
+	catalogVideosDesc = prometheus.NewDesc(
+		"catalog_videos",
+		"Number of videos by type, status and is_completed",
+		[]string{"type", "status", "is_completed"}, nil,
+	)
+	catalogVideosMissingDetailsDesc = prometheus.NewDesc(
+		"catalog_videos_missing_details",
+		"Number of videos still waiting for details by type",
+		[]string{"type"}, nil,
+	)
+	catalogVideosWithoutEpisodesDesc = prometheus.NewDesc(
+		"catalog_videos_without_episodes",
+		"Number of videos without any episode by type",
+		[]string{"type"}, nil,
+	)
+	catalogEpisodesDesc = prometheus.NewDesc(
+		"catalog_episodes",
+		"Number of episodes by channel",
+		[]string{"channel"}, nil,
+	)
+	catalogUnhealthyEpisodesDesc = prometheus.NewDesc(
+		"catalog_unhealthy_episodes",
+		"Number of episodes whose play URL is empty or not an http(s) URL by channel",
+		[]string{"channel"}, nil,
+	)
+	catalogRefreshTimestampDesc = prometheus.NewDesc(
+		"catalog_stats_last_refresh_timestamp_seconds",
+		"Unix timestamp of the last successful catalog stats refresh",
+		nil, nil,
+	)
+)
+
+// catalogStats 一次刷新得到的目录统计结果
+type catalogStats struct {
+	videos            []*repository.VideoStateCount
+	missingDetails    []*repository.TypeCount
+	withoutEpisodes   []*repository.TypeCount
+	episodes          []*repository.ChannelCount
+	unhealthyEpisodes []*repository.ChannelCount
+	refreshedAt       time.Time
+}
+
+// CatalogCollector 目录状态指标收集器（实现 prometheus.Collector）
+// 统计结果由后台定时刷新并缓存，抓取 /metrics 时不查询MySQL；刷新失败时保留上一次的结果
+type CatalogCollector struct {
+	repo     repository.CatalogStatsRepository
+	interval time.Duration
+
+	mu    sync.RWMutex
+	stats *catalogStats // 尚未成功刷新时为nil
+}
+
+// NewCatalogCollector 创建目录状态指标收集器，配置项 metrics.catalog_refresh_interval 指定刷新间隔（默认5分钟）
+func NewCatalogCollector() *CatalogCollector {
+	interval := config.Cfg.GetDuration("metrics.catalog_refresh_interval")
+	if interval <= 0 {
+		interval = defaultCatalogMetricsInterval
+	}
+	return &CatalogCollector{
+		repo:     repository.NewCatalogStatsRepository(),
+		interval: interval,
+	}
+}
+
+// StartCatalogCollector 创建并注册目录状态指标收集器，在后台立即刷新一次并按间隔定时刷新
+func StartCatalogCollector() *CatalogCollector {
+	c := NewCatalogCollector()
+	prometheus.MustRegister(c)
+	go func() {
+		c.Refresh()
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.Refresh()
+		}
+	}()
+	zap.L().Info("目录状态指标已启用", zap.Duration("interval", c.interval))
+	return c
+}
+
+// Refresh 重新查询目录统计并替换缓存，失败时只记录日志
+func (c *CatalogCollector) Refresh() {
+	start := time.Now()
+	stats := &catalogStats{}
+	var err error
+	if stats.videos, err = c.repo.CountVideosByState(); err != nil {
+		zap.L().Warn("统计视频状态失败", zap.Error(err))
+		return
+	}
+	if stats.missingDetails, err = c.repo.CountVideosMissingDetails(); err != nil {
+		zap.L().Warn("统计缺少详情的视频失败", zap.Error(err))
+		return
+	}
+	if stats.withoutEpisodes, err = c.repo.CountVideosWithoutEpisodes(); err != nil {
+		zap.L().Warn("统计没有剧集的视频失败", zap.Error(err))
+		return
+	}
+	if stats.episodes, err = c.repo.CountEpisodesByChannel(); err != nil {
+		zap.L().Warn("统计各频道剧集失败", zap.Error(err))
+		return
+	}
+	if stats.unhealthyEpisodes, err = c.repo.CountUnhealthyEpisodesByChannel(); err != nil {
+		zap.L().Warn("统计各频道不可用剧集失败", zap.Error(err))
+		return
+	}
+	stats.refreshedAt = time.Now()
+
+	c.mu.Lock()
+	c.stats = stats
+	c.mu.Unlock()
+	zap.L().Debug("目录统计已刷新", zap.Duration("elapsed", time.Since(start)))
+}
+
+// Describe 实现 prometheus.Collector
+func (c *CatalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- catalogVideosDesc
+	ch <- catalogVideosMissingDetailsDesc
+	ch <- catalogVideosWithoutEpisodesDesc
+	ch <- catalogEpisodesDesc
+	ch <- catalogUnhealthyEpisodesDesc
+	ch <- catalogRefreshTimestampDesc
+}
+
+// Collect 实现 prometheus.Collector，输出缓存的统计结果（尚未成功刷新时不输出）
+func (c *CatalogCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	stats := c.stats
+	c.mu.RUnlock()
+	if stats == nil {
+		return
+	}
+
+	for _, v := range stats.videos {
+		ch <- prometheus.MustNewConstMetric(catalogVideosDesc, prometheus.GaugeValue, float64(v.Count),
+			v.Type, v.Status, strconv.FormatBool(v.IsCompleted))
+	}
+	for _, t := range stats.missingDetails {
+		ch <- prometheus.MustNewConstMetric(catalogVideosMissingDetailsDesc, prometheus.GaugeValue, float64(t.Count), t.Type)
+	}
+	for _, t := range stats.withoutEpisodes {
+		ch <- prometheus.MustNewConstMetric(catalogVideosWithoutEpisodesDesc, prometheus.GaugeValue, float64(t.Count), t.Type)
+	}
+	for _, e := range stats.episodes {
+		ch <- prometheus.MustNewConstMetric(catalogEpisodesDesc, prometheus.GaugeValue, float64(e.Count), e.Channel)
+	}
+	for _, e := range stats.unhealthyEpisodes {
+		ch <- prometheus.MustNewConstMetric(catalogUnhealthyEpisodesDesc, prometheus.GaugeValue, float64(e.Count), e.Channel)
+	}
+	ch <- prometheus.MustNewConstMetric(catalogRefreshTimestampDesc, prometheus.GaugeValue, float64(stats.refreshedAt.Unix()))
+}
@@ -0,0 +1,224 @@
+// service 包提供业务逻辑层
+// cover_mirror_service.go 提供封面镜像：下载来源站点的封面图片（带正确的Referer），保存到对象存储并生成缩略图
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	_ "image/png" // 注册PNG解码器
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/blobstore"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/logger"
+	"video-service/pkg/infrastructure/metrics"
+
+	"go.uber.org/zap"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册WebP解码器
+)
+
+const (
+	// coverMaxSize 封面图片最大字节数
+	coverMaxSize = 10 << 20
+	// coverMaxFailures 连续失败达到该次数后不再重试（来源图片通常已失效）
+	coverMaxFailures = 3
+	// coverThumbQuality 缩略图JPEG质量
+	coverThumbQuality = 85
+)
+
+// defaultCoverThumbWidths 默认缩略图宽度
+var defaultCoverThumbWidths = []int{160, 320, 640}
+
+// CoverMirrorService 封面镜像服务
+type CoverMirrorService struct {
+	repo   repository.CoverRepository
+	store  blobstore.Store
+	client *http.Client
+	widths []int
+	audit  *catalogAuditor // 封面字段变更记录（同步中使用同步的记录器）
+}
+
+// NewCoverMirrorService 创建封面镜像服务实例，未配置对象存储（storage.driver）时返回nil
+// 配置项 covers.thumbnail_widths 指定缩略图宽度（默认 160/320/640）
+func NewCoverMirrorService() *CoverMirrorService {
+	if blobstore.Blob == nil {
+		return nil
+	}
+
+	widths := config.Cfg.GetIntSlice("covers.thumbnail_widths")
+	if len(widths) == 0 {
+		widths = defaultCoverThumbWidths
+	}
+	sort.Ints(widths)
+
+	return &CoverMirrorService{
+		repo:   repository.NewCoverRepository(),
+		store:  blobstore.Blob,
+		client: newSyncHTTPClient("cover", "image", 30*time.Second, nil),
+		widths: widths,
+		audit:  newCatalogAuditor(model.CatalogActorSystem, ""),
+	}
+}
+
+// MirrorPending 镜像尚未镜像的封面（新视频优先，每次最多limit个），返回成功和失败的数量
+func (s *CoverMirrorService) MirrorPending(ctx context.Context, limit int) (int, int, error) {
+	videos, err := s.repo.FindVideosNeedMirror(coverMaxFailures, limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("查询需要镜像封面的视频失败: %w", err)
+	}
+	if len(videos) == 0 {
+		logger.FromContext(ctx).Info("没有需要镜像的封面")
+		return 0, 0, nil
+	}
+
+	logger.FromContext(ctx).Info("找到需要镜像封面的视频", zap.Int("count", len(videos)))
+
+	mirrored, failed := 0, 0
+	for _, video := range videos {
+		if err := s.MirrorVideo(ctx, video); err != nil {
+			failed++
+			logger.FromContext(ctx).Warn("镜像封面失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+			metrics.SyncItemsTotal.WithLabelValues(syncStageCovers, video.Type, syncItemFailed).Inc()
+			if err := s.repo.IncrMirrorFailures(video.ID); err != nil {
+				logger.FromContext(ctx).Error("更新封面镜像失败次数失败", zap.Int64("video_id", video.ID), zap.Error(err))
+			}
+			continue
+		}
+		mirrored++
+		metrics.SyncItemsTotal.WithLabelValues(syncStageCovers, video.Type, syncItemUpdated).Inc()
+
+		// 避免请求过快
+		syncThrottle(500 * time.Millisecond)
+	}
+
+	logger.FromContext(ctx).Info("封面镜像完成", zap.Int("mirrored", mirrored), zap.Int("failed", failed))
+	return mirrored, failed, nil
+}
+
+// MirrorVideo 镜像单个视频的封面：保存原图和各尺寸缩略图，并将 cover_url 替换为自有地址
+func (s *CoverMirrorService) MirrorVideo(ctx context.Context, video *model.Video) error {
+	sourceURL := video.CoverSourceURL
+	if sourceURL == "" {
+		sourceURL = video.CoverURL
+	}
+
+	data, err := s.download(ctx, sourceURL)
+	if err != nil {
+		return err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	prefix := fmt.Sprintf("covers/%d", video.ID)
+
+	// 原图按原格式保存
+	originalKey := prefix + "/original." + format
+	if err := s.store.Put(ctx, originalKey, data, "image/"+format); err != nil {
+		return fmt.Errorf("保存原图失败: %w", err)
+	}
+
+	thumbs := make(map[string]string, len(s.widths))
+	for _, width := range s.widths {
+		thumb, err := resizeImage(img, width)
+		if err != nil {
+			return fmt.Errorf("生成缩略图失败: %w", err)
+		}
+		key := fmt.Sprintf("%s/w%d.jpg", prefix, width)
+		if err := s.store.Put(ctx, key, thumb, "image/jpeg"); err != nil {
+			return fmt.Errorf("保存缩略图失败: %w", err)
+		}
+		thumbs[fmt.Sprintf("w%d", width)] = s.store.URL(key)
+	}
+
+	thumbsJSON, err := json.Marshal(thumbs)
+	if err != nil {
+		return fmt.Errorf("序列化缩略图地址失败: %w", err)
+	}
+
+	coverURL := s.store.URL(originalKey)
+	if err := s.repo.SaveMirroredCover(video.ID, sourceURL, coverURL, thumbsJSON); err != nil {
+		return fmt.Errorf("保存镜像结果失败: %w", err)
+	}
+	before := cloneVideo(video)
+	video.CoverSourceURL = sourceURL
+	video.CoverURL = coverURL
+	video.CoverThumbsJSON = thumbsJSON
+	s.audit.recordVideoDiff(before, video)
+
+	logger.FromContext(ctx).Info("封面已镜像", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.String("cover_url", coverURL))
+	return nil
+}
+
+// download 下载封面图片
+// 豆瓣图片有防盗链，需要带上豆瓣的Referer
+func (s *CoverMirrorService) download(ctx context.Context, sourceURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("accept", "image/avif,image/webp,image/apng,image/*,*/*;q=0.8")
+	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+	if strings.Contains(req.URL.Host, "douban") {
+		req.Header.Set("referer", "https://movie.douban.com/")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("响应状态码异常: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, coverMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if len(data) > coverMaxSize {
+		return nil, fmt.Errorf("图片过大（超过%d字节）", coverMaxSize)
+	}
+	return data, nil
+}
+
+// resizeImage 按宽度等比缩放图片并编码为JPEG（原图宽度不足时不放大）
+func resizeImage(img image.Image, width int) ([]byte, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, fmt.Errorf("图片尺寸无效: %dx%d", bounds.Dx(), bounds.Dy())
+	}
+	if bounds.Dx() < width {
+		width = bounds.Dx()
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	// JPEG不支持透明通道，先铺白色背景
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: coverThumbQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"os"
+	"testing"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/database"
+
+	"github.com/spf13/viper"
+)
+
+// 回放 testdata/fixtures/sync 中的夹具离线执行完整的 SyncAll，需要一个可以清空的MySQL测试库：
+//
+//	TEST_MYSQL_DSN="root:root@tcp(127.0.0.1:3306)/video_test?charset=utf8mb4&parseTime=True&loc=Local" \
+//	  go test ./internal/service -run TestSyncAllReplay
+//
+// 测试开始时会删除该库中的所有表，请不要指向开发或生产库；未设置 TEST_MYSQL_DSN 时跳过
+//
+// 夹具包含：最新电影列表和电视列表各一部（动画、纪录片、综艺列表为空），两部的详情页，以及两部的播放地址搜索结果
+const replayFixturesDir = "testdata/fixtures/sync"
+
+// 夹具中的豆瓣ID
+const (
+	replayMovieSourceID = 35000001
+	replayTVSourceID    = 35000002
+)
+
+func TestSyncAllReplay(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("未设置 TEST_MYSQL_DSN，跳过回放同步测试")
+	}
+	setupReplayDatabase(t, dsn)
+
+	// 第一次同步：从空库开始，创建视频、补充详情和演职员、保存剧集并发布
+	svc := NewDoubanSyncService()
+	if err := svc.SyncAll(); err != nil {
+		t.Fatalf("SyncAll 失败: %v", err)
+	}
+	stats := svc.stats.snapshot()
+	want := syncRunStats{VideosCreated: 2, DetailsUpdated: 2, EpisodesAdded: 4, VideosPublished: 2}
+	if stats != want {
+		t.Errorf("同步统计 = %+v, 期望 %+v", stats, want)
+	}
+
+	movie := findReplayVideo(t, replayMovieSourceID)
+	if movie.Type != "movie" || movie.Title != "示例电影" {
+		t.Errorf("电影 type/title = %q/%q", movie.Type, movie.Title)
+	}
+	if movie.Status != "1" || !movie.IsCompleted {
+		t.Errorf("电影应已发布并完结: status=%q is_completed=%v", movie.Status, movie.IsCompleted)
+	}
+	if movie.IMDbID != "tt0000001" {
+		t.Errorf("电影 imdb_id = %q", movie.IMDbID)
+	}
+	if movie.Runtime == nil || *movie.Runtime != 118 {
+		t.Errorf("电影 runtime = %v", movie.Runtime)
+	}
+	if movie.ReleaseDate == nil || movie.ReleaseDate.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("电影 release_date = %v", movie.ReleaseDate)
+	}
+	if movie.Score == nil || *movie.Score != 8.1 {
+		t.Errorf("电影 score = %v", movie.Score)
+	}
+
+	tv := findReplayVideo(t, replayTVSourceID)
+	if tv.EpisodeCount == nil || *tv.EpisodeCount != 3 {
+		t.Errorf("剧集 episode_count = %v", tv.EpisodeCount)
+	}
+	if tv.Status != "1" || !tv.IsCompleted || !tv.IsUpdate {
+		t.Errorf("剧集应已发布、完结且有更新: status=%q is_completed=%v is_update=%v", tv.Status, tv.IsCompleted, tv.IsUpdate)
+	}
+
+	assertCount(t, "电影剧集数", &model.Episode{}, 1, "video_id = ?", movie.ID)
+	assertCount(t, "剧集剧集数", &model.Episode{}, 3, "video_id = ?", tv.ID)
+	// 电影：导演、编剧、两名主演；剧集：导演、两名主演（其中一名与电影相同）
+	assertCount(t, "电影演职员数", &model.VideoCredit{}, 4, "video_id = ?", movie.ID)
+	assertCount(t, "剧集演职员数", &model.VideoCredit{}, 3, "video_id = ?", tv.ID)
+	assertCount(t, "人物数", &model.Person{}, 6, "1 = 1")
+	// 电影的原名和又名
+	assertCount(t, "电影别名数", &model.VideoTitle{}, 2, "video_id = ?", movie.ID)
+
+	// 第二次同步：夹具不变，不应产生任何变更
+	again := NewDoubanSyncService()
+	if err := again.SyncAll(); err != nil {
+		t.Fatalf("第二次 SyncAll 失败: %v", err)
+	}
+	if stats := again.stats.snapshot(); stats.hasChanges() || stats.DetailsFailed != 0 {
+		t.Errorf("第二次同步不应有变更，统计 = %+v", stats)
+	}
+	assertCount(t, "视频数", &model.Video{}, 2, "1 = 1")
+	assertCount(t, "剧集总数", &model.Episode{}, 4, "1 = 1")
+}
+
+func TestSyncAllReplayArchive(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("未设置 TEST_MYSQL_DSN，跳过回放同步测试")
+	}
+	setupReplayDatabase(t, dsn)
+	config.Cfg.Set("archive.storage", "local")
+	config.Cfg.Set("archive.dir", t.TempDir())
+
+	if err := NewDoubanSyncService().SyncAll(); err != nil {
+		t.Fatalf("SyncAll 失败: %v", err)
+	}
+
+	// 两部视频各归档详情页、列表条目和匹配到的播放地址搜索响应
+	assertCount(t, "详情页归档数", &model.PageArchive{}, 2, "source = ? AND kind = ?", "douban", model.PageArchiveKindDetail)
+	assertCount(t, "列表条目归档数", &model.PageArchive{}, 2, "source = ? AND kind = ?", "douban", model.PageArchiveKindList)
+	assertCount(t, "搜索响应归档数", &model.PageArchive{}, 2, "source = ? AND kind = ?", "playurl", model.PageArchiveKindSearch)
+
+	// 重新解析只处理详情页归档
+	reparse, err := NewReparseService()
+	if err != nil {
+		t.Fatalf("创建重新解析服务失败: %v", err)
+	}
+	report, err := reparse.Run("")
+	if err != nil {
+		t.Fatalf("重新解析失败: %v", err)
+	}
+	if report.Scanned != 2 || report.Updated != 2 || report.Failed != 0 {
+		t.Errorf("重新解析报告 = %+v, 期望扫描并更新2个详情页", report)
+	}
+	assertCount(t, "剧集总数", &model.Episode{}, 4, "1 = 1")
+}
+
+// setupReplayDatabase 使用回放夹具的配置连接测试库，删除库中所有表后重新建表
+func setupReplayDatabase(t *testing.T, dsn string) {
+	t.Helper()
+	config.Cfg = viper.New()
+	config.Cfg.Set("mysql.dsn", dsn)
+	config.Cfg.Set("sync.http_fixtures.mode", "replay")
+	config.Cfg.Set("sync.http_fixtures.dir", replayFixturesDir)
+
+	database.InitMySQL()
+	if database.DB == nil {
+		t.Fatal("连接测试库失败")
+	}
+
+	var tables []string
+	if err := database.DB.Raw("SHOW TABLES").Scan(&tables).Error; err != nil {
+		t.Fatalf("查询测试库的表失败: %v", err)
+	}
+	for _, table := range tables {
+		if err := database.DB.Migrator().DropTable(table); err != nil {
+			t.Fatalf("删除表 %s 失败: %v", table, err)
+		}
+	}
+	// 重新建表（迁移在 InitMySQL 中执行）
+	database.InitMySQL()
+}
+
+// findReplayVideo 按豆瓣ID查询同步创建的视频
+func findReplayVideo(t *testing.T, sourceID int64) *model.Video {
+	t.Helper()
+	var video model.Video
+	if err := database.DB.Where("source = ? AND source_id = ?", "douban", sourceID).First(&video).Error; err != nil {
+		t.Fatalf("查询视频 source_id=%d 失败: %v", sourceID, err)
+	}
+	return &video
+}
+
+// assertCount 断言满足条件的记录数
+func assertCount(t *testing.T, name string, rowModel interface{}, want int64, query string, args ...interface{}) {
+	t.Helper()
+	var got int64
+	if err := database.DB.Model(rowModel).Where(query, args...).Count(&got).Error; err != nil {
+		t.Fatalf("统计%s失败: %v", name, err)
+	}
+	if got != want {
+		t.Errorf("%s = %d, 期望 %d", name, got, want)
+	}
+}
@@ -2,6 +2,7 @@
 package service
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -12,13 +13,19 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"video-service/internal/model"
+	"video-service/internal/pkg/dictionary"
 	"video-service/internal/pkg/utils"
 	"video-service/internal/repository"
-	"video-service/pkg/infrastructure/database"
+	"video-service/pkg/infrastructure/logger"
+	"video-service/pkg/infrastructure/tracing"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -54,128 +61,359 @@ type SearchResult struct {
 }
 
 // DoubanSyncService 豆瓣同步服务
+// 每个实例对应一次同步运行（run），拥有独立的运行ID
 type DoubanSyncService struct {
-	videoRepo   repository.VideoRepository
-	episodeRepo repository.EpisodeRepository
+	runID          string
+	ctx            context.Context // 同步运行的父上下文（用于追踪），默认为 context.Background()
+	log            *zap.Logger     // 运行级别的日志记录器（带 run_id，同步开始后带上 trace_id）
+	videoRepo      repository.VideoRepository
+	episodeRepo    repository.EpisodeRepository
+	redirects      repository.VideoRedirectRepository    // 已合并视频的重定向（只读，预演模式同样使用）
+	titleRepo      repository.VideoTitleRepository       // 视频别名（预演模式下只读）
+	releaseDates   repository.VideoReleaseDateRepository // 各地区上映日期（预演模式下不写入）
+	releaseRegions []string                              // 选取 videos.release_date 时的地区偏好（国家/地区规范代码）
+	dryRun         *dryRunRecorder                       // 非nil时为预演模式，所有写操作只记录不落库
+	webhooks       *WebhookService                       // 出站Webhook，预演模式下为nil
+	series         *SeriesService                        // 多季剧集的系列关联，预演模式下为nil
+	covers         *CoverMirrorService                   // 封面镜像，预演模式或未配置对象存储时为nil
+	people         *PeopleService                        // 演职员，预演模式下为nil
+	filters        *FilterService                        // 筛选项统计，预演模式下为nil
+	suggest        *SuggestService                       // 搜索建议索引，预演模式或未配置Redis时为nil
+	home           *HomeService                          // 首页推荐位缓存，预演模式下为nil
+	archive        *PageArchive                          // 原始页面归档，预演模式或未配置 archive.storage 时为nil
+	stats          syncRunStats
+}
+
+// syncRunStats 单次同步运行的统计数据（并发安全，使用atomic累加）
+type syncRunStats struct {
+	VideosCreated   int64 `json:"videos_created"`
+	DetailsUpdated  int64 `json:"details_updated"`
+	DetailsFailed   int64 `json:"details_failed"`
+	EpisodesAdded   int64 `json:"episodes_added"`
+	VideosPublished int64 `json:"videos_published"`
+	CoversMirrored  int64 `json:"covers_mirrored"`
+}
+
+// snapshot 返回统计数据的快照
+func (st *syncRunStats) snapshot() syncRunStats {
+	return syncRunStats{
+		VideosCreated:   atomic.LoadInt64(&st.VideosCreated),
+		DetailsUpdated:  atomic.LoadInt64(&st.DetailsUpdated),
+		DetailsFailed:   atomic.LoadInt64(&st.DetailsFailed),
+		EpisodesAdded:   atomic.LoadInt64(&st.EpisodesAdded),
+		VideosPublished: atomic.LoadInt64(&st.VideosPublished),
+		CoversMirrored:  atomic.LoadInt64(&st.CoversMirrored),
+	}
+}
+
+// hasChanges 是否有新建、更新或发布的视频，新增的剧集，或转存后改写了封面地址的视频
+func (st syncRunStats) hasChanges() bool {
+	return st.VideosCreated > 0 || st.DetailsUpdated > 0 || st.EpisodesAdded > 0 || st.VideosPublished > 0 ||
+		st.CoversMirrored > 0
 }
 
 // NewDoubanSyncService 创建豆瓣同步服务实例
 func NewDoubanSyncService() *DoubanSyncService {
+	runID := uuid.New().String()
+	// 同步写入的字段变更记录到 catalog_changes，操作者为sync并带上运行ID
+	audit := newCatalogAuditor(model.CatalogActorSync, runID)
+	covers := NewCoverMirrorService()
+	if covers != nil {
+		covers.audit = audit
+	}
+	series := NewSeriesService()
+	series.audit = audit
+	return &DoubanSyncService{
+		runID:          runID,
+		log:            zap.L().With(zap.String("run_id", runID)),
+		videoRepo:      &auditedVideoRepository{VideoRepository: repository.NewInvalidatingVideoRepository(repository.NewVideoRepository()), audit: audit},
+		episodeRepo:    &auditedEpisodeRepository{EpisodeRepository: repository.NewInvalidatingEpisodeRepository(repository.NewEpisodeRepository()), audit: audit},
+		redirects:      repository.NewVideoRedirectRepository(),
+		titleRepo:      repository.NewVideoTitleRepository(),
+		releaseDates:   repository.NewVideoReleaseDateRepository(),
+		releaseRegions: releaseRegionPreference(),
+		webhooks:       NewWebhookService(),
+		series:         series,
+		covers:         covers,
+		people:         NewPeopleService(),
+		filters:        NewFilterService(),
+		suggest:        NewSuggestService(),
+		home:           NewHomeService(),
+		archive:        NewPageArchive(),
+	}
+}
+
+// NewDoubanSyncDryRunService 创建预演模式（dry-run）的豆瓣同步服务实例
+// 预演模式会照常请求和解析所有数据，但 VideoRepository/EpisodeRepository 的写操作
+// 只会被记录下来，不会修改MySQL，执行结束后可通过 DryRunReport 获取变更预览
+func NewDoubanSyncDryRunService() *DoubanSyncService {
+	runID := uuid.New().String()
+	recorder := newDryRunRecorder(runID, repository.NewVideoRepository(), repository.NewEpisodeRepository())
 	return &DoubanSyncService{
-		videoRepo:   repository.NewVideoRepository(),
-		episodeRepo: repository.NewEpisodeRepository(),
+		runID:          runID,
+		log:            zap.L().With(zap.String("run_id", runID)),
+		videoRepo:      &dryRunVideoRepository{rec: recorder},
+		episodeRepo:    &dryRunEpisodeRepository{rec: recorder},
+		redirects:      repository.NewVideoRedirectRepository(),
+		titleRepo:      repository.NewVideoTitleRepository(),
+		releaseDates:   repository.NewVideoReleaseDateRepository(),
+		releaseRegions: releaseRegionPreference(),
+		dryRun:         recorder,
+	}
+}
+
+// RunID 返回本次同步运行的ID
+func (s *DoubanSyncService) RunID() string {
+	return s.runID
+}
+
+// IsDryRun 是否为预演模式
+func (s *DoubanSyncService) IsDryRun() bool {
+	return s.dryRun != nil
+}
+
+// DryRunReport 返回预演模式下记录的变更预览报告，非预演模式返回nil
+func (s *DoubanSyncService) DryRunReport() *DryRunReport {
+	if s.dryRun == nil {
+		return nil
 	}
+	return s.dryRun.Report()
+}
+
+// WithContext 设置同步运行的父上下文并返回自身，同步的span会挂在该上下文的span下
+// 上下文只用于追踪，同步在后台执行时应使用 tracing.Detach 去掉请求的取消
+func (s *DoubanSyncService) WithContext(ctx context.Context) *DoubanSyncService {
+	s.ctx = ctx
+	return s
 }
 
 // SyncAll 同步所有豆瓣数据
 func (s *DoubanSyncService) SyncAll() error {
-	zap.L().Info("开始同步豆瓣数据")
+	s.log.Info("开始同步豆瓣数据", zap.String("run_id", s.runID), zap.Bool("dry_run", s.IsDryRun()))
+	startedAt := time.Now()
+
+	parent := s.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := tracing.Tracer().Start(parent, "sync.run", trace.WithAttributes(
+		attribute.String("sync.run_id", s.runID),
+		attribute.Bool("sync.dry_run", s.IsDryRun()),
+	))
+	defer span.End()
+
+	// 之后的日志都带上 trace_id，按阶段、视频和worker记录的日志通过上下文中的子记录器带上对应字段
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		s.log = s.log.With(zap.String("trace_id", sc.TraceID().String()))
+	}
+	ctx = logger.WithContext(ctx, s.log)
 
 	// 第一步：获取最新列表并保存基本信息
-	if err := s.fetchAndSaveAllLists(); err != nil {
-		zap.L().Error("获取列表失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStageList, "", s.fetchAndSaveAllLists); err != nil {
+		s.log.Error("获取列表失败", zap.Error(err))
+		tracing.RecordError(span, err)
+		s.publishSyncFinished(ctx, startedAt, err)
 		return err
 	}
 
 	// 第二步：更新详细信息
 	// a. 更新电影详细信息
-	if err := s.fetchAndUpdateMovieDetails(); err != nil {
-		zap.L().Error("更新电影详情失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStageDetail, "movie", s.fetchAndUpdateMovieDetails); err != nil {
+		s.log.Error("更新电影详情失败", zap.Error(err))
 	}
 
 	// b. 更新电视详细信息
-	if err := s.fetchAndUpdateTVDetails(); err != nil {
-		zap.L().Error("更新电视详情失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStageDetail, "tv", s.fetchAndUpdateTVDetails); err != nil {
+		s.log.Error("更新电视详情失败", zap.Error(err))
 	}
 
 	// c. 更新动漫详细信息（b执行完才能执行）
-	if err := s.fetchAndUpdateAnimeDetails(); err != nil {
-		zap.L().Error("更新动漫详情失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStageDetail, "anime", s.fetchAndUpdateAnimeDetails); err != nil {
+		s.log.Error("更新动漫详情失败", zap.Error(err))
 	}
 
 	// d. 更新综艺详细信息（b执行完才能执行）
-	if err := s.fetchAndUpdateShowDetails(); err != nil {
-		zap.L().Error("更新综艺详情失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStageDetail, "tvshow", s.fetchAndUpdateShowDetails); err != nil {
+		s.log.Error("更新综艺详情失败", zap.Error(err))
 	}
 
 	// e. 更新纪录片详细信息（b执行完才能执行）
-	if err := s.fetchAndUpdateDocDetails(); err != nil {
-		zap.L().Error("更新纪录片详情失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStageDetail, "doc", s.fetchAndUpdateDocDetails); err != nil {
+		s.log.Error("更新纪录片详情失败", zap.Error(err))
+	}
+
+	// f. 为旧数据补充演职员（预演模式下跳过）
+	if err := s.runStage(ctx, syncStageCredits, "", func(ctx context.Context) error { return s.backfillCredits(ctx, 50) }); err != nil {
+		s.log.Error("补充演职员失败", zap.Error(err))
 	}
 
 	// 第三步：搜索播放地址并插入episodes表
-	if err := s.searchAndSavePlayURLs(); err != nil {
-		zap.L().Error("搜索播放地址失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStagePlayURL, "", s.searchAndSavePlayURLs); err != nil {
+		s.log.Error("搜索播放地址失败", zap.Error(err))
 	}
 
 	// 第四步：更新存在 episodes 记录的 videos 的 status 为 1
-	if err := s.updateVideosStatusByEpisodes(); err != nil {
-		zap.L().Error("更新视频状态失败", zap.Error(err))
+	if err := s.runStage(ctx, syncStagePublish, "", s.updateVideosStatusByEpisodes); err != nil {
+		s.log.Error("更新视频状态失败", zap.Error(err))
+	}
+
+	// 第五步：镜像封面图片到对象存储（预演模式或未配置对象存储时跳过）
+	if s.covers != nil {
+		if err := s.runStage(ctx, syncStageCovers, "", func(ctx context.Context) error {
+			mirrored, _, err := s.covers.MirrorPending(ctx, 200)
+			atomic.AddInt64(&s.stats.CoversMirrored, int64(mirrored))
+			return err
+		}); err != nil {
+			s.log.Error("镜像封面失败", zap.Error(err))
+		}
+	}
+
+	// 第六步：根据已发布视频重新统计筛选项（预演模式下跳过）
+	_ = s.runStage(ctx, syncStageFilters, "", func(ctx context.Context) error {
+		s.refreshFilters(ctx)
+		return nil
+	})
+
+	// 第七步：更新本次同步中变化的视频的搜索建议索引（预演模式或未配置Redis时跳过）
+	if err := s.runStage(ctx, syncStageSuggest, "", func(context.Context) error { return s.refreshSuggestions(startedAt) }); err != nil {
+		s.log.Error("更新搜索建议索引失败", zap.Error(err))
 	}
 
-	zap.L().Info("豆瓣数据同步完成")
+	// 第八步：本次同步有变更时清除首页推荐位缓存（预演模式下跳过）
+	if err := s.runStage(ctx, syncStageHome, "", s.invalidateHome); err != nil {
+		s.log.Error("清除首页推荐位缓存失败", zap.Error(err))
+	}
+
+	s.markSyncSucceeded()
+	s.publishSyncFinished(ctx, startedAt, nil)
+	s.log.Info("豆瓣数据同步完成", zap.String("run_id", s.runID), zap.Any("stats", s.stats.snapshot()))
 	return nil
 }
 
+// publish 发布Webhook事件（预演模式下不发布）
+func (s *DoubanSyncService) publish(ctx context.Context, event string, data interface{}) {
+	if s.webhooks == nil {
+		return
+	}
+	s.webhooks.Publish(ctx, event, data)
+}
+
+// linkSeries 根据标题中的季数标记自动关联系列（预演模式下不处理），失败只记录日志
+func (s *DoubanSyncService) linkSeries(ctx context.Context, video *model.Video) {
+	if s.series == nil {
+		return
+	}
+	if _, err := s.series.AutoLink(ctx, video); err != nil {
+		logger.FromContext(ctx).Warn("自动关联系列失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+	}
+}
+
+// publishSyncFinished 发布同步运行结束事件
+func (s *DoubanSyncService) publishSyncFinished(ctx context.Context, startedAt time.Time, err error) {
+	finishedAt := time.Now()
+	data := map[string]interface{}{
+		"run_id":      s.runID,
+		"status":      "success",
+		"started_at":  startedAt,
+		"finished_at": finishedAt,
+		"duration_ms": finishedAt.Sub(startedAt).Milliseconds(),
+		"stats":       s.stats.snapshot(),
+	}
+	if err != nil {
+		data["status"] = "failed"
+		data["error"] = err.Error()
+	}
+	s.publish(ctx, EventSyncFinished, data)
+}
+
+// videoEventData 构建视频相关事件的数据
+func (s *DoubanSyncService) videoEventData(video *model.Video) map[string]interface{} {
+	return map[string]interface{}{
+		"run_id":    s.runID,
+		"video_id":  video.ID,
+		"source":    video.Source,
+		"source_id": video.SourceID,
+		"title":     video.Title,
+		"type":      video.Type,
+	}
+}
+
+// publishEpisodesAdded 发布视频新增剧集事件
+func (s *DoubanSyncService) publishEpisodesAdded(ctx context.Context, video *model.Video, episodeNumbers []int64) {
+	atomic.AddInt64(&s.stats.EpisodesAdded, int64(len(episodeNumbers)))
+	s.observeItems(syncStagePlayURL, video.Type, syncItemSaved, len(episodeNumbers))
+	data := s.videoEventData(video)
+	data["count"] = len(episodeNumbers)
+	data["episode_numbers"] = episodeNumbers
+	s.publish(ctx, EventEpisodesAdded, data)
+}
+
+// publishVideoPublished 发布视频发布事件（status变为1）
+func (s *DoubanSyncService) publishVideoPublished(ctx context.Context, video *model.Video) {
+	atomic.AddInt64(&s.stats.VideosPublished, 1)
+	s.observeItem(syncStagePublish, video.Type, syncItemUpdated)
+	s.publish(ctx, EventVideoPublished, s.videoEventData(video))
+}
+
 // fetchAndSaveAllLists 获取并保存所有列表
-func (s *DoubanSyncService) fetchAndSaveAllLists() error {
+func (s *DoubanSyncService) fetchAndSaveAllLists(ctx context.Context) error {
 	// 1. 最新电影列表
-	if err := s.fetchAndSaveList(
+	if err := s.fetchAndSaveList(ctx,
 		"https://m.douban.com/rexxar/api/v2/subject/recent_hot/movie?start=0&limit=100&category=%E6%9C%80%E6%96%B0&type=%E5%85%A8%E9%83%A8",
 		"https://movie.douban.com/explore",
 		"movie",
 		"", // 使用items.type
 	); err != nil {
-		zap.L().Error("获取电影列表失败", zap.Error(err))
+		logger.FromContext(ctx).Error("获取电影列表失败", zap.Error(err))
 	}
 
 	// 2. 最新电视列表
-	if err := s.fetchAndSaveList(
+	if err := s.fetchAndSaveList(ctx,
 		"https://m.douban.com/rexxar/api/v2/subject/recent_hot/tv?start=0&limit=100&category=tv&type=tv",
 		"https://movie.douban.com/tv/",
 		"tv",
 		"", // 使用items.type
 	); err != nil {
-		zap.L().Error("获取电视列表失败", zap.Error(err))
+		logger.FromContext(ctx).Error("获取电视列表失败", zap.Error(err))
 	}
 
 	// 3. 动画列表
-	if err := s.fetchAndSaveList(
+	if err := s.fetchAndSaveList(ctx,
 		"https://m.douban.com/rexxar/api/v2/subject/recent_hot/tv?start=0&limit=200&category=tv&type=tv_animation",
 		"https://movie.douban.com/tv/",
 		"anime",
 		"anime", // 固定为anime
 	); err != nil {
-		zap.L().Error("获取动画列表失败", zap.Error(err))
+		logger.FromContext(ctx).Error("获取动画列表失败", zap.Error(err))
 	}
 
 	// 4. 纪录片列表
-	if err := s.fetchAndSaveList(
+	if err := s.fetchAndSaveList(ctx,
 		"https://m.douban.com/rexxar/api/v2/subject/recent_hot/tv?start=0&limit=200&category=tv&type=tv_documentary",
 		"https://movie.douban.com/tv/",
 		"doc",
 		"doc", // 固定为doc，便于第二步区分
 	); err != nil {
-		zap.L().Error("获取纪录片列表失败", zap.Error(err))
+		logger.FromContext(ctx).Error("获取纪录片列表失败", zap.Error(err))
 	}
 
 	// 5. 综艺列表
-	if err := s.fetchAndSaveList(
+	if err := s.fetchAndSaveList(ctx,
 		"https://m.douban.com/rexxar/api/v2/subject/recent_hot/tv?start=0&limit=200&category=show&type=show",
 		"https://movie.douban.com/tv/",
 		"tvshow",
 		"tvshow", // 固定为tvshow
 	); err != nil {
-		zap.L().Error("获取综艺列表失败", zap.Error(err))
+		logger.FromContext(ctx).Error("获取综艺列表失败", zap.Error(err))
 	}
 
 	return nil
 }
 
 // fetchAndSaveList 获取并保存单个列表
-func (s *DoubanSyncService) fetchAndSaveList(url, referer, defaultType, fixedType string) error {
+func (s *DoubanSyncService) fetchAndSaveList(ctx context.Context, url, referer, defaultType, fixedType string) error {
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -198,7 +436,7 @@ func (s *DoubanSyncService) fetchAndSaveList(url, referer, defaultType, fixedTyp
 	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 
 	// 发送请求
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newSyncHTTPClient("douban", "list", 30*time.Second, nil)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("请求失败: %w", err)
@@ -216,8 +454,9 @@ func (s *DoubanSyncService) fetchAndSaveList(url, referer, defaultType, fixedTyp
 	if err := json.Unmarshal(body, &listResponse); err != nil {
 		return fmt.Errorf("解析JSON失败: %w", err)
 	}
+	s.archiveListItems(ctx, body)
 
-	zap.L().Info("获取到列表", zap.String("type", defaultType), zap.Int("count", len(listResponse.Items)))
+	logger.FromContext(ctx).Info("获取到列表", zap.String("type", defaultType), zap.Int("count", len(listResponse.Items)))
 
 	// 遍历列表，保存不存在的项
 	savedCount := 0
@@ -225,7 +464,8 @@ func (s *DoubanSyncService) fetchAndSaveList(url, referer, defaultType, fixedTyp
 		// 将字符串ID转换为整数
 		sourceIDInt, err := strconv.Atoi(item.ID)
 		if err != nil {
-			zap.L().Warn("无效的ID", zap.String("id", item.ID))
+			logger.FromContext(ctx).Warn("无效的ID", zap.String("id", item.ID))
+			s.observeItem(syncStageList, defaultType, syncItemFailed)
 			continue
 		}
 		sourceID := int64(sourceIDInt)
@@ -234,10 +474,24 @@ func (s *DoubanSyncService) fetchAndSaveList(url, referer, defaultType, fixedTyp
 		_, err = s.videoRepo.FindBySourceID(sourceID)
 		if err == nil {
 			// 已存在，跳过
+			s.observeItem(syncStageList, defaultType, syncItemSkipped)
 			continue
 		}
 		if err != gorm.ErrRecordNotFound {
-			zap.L().Error("查询数据库失败", zap.Error(err))
+			logger.FromContext(ctx).Error("查询数据库失败", zap.Error(err))
+			s.observeItem(syncStageList, defaultType, syncItemFailed)
+			continue
+		}
+
+		// 已被去重合并到其他视频的来源ID，不再重新创建
+		merged, err := s.redirects.ExistsBySourceID("douban", sourceID)
+		if err != nil {
+			logger.FromContext(ctx).Error("查询视频重定向失败", zap.Error(err))
+			s.observeItem(syncStageList, defaultType, syncItemFailed)
+			continue
+		}
+		if merged {
+			s.observeItem(syncStageList, defaultType, syncItemSkipped)
 			continue
 		}
 
@@ -261,20 +515,28 @@ func (s *DoubanSyncService) fetchAndSaveList(url, referer, defaultType, fixedTyp
 		}
 
 		if err := s.videoRepo.Create(video); err != nil {
-			zap.L().Error("保存视频失败", zap.Error(err), zap.String("title", item.Title))
+			logger.FromContext(ctx).Error("保存视频失败", zap.Error(err), zap.String("title", item.Title))
+			s.observeItem(syncStageList, defaultType, syncItemFailed)
 			continue
 		}
 
 		savedCount++
-		zap.L().Info("保存新视频", zap.String("title", item.Title), zap.Int64("source_id", sourceID), zap.String("type", videoType))
+		s.observeItem(syncStageList, defaultType, syncItemSaved)
+		atomic.AddInt64(&s.stats.VideosCreated, 1)
+		s.linkSeries(ctx, video)
+		data := s.videoEventData(video)
+		data["cover_url"] = video.CoverURL
+		data["score"] = video.Score
+		s.publish(ctx, EventVideoCreated, data)
+		logger.FromContext(ctx).Info("保存新视频", zap.String("title", item.Title), zap.Int64("source_id", sourceID), zap.String("type", videoType))
 	}
 
-	zap.L().Info("列表同步完成", zap.String("type", defaultType), zap.Int("saved_count", savedCount))
+	logger.FromContext(ctx).Info("列表同步完成", zap.String("type", defaultType), zap.Int("saved_count", savedCount))
 	return nil
 }
 
 // fetchAndUpdateMovieDetails 获取并更新电影详情
-func (s *DoubanSyncService) fetchAndUpdateMovieDetails() error {
+func (s *DoubanSyncService) fetchAndUpdateMovieDetails(ctx context.Context) error {
 	// 查找需要补充详情的电影（每次处理100条）
 	videos, err := s.videoRepo.FindNeedDetailVideosByType("movie", 100)
 	if err != nil {
@@ -282,28 +544,32 @@ func (s *DoubanSyncService) fetchAndUpdateMovieDetails() error {
 	}
 
 	if len(videos) == 0 {
-		zap.L().Info("没有需要更新详情的电影")
+		logger.FromContext(ctx).Info("没有需要更新详情的电影")
 		return nil
 	}
 
-	zap.L().Info("找到需要更新详情的电影", zap.Int("count", len(videos)))
+	logger.FromContext(ctx).Info("找到需要更新详情的电影", zap.Int("count", len(videos)))
 
 	// 遍历每个视频，获取详情
 	for _, video := range videos {
-		if err := s.fetchAndUpdateSingleMovieDetail(video); err != nil {
-			zap.L().Error("更新电影详情失败", zap.Error(err), zap.String("title", video.Title))
+		if err := s.traceVideo(ctx, "sync.detail", video, s.fetchAndUpdateSingleMovieDetail); err != nil {
+			atomic.AddInt64(&s.stats.DetailsFailed, 1)
+			s.observeItem(syncStageDetail, video.Type, syncItemFailed)
+			logger.FromContext(ctx).Error("更新电影详情失败", zap.Error(err), zap.String("title", video.Title))
 			continue
 		}
+		atomic.AddInt64(&s.stats.DetailsUpdated, 1)
+		s.observeItem(syncStageDetail, video.Type, syncItemUpdated)
 
 		// 避免请求过快，休眠4秒
-		time.Sleep(4 * time.Second)
+		syncThrottle(4 * time.Second)
 	}
 
 	return nil
 }
 
 // fetchAndUpdateTVDetails 获取并更新电视详情
-func (s *DoubanSyncService) fetchAndUpdateTVDetails() error {
+func (s *DoubanSyncService) fetchAndUpdateTVDetails(ctx context.Context) error {
 	// 查找需要补充详情的电视（每次处理100条）
 	videos, err := s.videoRepo.FindNeedDetailVideosByType("tv", 100)
 	if err != nil {
@@ -311,28 +577,32 @@ func (s *DoubanSyncService) fetchAndUpdateTVDetails() error {
 	}
 
 	if len(videos) == 0 {
-		zap.L().Info("没有需要更新详情的电视")
+		logger.FromContext(ctx).Info("没有需要更新详情的电视")
 		return nil
 	}
 
-	zap.L().Info("找到需要更新详情的电视", zap.Int("count", len(videos)))
+	logger.FromContext(ctx).Info("找到需要更新详情的电视", zap.Int("count", len(videos)))
 
 	// 遍历每个视频，获取详情
 	for _, video := range videos {
-		if err := s.fetchAndUpdateSingleTVDetail(video); err != nil {
-			zap.L().Error("更新电视详情失败", zap.Error(err), zap.String("title", video.Title))
+		if err := s.traceVideo(ctx, "sync.detail", video, s.fetchAndUpdateSingleTVDetail); err != nil {
+			atomic.AddInt64(&s.stats.DetailsFailed, 1)
+			s.observeItem(syncStageDetail, video.Type, syncItemFailed)
+			logger.FromContext(ctx).Error("更新电视详情失败", zap.Error(err), zap.String("title", video.Title))
 			continue
 		}
+		atomic.AddInt64(&s.stats.DetailsUpdated, 1)
+		s.observeItem(syncStageDetail, video.Type, syncItemUpdated)
 
 		// 避免请求过快，休眠4秒
-		time.Sleep(4 * time.Second)
+		syncThrottle(4 * time.Second)
 	}
 
 	return nil
 }
 
 // fetchAndUpdateAnimeDetails 获取并更新动漫详情
-func (s *DoubanSyncService) fetchAndUpdateAnimeDetails() error {
+func (s *DoubanSyncService) fetchAndUpdateAnimeDetails(ctx context.Context) error {
 	// 查找需要补充详情的动漫（type=anime且release_date和country_json都为空，每次处理100条）
 	// 注意：第一步调用3时已经保存为type=anime，所以这里从anime查找
 	videos, err := s.videoRepo.FindNeedDetailVideosByType("anime", 100)
@@ -341,29 +611,33 @@ func (s *DoubanSyncService) fetchAndUpdateAnimeDetails() error {
 	}
 
 	if len(videos) == 0 {
-		zap.L().Info("没有需要更新详情的动漫")
+		logger.FromContext(ctx).Info("没有需要更新详情的动漫")
 		return nil
 	}
 
-	zap.L().Info("找到需要更新详情的动漫", zap.Int("count", len(videos)))
+	logger.FromContext(ctx).Info("找到需要更新详情的动漫", zap.Int("count", len(videos)))
 
 	// 遍历每个视频，获取详情
 	for _, video := range videos {
-		if err := s.fetchAndUpdateSingleTVDetail(video); err != nil {
-			zap.L().Error("更新动漫详情失败", zap.Error(err), zap.String("title", video.Title))
+		if err := s.traceVideo(ctx, "sync.detail", video, s.fetchAndUpdateSingleTVDetail); err != nil {
+			atomic.AddInt64(&s.stats.DetailsFailed, 1)
+			s.observeItem(syncStageDetail, video.Type, syncItemFailed)
+			logger.FromContext(ctx).Error("更新动漫详情失败", zap.Error(err), zap.String("title", video.Title))
 			continue
 		}
+		atomic.AddInt64(&s.stats.DetailsUpdated, 1)
+		s.observeItem(syncStageDetail, video.Type, syncItemUpdated)
 
 		// fetchAndUpdateSingleTVDetail 内部已经更新了数据库，这里不需要再次更新
 		// 避免请求过快，休眠4秒
-		time.Sleep(4 * time.Second)
+		syncThrottle(4 * time.Second)
 	}
 
 	return nil
 }
 
 // fetchAndUpdateShowDetails 获取并更新综艺详情
-func (s *DoubanSyncService) fetchAndUpdateShowDetails() error {
+func (s *DoubanSyncService) fetchAndUpdateShowDetails(ctx context.Context) error {
 	// 查找需要补充详情的综艺（type=tvshow且release_date和country_json都为空，每次处理100条）
 	// 注意：第一步调用5时已经保存为type=tvshow，所以这里从tvshow查找
 	videos, err := s.videoRepo.FindNeedDetailVideosByType("tvshow", 100)
@@ -372,29 +646,33 @@ func (s *DoubanSyncService) fetchAndUpdateShowDetails() error {
 	}
 
 	if len(videos) == 0 {
-		zap.L().Info("没有需要更新详情的综艺")
+		logger.FromContext(ctx).Info("没有需要更新详情的综艺")
 		return nil
 	}
 
-	zap.L().Info("找到需要更新详情的综艺", zap.Int("count", len(videos)))
+	logger.FromContext(ctx).Info("找到需要更新详情的综艺", zap.Int("count", len(videos)))
 
 	// 遍历每个视频，获取详情
 	for _, video := range videos {
-		if err := s.fetchAndUpdateSingleShowDetail(video); err != nil {
-			zap.L().Error("更新综艺详情失败", zap.Error(err), zap.String("title", video.Title))
+		if err := s.traceVideo(ctx, "sync.detail", video, s.fetchAndUpdateSingleShowDetail); err != nil {
+			atomic.AddInt64(&s.stats.DetailsFailed, 1)
+			s.observeItem(syncStageDetail, video.Type, syncItemFailed)
+			logger.FromContext(ctx).Error("更新综艺详情失败", zap.Error(err), zap.String("title", video.Title))
 			continue
 		}
+		atomic.AddInt64(&s.stats.DetailsUpdated, 1)
+		s.observeItem(syncStageDetail, video.Type, syncItemUpdated)
 
 		// fetchAndUpdateSingleShowDetail 内部已经更新了数据库，这里不需要再次更新
 		// 避免请求过快，休眠4秒
-		time.Sleep(4 * time.Second)
+		syncThrottle(4 * time.Second)
 	}
 
 	return nil
 }
 
 // fetchAndUpdateDocDetails 获取并更新纪录片详情
-func (s *DoubanSyncService) fetchAndUpdateDocDetails() error {
+func (s *DoubanSyncService) fetchAndUpdateDocDetails(ctx context.Context) error {
 	// 查找需要补充详情的纪录片（type=doc且release_date和country_json都为空，每次处理100条）
 	// 注意：第一步调用4时已经保存为type=doc，所以这里从doc查找
 	videos, err := s.videoRepo.FindNeedDetailVideosByType("doc", 100)
@@ -403,39 +681,43 @@ func (s *DoubanSyncService) fetchAndUpdateDocDetails() error {
 	}
 
 	if len(videos) == 0 {
-		zap.L().Info("没有需要更新详情的纪录片")
+		logger.FromContext(ctx).Info("没有需要更新详情的纪录片")
 		return nil
 	}
 
-	zap.L().Info("找到需要更新详情的纪录片", zap.Int("count", len(videos)))
+	logger.FromContext(ctx).Info("找到需要更新详情的纪录片", zap.Int("count", len(videos)))
 
 	// 遍历每个视频，获取详情
 	for _, video := range videos {
-		if err := s.fetchAndUpdateSingleDocDetail(video); err != nil {
-			zap.L().Error("更新纪录片详情失败", zap.Error(err), zap.String("title", video.Title))
+		if err := s.traceVideo(ctx, "sync.detail", video, s.fetchAndUpdateSingleDocDetail); err != nil {
+			atomic.AddInt64(&s.stats.DetailsFailed, 1)
+			s.observeItem(syncStageDetail, video.Type, syncItemFailed)
+			logger.FromContext(ctx).Error("更新纪录片详情失败", zap.Error(err), zap.String("title", video.Title))
 			continue
 		}
+		atomic.AddInt64(&s.stats.DetailsUpdated, 1)
+		s.observeItem(syncStageDetail, video.Type, syncItemUpdated)
 
 		// fetchAndUpdateSingleDocDetail 内部已经更新了数据库，这里不需要再次更新
 		// 避免请求过快，休眠4秒
-		time.Sleep(4 * time.Second)
+		syncThrottle(4 * time.Second)
 	}
 
 	return nil
 }
 
 // fetchAndUpdateSingleMovieDetail 获取并更新单个电影详情
-func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video) error {
+func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(ctx context.Context, video *model.Video) error {
 	// 检查 SourceID 是否存在
 	if video.SourceID == nil || *video.SourceID == 0 {
 		return fmt.Errorf("视频的 SourceID 为空或无效: title=%s, id=%d", video.Title, video.ID)
 	}
 
 	url := fmt.Sprintf("https://movie.douban.com/subject/%d/", *video.SourceID)
-	zap.L().Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
+	logger.FromContext(ctx).Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -459,7 +741,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video)
 	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 
 	// 发送请求
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newSyncHTTPClient("douban", "detail", 30*time.Second, nil)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("请求失败: %w", err)
@@ -468,7 +750,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video)
 
 	// 检查响应状态码
 	if resp.StatusCode != http.StatusOK {
-		zap.L().Warn("HTTP请求返回非200状态码", zap.String("title", video.Title), zap.Int("status_code", resp.StatusCode), zap.String("url", url))
+		logger.FromContext(ctx).Warn("HTTP请求返回非200状态码", zap.String("title", video.Title), zap.Int("status_code", resp.StatusCode), zap.String("url", url))
 	}
 
 	// 读取响应
@@ -477,26 +759,34 @@ func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video)
 		return fmt.Errorf("读取响应失败: %w", err)
 	}
 
+	// 归档原始页面，供解析逻辑修复后离线重新解析
+	s.archiveDetailPage(*video.SourceID, resp.StatusCode, body)
+
 	html := string(body)
 
 	// 检查HTML是否包含关键内容
 	if len(html) < 1000 {
-		zap.L().Warn("HTML内容过短，可能请求失败", zap.String("title", video.Title), zap.Int("html_length", len(html)), zap.String("url", url))
+		logger.FromContext(ctx).Warn("HTML内容过短，可能请求失败", zap.String("title", video.Title), zap.Int("html_length", len(html)), zap.String("url", url))
 	}
 
 	// 检查HTML是否包含关键标识
 	if !strings.Contains(html, "导演") && !strings.Contains(html, "主演") {
-		zap.L().Warn("HTML中未找到关键字段，可能页面结构变化", zap.String("title", video.Title), zap.String("url", url))
+		logger.FromContext(ctx).Warn("HTML中未找到关键字段，可能页面结构变化", zap.String("title", video.Title), zap.String("url", url))
 	}
 
+	return s.applyMovieDetail(ctx, video, html)
+}
+
+// applyMovieDetail 解析电影详情页并更新视频（同步和离线重新解析共用）
+func (s *DoubanSyncService) applyMovieDetail(ctx context.Context, video *model.Video, html string) error {
 	// 解析HTML，提取信息
 	directorStr := extractFieldWithAttrs(html, "导演")
 	actorsStr := extractFieldWithAttrs(html, "主演")
-	tagsStr := extractGenres(html)
+	tagsStr := extractGenres(ctx, html)
 	countryStr := extractField(html, `<span class="pl">制片国家/地区:</span>`, `<br`)
 
 	// 添加调试日志，查看提取到的原始字符串
-	zap.L().Info("提取到的原始字段",
+	logger.FromContext(ctx).Info("提取到的原始字段",
 		zap.String("title", video.Title),
 		zap.String("director_str", directorStr),
 		zap.String("actors_str", actorsStr),
@@ -522,7 +812,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video)
 	} else {
 		video.TagsJSON = []byte("[]")
 	}
-	if countryJSON, err := stringToCountryJSONArray(countryStr); err == nil {
+	if countryJSON, err := stringToCountryJSONArray(ctx, countryStr); err == nil {
 		video.CountryJSON = truncateJSONArray(countryJSON)
 	} else {
 		video.CountryJSON = []byte("[]")
@@ -533,9 +823,9 @@ func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video)
 		video.Score = score
 	}
 
-	// 提取上映日期（完整日期）
+	// 提取上映日期（页面中列出多个地区时按地区偏好选取）
 	dateStr := extractField(html, `<span class="pl">上映日期:</span>`, `<br`)
-	video.ReleaseDate = parseDateString(dateStr)
+	video.ReleaseDate = s.deriveReleaseDate(dateStr)
 
 	// 提取片长（只保留数字）
 	runtimeStr := extractField(html, `<span class="pl">片长:</span>`, `<br`)
@@ -587,22 +877,27 @@ func (s *DoubanSyncService) fetchAndUpdateSingleMovieDetail(video *model.Video)
 		return fmt.Errorf("更新数据库失败: %w", err)
 	}
 
-	zap.L().Info("更新电影详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
+	// 保存演职员（人物及其在该视频中的角色）、别名（原名、又名）和各地区上映日期
+	s.saveCredits(video, html)
+	s.saveTitles(video, html)
+	s.saveReleaseDates(video, html)
+
+	logger.FromContext(ctx).Info("更新电影详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
 	return nil
 }
 
 // fetchAndUpdateSingleTVDetail 获取并更新单个电视详情
-func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(video *model.Video) error {
+func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(ctx context.Context, video *model.Video) error {
 	// 检查 SourceID 是否存在
 	if video.SourceID == nil || *video.SourceID == 0 {
 		return fmt.Errorf("视频的 SourceID 为空或无效: title=%s, id=%d", video.Title, video.ID)
 	}
 
 	url := fmt.Sprintf("https://movie.douban.com/subject/%d/", *video.SourceID)
-	zap.L().Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
+	logger.FromContext(ctx).Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -626,7 +921,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(video *model.Video) err
 	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 
 	// 发送请求
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newSyncHTTPClient("douban", "detail", 30*time.Second, nil)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("请求失败: %w", err)
@@ -639,12 +934,20 @@ func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(video *model.Video) err
 		return fmt.Errorf("读取响应失败: %w", err)
 	}
 
+	// 归档原始页面，供解析逻辑修复后离线重新解析
+	s.archiveDetailPage(*video.SourceID, resp.StatusCode, body)
+
 	html := string(body)
 
+	return s.applyTVDetail(ctx, video, html)
+}
+
+// applyTVDetail 解析电视（动漫）详情页并更新视频（同步和离线重新解析共用）
+func (s *DoubanSyncService) applyTVDetail(ctx context.Context, video *model.Video, html string) error {
 	// 解析HTML，提取信息
 	directorStr := extractFieldWithAttrs(html, "导演")
 	actorsStr := extractFieldWithAttrs(html, "主演")
-	tagsStr := extractGenres(html)
+	tagsStr := extractGenres(ctx, html)
 	countryStr := extractField(html, `<span class="pl">制片国家/地区:</span>`, `<br`)
 
 	// 转换为JSON数组并截断到512字节以内
@@ -664,7 +967,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(video *model.Video) err
 	} else {
 		video.TagsJSON = []byte("[]")
 	}
-	if countryJSON, err := stringToCountryJSONArray(countryStr); err == nil {
+	if countryJSON, err := stringToCountryJSONArray(ctx, countryStr); err == nil {
 		video.CountryJSON = truncateJSONArray(countryJSON)
 	} else {
 		video.CountryJSON = []byte("[]")
@@ -675,9 +978,9 @@ func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(video *model.Video) err
 		video.Score = score
 	}
 
-	// 提取首播日期（完整日期）
+	// 提取首播日期（页面中列出多个地区时按地区偏好选取）
 	dateStr := extractField(html, `<span class="pl">首播:</span>`, `<br`)
-	video.ReleaseDate = parseDateString(dateStr)
+	video.ReleaseDate = s.deriveReleaseDate(dateStr)
 
 	// 提取集数（只保留数字）
 	episodeStr := extractField(html, `<span class="pl">集数:</span>`, `<br`)
@@ -701,22 +1004,27 @@ func (s *DoubanSyncService) fetchAndUpdateSingleTVDetail(video *model.Video) err
 		return fmt.Errorf("更新数据库失败: %w", err)
 	}
 
-	zap.L().Info("更新电视详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
+	// 保存演职员（人物及其在该视频中的角色）、别名（原名、又名）和各地区上映日期
+	s.saveCredits(video, html)
+	s.saveTitles(video, html)
+	s.saveReleaseDates(video, html)
+
+	logger.FromContext(ctx).Info("更新电视详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
 	return nil
 }
 
 // fetchAndUpdateSingleShowDetail 获取并更新单个综艺详情
-func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(video *model.Video) error {
+func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(ctx context.Context, video *model.Video) error {
 	// 检查 SourceID 是否存在
 	if video.SourceID == nil || *video.SourceID == 0 {
 		return fmt.Errorf("视频的 SourceID 为空或无效: title=%s, id=%d", video.Title, video.ID)
 	}
 
 	url := fmt.Sprintf("https://movie.douban.com/subject/%d/", *video.SourceID)
-	zap.L().Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
+	logger.FromContext(ctx).Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -740,7 +1048,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(video *model.Video) e
 	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 
 	// 发送请求
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newSyncHTTPClient("douban", "detail", 30*time.Second, nil)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("请求失败: %w", err)
@@ -753,11 +1061,19 @@ func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(video *model.Video) e
 		return fmt.Errorf("读取响应失败: %w", err)
 	}
 
+	// 归档原始页面，供解析逻辑修复后离线重新解析
+	s.archiveDetailPage(*video.SourceID, resp.StatusCode, body)
+
 	html := string(body)
 
+	return s.applyShowDetail(ctx, video, html)
+}
+
+// applyShowDetail 解析综艺详情页并更新视频（同步和离线重新解析共用）
+func (s *DoubanSyncService) applyShowDetail(ctx context.Context, video *model.Video, html string) error {
 	// 解析HTML，提取信息（综艺没有导演）
 	actorsStr := extractFieldWithAttrs(html, "主演")
-	tagsStr := extractGenres(html)
+	tagsStr := extractGenres(ctx, html)
 	countryStr := extractField(html, `<span class="pl">制片国家/地区:</span>`, `<br`)
 
 	// 转换为JSON数组并截断到512字节以内
@@ -767,7 +1083,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(video *model.Video) e
 	if tagsJSON, err := stringToJSONArray(tagsStr); err == nil {
 		video.TagsJSON = truncateJSONArray(tagsJSON)
 	}
-	if countryJSON, err := stringToCountryJSONArray(countryStr); err == nil {
+	if countryJSON, err := stringToCountryJSONArray(ctx, countryStr); err == nil {
 		video.CountryJSON = truncateJSONArray(countryJSON)
 	}
 
@@ -776,9 +1092,9 @@ func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(video *model.Video) e
 		video.Score = score
 	}
 
-	// 提取首播日期（完整日期）
+	// 提取首播日期（页面中列出多个地区时按地区偏好选取）
 	dateStr := extractField(html, `<span class="pl">首播:</span>`, `<br`)
-	video.ReleaseDate = parseDateString(dateStr)
+	video.ReleaseDate = s.deriveReleaseDate(dateStr)
 
 	// 提取集数（只保留数字）
 	episodeStr := extractField(html, `<span class="pl">集数:</span>`, `<br`)
@@ -799,22 +1115,27 @@ func (s *DoubanSyncService) fetchAndUpdateSingleShowDetail(video *model.Video) e
 		return fmt.Errorf("更新数据库失败: %w", err)
 	}
 
-	zap.L().Info("更新综艺详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
+	// 保存演职员（人物及其在该视频中的角色）、别名（原名、又名）和各地区上映日期
+	s.saveCredits(video, html)
+	s.saveTitles(video, html)
+	s.saveReleaseDates(video, html)
+
+	logger.FromContext(ctx).Info("更新综艺详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
 	return nil
 }
 
 // fetchAndUpdateSingleDocDetail 获取并更新单个纪录片详情
-func (s *DoubanSyncService) fetchAndUpdateSingleDocDetail(video *model.Video) error {
+func (s *DoubanSyncService) fetchAndUpdateSingleDocDetail(ctx context.Context, video *model.Video) error {
 	// 检查 SourceID 是否存在
 	if video.SourceID == nil || *video.SourceID == 0 {
 		return fmt.Errorf("视频的 SourceID 为空或无效: title=%s, id=%d", video.Title, video.ID)
 	}
 
 	url := fmt.Sprintf("https://movie.douban.com/subject/%d/", *video.SourceID)
-	zap.L().Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
+	logger.FromContext(ctx).Info("准备请求豆瓣详情页", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID), zap.String("url", url))
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -838,7 +1159,7 @@ func (s *DoubanSyncService) fetchAndUpdateSingleDocDetail(video *model.Video) er
 	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
 
 	// 发送请求
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newSyncHTTPClient("douban", "detail", 30*time.Second, nil)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("请求失败: %w", err)
@@ -851,17 +1172,25 @@ func (s *DoubanSyncService) fetchAndUpdateSingleDocDetail(video *model.Video) er
 		return fmt.Errorf("读取响应失败: %w", err)
 	}
 
+	// 归档原始页面，供解析逻辑修复后离线重新解析
+	s.archiveDetailPage(*video.SourceID, resp.StatusCode, body)
+
 	html := string(body)
 
+	return s.applyDocDetail(ctx, video, html)
+}
+
+// applyDocDetail 解析纪录片详情页并更新视频（同步和离线重新解析共用）
+func (s *DoubanSyncService) applyDocDetail(ctx context.Context, video *model.Video, html string) error {
 	// 解析HTML，提取信息（纪录片没有导演和主演）
-	tagsStr := extractGenres(html)
+	tagsStr := extractGenres(ctx, html)
 	countryStr := extractField(html, `<span class="pl">制片国家/地区:</span>`, `<br`)
 
 	// 转换为JSON数组并截断到512字节以内
 	if tagsJSON, err := stringToJSONArray(tagsStr); err == nil {
 		video.TagsJSON = truncateJSONArray(tagsJSON)
 	}
-	if countryJSON, err := stringToCountryJSONArray(countryStr); err == nil {
+	if countryJSON, err := stringToCountryJSONArray(ctx, countryStr); err == nil {
 		video.CountryJSON = truncateJSONArray(countryJSON)
 	}
 
@@ -870,9 +1199,9 @@ func (s *DoubanSyncService) fetchAndUpdateSingleDocDetail(video *model.Video) er
 		video.Score = score
 	}
 
-	// 提取首播日期（完整日期）
+	// 提取首播日期（页面中列出多个地区时按地区偏好选取）
 	dateStr := extractField(html, `<span class="pl">首播:</span>`, `<br`)
-	video.ReleaseDate = parseDateString(dateStr)
+	video.ReleaseDate = s.deriveReleaseDate(dateStr)
 
 	// 提取集数（只保留数字）
 	episodeStr := extractField(html, `<span class="pl">集数:</span>`, `<br`)
@@ -893,10 +1222,55 @@ func (s *DoubanSyncService) fetchAndUpdateSingleDocDetail(video *model.Video) er
 		return fmt.Errorf("更新数据库失败: %w", err)
 	}
 
-	zap.L().Info("更新纪录片详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
+	// 保存演职员（人物及其在该视频中的角色）、别名（原名、又名）和各地区上映日期
+	s.saveCredits(video, html)
+	s.saveTitles(video, html)
+	s.saveReleaseDates(video, html)
+
+	logger.FromContext(ctx).Info("更新纪录片详情成功", zap.String("title", video.Title), zap.Int64("source_id", *video.SourceID))
 	return nil
 }
 
+// fetchDoubanDetailHTML 请求豆瓣详情页并返回HTML
+func fetchDoubanDetailHTML(ctx context.Context, sourceID int64, referer string) (string, error) {
+	url := fmt.Sprintf("https://movie.douban.com/subject/%d/", sourceID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	// 设置请求头（与详情同步相同）
+	req.Header.Set("accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
+	req.Header.Set("accept-language", "zh-CN,zh;q=0.9,en;q=0.8")
+	req.Header.Set("cache-control", "no-cache")
+	req.Header.Set("dnt", "1")
+	req.Header.Set("pragma", "no-cache")
+	req.Header.Set("referer", referer)
+	req.Header.Set("sec-fetch-dest", "document")
+	req.Header.Set("sec-fetch-mode", "navigate")
+	req.Header.Set("sec-fetch-site", "same-origin")
+	req.Header.Set("upgrade-insecure-requests", "1")
+	req.Header.Set("user-agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36")
+
+	client := newSyncHTTPClient("douban", "detail", 30*time.Second, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("响应状态码异常: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("读取响应失败: %w", err)
+	}
+	return string(body), nil
+}
+
 // extractField 从HTML中提取字段值
 func extractField(html, startTag, endTag string) string {
 	startIdx := strings.Index(html, startTag)
@@ -941,7 +1315,7 @@ func extractFieldWithAttrs(html, label string) string {
 }
 
 // extractGenres 提取类型标签
-func extractGenres(html string) string {
+func extractGenres(ctx context.Context, html string) string {
 	// 查找所有 <span property="v:genre">...</span>
 	re := regexp.MustCompile(`<span property="v:genre">([^<]+)</span>`)
 	matches := re.FindAllStringSubmatch(html, -1)
@@ -951,6 +1325,11 @@ func extractGenres(html string) string {
 			genres = append(genres, strings.TrimSpace(match[1]))
 		}
 	}
+	// 转换为规范代码（如"剧情"→drama）
+	genres, unknown := dictionary.Genres.Canonicalize(genres)
+	if len(unknown) > 0 {
+		logger.FromContext(ctx).Debug("类型词典中没有的取值", zap.Strings("values", unknown))
+	}
 	return strings.Join(genres, ", ")
 }
 
@@ -1110,8 +1489,8 @@ func stringToJSONArray(str string) ([]byte, error) {
 
 // stringToCountryJSONArray 将国家/地区字符串转换为JSON数组
 // 支持多种分隔符：", "、","、" / "、"/"
-// 每个国家作为一个独立的JSON值存储，如：["中国大陆","美国"]
-func stringToCountryJSONArray(str string) ([]byte, error) {
+// 每个国家转换为规范代码后作为一个独立的JSON值存储，如：["CN","US"]
+func stringToCountryJSONArray(ctx context.Context, str string) ([]byte, error) {
 	if str == "" {
 		return []byte("[]"), nil
 	}
@@ -1129,6 +1508,11 @@ func stringToCountryJSONArray(str string) ([]byte, error) {
 			items = append(items, trimmed)
 		}
 	}
+	// 转换为规范代码（如"中国香港"、"香港"→HK）
+	items, unknown := dictionary.Countries.Canonicalize(items)
+	if len(unknown) > 0 {
+		logger.FromContext(ctx).Debug("国家/地区词典中没有的取值", zap.Strings("values", unknown))
+	}
 	return json.Marshal(items)
 }
 
@@ -1172,8 +1556,8 @@ func truncateJSONArray(jsonData []byte) []byte {
 }
 
 // searchAndSavePlayURLs 搜索播放地址并保存到episodes表（多线程并发执行）
-func (s *DoubanSyncService) searchAndSavePlayURLs() error {
-	zap.L().Info("开始搜索播放地址")
+func (s *DoubanSyncService) searchAndSavePlayURLs(ctx context.Context) error {
+	logger.FromContext(ctx).Info("开始搜索播放地址")
 
 	// 查询 status 不等于 0 和 1 的视频的 id、type、title（用于更新episodes）
 	videos, err := s.videoRepo.FindVideosNeedUpdateEpisodes()
@@ -1182,11 +1566,11 @@ func (s *DoubanSyncService) searchAndSavePlayURLs() error {
 	}
 
 	if len(videos) == 0 {
-		zap.L().Info("没有需要搜索播放地址的视频")
+		logger.FromContext(ctx).Info("没有需要搜索播放地址的视频")
 		return nil
 	}
 
-	zap.L().Info("找到需要搜索播放地址的视频", zap.Int("count", len(videos)))
+	logger.FromContext(ctx).Info("找到需要搜索播放地址的视频", zap.Int("count", len(videos)))
 
 	// 过滤掉title为空的视频
 	validVideos := make([]*model.Video, 0, len(videos))
@@ -1197,7 +1581,7 @@ func (s *DoubanSyncService) searchAndSavePlayURLs() error {
 	}
 
 	if len(validVideos) == 0 {
-		zap.L().Info("没有有效的视频需要搜索播放地址")
+		logger.FromContext(ctx).Info("没有有效的视频需要搜索播放地址")
 		return nil
 	}
 
@@ -1227,14 +1611,14 @@ func (s *DoubanSyncService) searchAndSavePlayURLs() error {
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
+			ctx, log := logger.With(ctx, zap.Int("worker_id", workerID))
 
 			for video := range videoChan {
-				if err := s.searchAndSavePlayURLsForVideo(video); err != nil {
-					zap.L().Error("搜索播放地址失败",
+				if err := s.traceVideo(ctx, "sync.playurl", video, s.searchAndSavePlayURLsForVideo); err != nil {
+					log.Error("搜索播放地址失败",
 						zap.Error(err),
 						zap.String("title", video.Title),
-						zap.Int64("id", video.ID),
-						zap.Int("worker_id", workerID))
+						zap.Int64("id", video.ID))
 
 					mu.Lock()
 					failCount++
@@ -1244,14 +1628,13 @@ func (s *DoubanSyncService) searchAndSavePlayURLs() error {
 					successCount++
 					mu.Unlock()
 
-					zap.L().Info("搜索播放地址成功",
+					log.Info("搜索播放地址成功",
 						zap.String("title", video.Title),
-						zap.Int64("id", video.ID),
-						zap.Int("worker_id", workerID))
+						zap.Int64("id", video.ID))
 				}
 
 				// 避免请求过快，每个worker处理完一个任务后休眠
-				time.Sleep(500 * time.Millisecond)
+				syncThrottle(500 * time.Millisecond)
 			}
 		}(i)
 	}
@@ -1259,22 +1642,22 @@ func (s *DoubanSyncService) searchAndSavePlayURLs() error {
 	// 等待所有goroutine完成
 	wg.Wait()
 
-	zap.L().Info("播放地址搜索完成",
+	logger.FromContext(ctx).Info("播放地址搜索完成",
 		zap.Int("total", len(validVideos)),
 		zap.Int("success", successCount),
 		zap.Int("failed", failCount))
 	return nil
 }
 
-// searchAndSavePlayURLsForVideo 为单个视频搜索播放地址并保存
-func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) error {
-	// 构建搜索URL，使用title替换q参数
-	searchURL := fmt.Sprintf("http://124.222.196.128:3000/api/search?q=%s", url.QueryEscape(video.Title))
+// fetchPlayURLSearchResults 请求播放地址搜索接口，同时返回原始响应（用于归档）
+func fetchPlayURLSearchResults(ctx context.Context, query string) ([]SearchResult, []byte, error) {
+	// 构建搜索URL，使用query替换q参数
+	searchURL := fmt.Sprintf("http://124.222.196.128:3000/api/search?q=%s", url.QueryEscape(query))
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
-		return fmt.Errorf("创建请求失败: %w", err)
+		return nil, nil, fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	// 设置请求头
@@ -1285,36 +1668,51 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 	req.Header.Set("Cookie", "auth=%257B%2522role%2522%253A%2522user%2522%252C%2522password%2522%253A%252212345%2522%257D")
 
 	// 创建HTTP客户端（跳过SSL验证）
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
+	client := newSyncHTTPClient("playurl", "search", 30*time.Second, &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	})
 
 	// 发送请求
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("请求失败: %w", err)
+		return nil, nil, fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("读取响应失败: %w", err)
+		return nil, nil, fmt.Errorf("读取响应失败: %w", err)
 	}
 
 	// 解析JSON响应
 	var searchResponse SearchResponse
 	if err := json.Unmarshal(body, &searchResponse); err != nil {
-		return fmt.Errorf("解析JSON失败: %w", err)
+		return nil, nil, fmt.Errorf("解析JSON失败: %w", err)
+	}
+
+	return searchResponse.Results, body, nil
+}
+
+// searchAndSavePlayURLsForVideo 为单个视频搜索播放地址并保存
+func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(ctx context.Context, video *model.Video) error {
+	// 依次使用标题和别名搜索，直到搜索结果中出现匹配的标题
+	titles := s.searchTitles(video)
+	results, err := s.searchPlayURLResults(ctx, video, titles)
+	if err != nil {
+		s.observePlayURLSearch(video.Type, playURLSearchFailed)
+		return err
+	}
+	if len(results) == 0 {
+		s.observePlayURLSearch(video.Type, playURLSearchUnmatched)
+	} else {
+		s.observePlayURLSearch(video.Type, playURLSearchMatched)
 	}
 
 	// 遍历搜索结果，只处理第一个匹配的 result
-	for _, result := range searchResponse.Results {
-		// 判断 results.title = videos.title
-		if result.Title != video.Title {
+	for _, result := range results {
+		// 判断 results.title 与 videos.title 或任一别名相同
+		if !matchesAnyTitle(result.Title, titles) {
 			continue
 		}
 
@@ -1375,7 +1773,7 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 			playURL := firstLine
 			if len(playURL) > 255 {
 				playURL = playURL[:255]
-				zap.L().Warn("播放地址长度超过255字符，已截断", zap.String("original", firstLine), zap.String("truncated", playURL))
+				logger.FromContext(ctx).Warn("播放地址长度超过255字符，已截断", zap.String("original", firstLine), zap.String("truncated", playURL))
 			}
 
 			// 创建episode记录
@@ -1396,29 +1794,35 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 
 			// 插入到数据库
 			if err := s.episodeRepo.Create(episode); err != nil {
-				zap.L().Error("插入episode失败", zap.Error(err), zap.String("title", result.Title), zap.Int64("video_id", video.ID))
+				logger.FromContext(ctx).Error("插入episode失败", zap.Error(err), zap.String("title", result.Title), zap.Int64("video_id", video.ID))
 				return fmt.Errorf("插入episode失败: %w", err)
 			}
 
-			zap.L().Info("插入episode成功", zap.String("title", result.Title), zap.Int64("video_id", video.ID), zap.Int64("episode_number", episodeNumber), zap.String("play_url", firstLine))
+			logger.FromContext(ctx).Info("插入episode成功", zap.String("title", result.Title), zap.Int64("video_id", video.ID), zap.Int64("episode_number", episodeNumber), zap.String("play_url", firstLine))
+			s.publishEpisodesAdded(ctx, video, []int64{episodeNumber})
 
 			// movie类型：检查videos.id在episodes表的video_id是否存在，如果存在则更新status，并将is_completed设为1
 			if video.Type == "movie" {
 				exists, err := s.episodeRepo.ExistsByVideoID(video.ID)
 				if err != nil {
-					zap.L().Error("检查episode是否存在失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+					logger.FromContext(ctx).Error("检查episode是否存在失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 				} else if exists {
 					// 更新videos表status的值为1
 					if err := s.videoRepo.UpdateVideoStatus(video.ID, "1"); err != nil {
-						zap.L().Error("更新视频status失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+						logger.FromContext(ctx).Error("更新视频status失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 					} else {
-						zap.L().Info("更新视频status为1", zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+						logger.FromContext(ctx).Info("更新视频status为1", zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+						// status 从其他值变为1时发布视频发布事件
+						if video.Status != "1" {
+							video.Status = "1"
+							s.publishVideoPublished(ctx, video)
+						}
 					}
 					// movie类型只有单集，直接标记is_completed为1
 					if err := s.videoRepo.UpdateVideoIsCompleted(video.ID, true); err != nil {
-						zap.L().Error("更新视频is_completed失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+						logger.FromContext(ctx).Error("更新视频is_completed失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 					} else {
-						zap.L().Info("更新视频is_completed", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Bool("is_completed", true))
+						logger.FromContext(ctx).Info("更新视频is_completed", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Bool("is_completed", true))
 					}
 				}
 			}
@@ -1465,20 +1869,25 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 			// 检查videos.id在episodes表的video_id是否存在
 			exists, err := s.episodeRepo.ExistsByVideoID(video.ID)
 			if err != nil {
-				zap.L().Error("检查episode是否存在失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+				logger.FromContext(ctx).Error("检查episode是否存在失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 			} else if exists {
 				// 如果存在，更新videos表status的值为1
 				if err := s.videoRepo.UpdateVideoStatus(video.ID, "1"); err != nil {
-					zap.L().Error("更新视频status失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+					logger.FromContext(ctx).Error("更新视频status失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 				} else {
-					zap.L().Info("更新视频status为1", zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+					logger.FromContext(ctx).Info("更新视频status为1", zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+					// status 从其他值变为1时发布视频发布事件
+					if video.Status != "1" {
+						video.Status = "1"
+						s.publishVideoPublished(ctx, video)
+					}
 				}
 			}
 
 			// 获取当前已存在的episode数量
 			existingCount, err := s.episodeRepo.CountByVideoID(video.ID)
 			if err != nil {
-				zap.L().Error("统计episode数量失败", zap.Error(err), zap.Int64("video_id", video.ID))
+				logger.FromContext(ctx).Error("统计episode数量失败", zap.Error(err), zap.Int64("video_id", video.ID))
 				existingCount = 0
 			}
 
@@ -1490,6 +1899,7 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 				// 从existingCount+1开始，增量插入新的episodes
 				startIndex := int(existingCount)
 				newEpisodesCount := 0
+				var newEpisodeNumbers []int64
 
 				for i := startIndex; i < len(selectedEpisodes); i++ {
 					episodeValue := strings.TrimSpace(selectedEpisodes[i])
@@ -1501,7 +1911,7 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 					playURL := episodeValue
 					if len(playURL) > 255 {
 						playURL = playURL[:255]
-						zap.L().Warn("播放地址长度超过255字符，已截断", zap.String("original", episodeValue), zap.String("truncated", playURL))
+						logger.FromContext(ctx).Warn("播放地址长度超过255字符，已截断", zap.String("original", episodeValue), zap.String("truncated", playURL))
 					}
 
 					// 创建episode记录，episode_number从existingCount+1开始
@@ -1522,21 +1932,21 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 
 					// 插入到数据库
 					if err := s.episodeRepo.Create(episode); err != nil {
-						zap.L().Error("插入episode失败", zap.Error(err), zap.String("title", result.Title), zap.Int64("video_id", video.ID), zap.Int64("episode_number", episodeNumber))
+						logger.FromContext(ctx).Error("插入episode失败", zap.Error(err), zap.String("title", result.Title), zap.Int64("video_id", video.ID), zap.Int64("episode_number", episodeNumber))
 						continue
 					}
 
 					newEpisodesCount++
-					zap.L().Info("插入episode成功", zap.String("title", result.Title), zap.Int64("video_id", video.ID), zap.Int64("episode_number", episodeNumber), zap.String("play_url", episodeValue))
+					newEpisodeNumbers = append(newEpisodeNumbers, episodeNumber)
+					logger.FromContext(ctx).Info("插入episode成功", zap.String("title", result.Title), zap.Int64("video_id", video.ID), zap.Int64("episode_number", episodeNumber), zap.String("play_url", episodeValue))
 				}
 
 				// 如果有新增episodes，更新videos表的updated_at为当前时间
 				if newEpisodesCount > 0 {
-					if err := database.DB.Model(&model.Video{}).
-						Where("id = ?", video.ID).
-						Update("updated_at", time.Now()).Error; err != nil {
-						zap.L().Error("更新视频updated_at失败", zap.Error(err), zap.Int64("video_id", video.ID))
+					if err := s.videoRepo.TouchUpdatedAt(video.ID); err != nil {
+						logger.FromContext(ctx).Error("更新视频updated_at失败", zap.Error(err), zap.Int64("video_id", video.ID))
 					}
+					s.publishEpisodesAdded(ctx, video, newEpisodeNumbers)
 				}
 			}
 
@@ -1555,9 +1965,9 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 		}
 		// 更新is_update字段
 		if err := s.videoRepo.UpdateVideoIsUpdate(video.ID, isUpdate); err != nil {
-			zap.L().Error("更新视频is_update失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+			logger.FromContext(ctx).Error("更新视频is_update失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 		} else {
-			zap.L().Info("更新视频is_update", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Bool("is_update", isUpdate))
+			logger.FromContext(ctx).Info("更新视频is_update", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Bool("is_update", isUpdate))
 		}
 
 		// 获取视频完整信息，检查episode_count（movie类型已在分支中处理，这里只处理非movie类型）
@@ -1570,9 +1980,9 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 					// 如果episodes总数等于episode_count，则is_completed为1
 					isCompleted := currentCount == *videoInfo.EpisodeCount
 					if err := s.videoRepo.UpdateVideoIsCompleted(video.ID, isCompleted); err != nil {
-						zap.L().Error("更新视频is_completed失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
+						logger.FromContext(ctx).Error("更新视频is_completed失败", zap.Error(err), zap.Int64("video_id", video.ID), zap.String("title", video.Title))
 					} else {
-						zap.L().Info("更新视频is_completed", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Bool("is_completed", isCompleted), zap.Int64("current_count", currentCount), zap.Int64("episode_count", *videoInfo.EpisodeCount))
+						logger.FromContext(ctx).Info("更新视频is_completed", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Bool("is_completed", isCompleted), zap.Int64("current_count", currentCount), zap.Int64("episode_count", *videoInfo.EpisodeCount))
 					}
 				}
 			}
@@ -1586,13 +1996,23 @@ func (s *DoubanSyncService) searchAndSavePlayURLsForVideo(video *model.Video) er
 }
 
 // updateVideosStatusByEpisodes 更新存在 episodes 记录的 videos 的 status 为 1
-func (s *DoubanSyncService) updateVideosStatusByEpisodes() error {
-	zap.L().Info("开始更新视频状态")
+func (s *DoubanSyncService) updateVideosStatusByEpisodes(ctx context.Context) error {
+	s.log.Info("开始更新视频状态")
+
+	// 先查询将被更新的视频，用于在更新后发布视频发布事件
+	videos, err := s.videoRepo.FindVideosWithEpisodesByStatusNotEqual("1")
+	if err != nil {
+		return fmt.Errorf("查询待更新状态的视频失败: %w", err)
+	}
 
 	if err := s.videoRepo.UpdateVideosStatusByEpisodes("1"); err != nil {
 		return fmt.Errorf("更新视频状态失败: %w", err)
 	}
 
-	zap.L().Info("视频状态更新完成")
+	for _, video := range videos {
+		s.publishVideoPublished(ctx, video)
+	}
+
+	s.log.Info("视频状态更新完成")
 	return nil
 }
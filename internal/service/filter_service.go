@@ -0,0 +1,193 @@
+// service 包提供业务逻辑层
+// filter_service.go 提供筛选项统计：根据已发布视频的标签、国家/地区和上映年份生成各类型的筛选项（含视频数）
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/dictionary"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// filterRecentYears 最近多少年单独作为年份筛选项，更早的年份按年代合并
+const filterRecentYears = 10
+
+// FilterOption 一个筛选项
+type FilterOption struct {
+	Name   string `json:"name"`              // 显示名称
+	NameEn string `json:"name_en,omitempty"` // 英文名称（标签和国家/地区）
+	Value  string `json:"value"`             // 筛选值（标签和国家/地区为规范代码，年份区间格式为 "起始年-结束年"，包含两端）
+	Count  int64  `json:"count"`             // 已发布视频数
+}
+
+// VideoFilters 某一视频类型的全部筛选项
+type VideoFilters struct {
+	Type      string          `json:"type"`
+	Tags      []*FilterOption `json:"tags"`      // 按视频数降序
+	Countries []*FilterOption `json:"countries"` // 按视频数降序
+	Years     []*FilterOption `json:"years"`     // 按年份降序
+	UpdatedAt *time.Time      `json:"updated_at"`
+}
+
+// FilterService 筛选项服务
+type FilterService struct {
+	repo repository.FilterRepository
+}
+
+// NewFilterService 创建筛选项服务实例
+func NewFilterService() *FilterService {
+	return &FilterService{
+		repo: repository.NewFilterRepository(),
+	}
+}
+
+// GetFilters 查询指定视频类型的筛选项
+func (s *FilterService) GetFilters(ctx context.Context, videoType string) (*VideoFilters, error) {
+	if videoType == "" {
+		return nil, errors.ErrFilterTypeRequired
+	}
+
+	rows, err := s.repo.FindByType(videoType)
+	if err != nil {
+		logger.FromContext(ctx).Error("查询筛选项失败", zap.String("type", videoType), zap.Error(err))
+		return nil, errors.ErrFilterQueryFailed
+	}
+
+	filters := &VideoFilters{
+		Type:      videoType,
+		Tags:      []*FilterOption{},
+		Countries: []*FilterOption{},
+		Years:     []*FilterOption{},
+	}
+	// 写入时已按展示顺序插入，按ID顺序读取即可
+	for _, row := range rows {
+		switch {
+		case row.Tags != "":
+			filters.Tags = append(filters.Tags, &FilterOption{
+				Name:   row.Name,
+				NameEn: dictionary.Genres.EnglishName(row.Tags),
+				Value:  row.Tags,
+				Count:  row.Count,
+			})
+		case row.Country != "":
+			filters.Countries = append(filters.Countries, &FilterOption{
+				Name:   row.Name,
+				NameEn: dictionary.Countries.EnglishName(row.Country),
+				Value:  row.Country,
+				Count:  row.Count,
+			})
+		case row.Year != "":
+			filters.Years = append(filters.Years, &FilterOption{Name: row.Name, Value: row.Year, Count: row.Count})
+		}
+		if row.UpdatedAt != nil && (filters.UpdatedAt == nil || row.UpdatedAt.After(*filters.UpdatedAt)) {
+			filters.UpdatedAt = row.UpdatedAt
+		}
+	}
+	return filters, nil
+}
+
+// Refresh 重新统计所有视频类型的筛选项（只统计已发布的视频）
+func (s *FilterService) Refresh(ctx context.Context) error {
+	types, err := s.repo.FindPublishedTypes()
+	if err != nil {
+		return fmt.Errorf("查询视频类型失败: %w", err)
+	}
+
+	for _, videoType := range types {
+		if err := s.refreshType(videoType); err != nil {
+			return fmt.Errorf("统计%s筛选项失败: %w", videoType, err)
+		}
+	}
+
+	// 已没有已发布视频的类型不再返回筛选项
+	if err := s.repo.DeleteExceptTypes(types); err != nil {
+		return fmt.Errorf("清理筛选项失败: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("筛选项统计完成", zap.Strings("types", types))
+	return nil
+}
+
+// refreshType 统计单个视频类型的筛选项
+func (s *FilterService) refreshType(videoType string) error {
+	tags, err := s.repo.CountTags(videoType)
+	if err != nil {
+		return err
+	}
+	countries, err := s.repo.CountCountries(videoType)
+	if err != nil {
+		return err
+	}
+	years, err := s.repo.CountYears(videoType)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]*model.FilterInfo, 0, len(tags)+len(countries)+len(years))
+	for _, tag := range tags {
+		rows = append(rows, &model.FilterInfo{Name: dictionary.Genres.DisplayName(tag.Value), Type: videoType, Tags: tag.Value, Count: tag.Count})
+	}
+	for _, country := range countries {
+		rows = append(rows, &model.FilterInfo{Name: dictionary.Countries.DisplayName(country.Value), Type: videoType, Country: country.Value, Count: country.Count})
+	}
+	for _, bucket := range buildYearBuckets(years, time.Now().Year()) {
+		rows = append(rows, &model.FilterInfo{Name: bucket.Name, Type: videoType, Year: bucket.Value, Count: bucket.Count})
+	}
+
+	return s.repo.ReplaceByType(videoType, rows)
+}
+
+// buildYearBuckets 生成年份筛选项（按年份降序）：
+// 最近 filterRecentYears 年每年一项（如 "2024"），更早的按年代合并（如 "2000-2009"，显示为 "2000年代"），
+// 与最近年份重叠的年代只包含剩余的年份（如 "2010-2016"）
+func buildYearBuckets(counts []*repository.YearCount, currentYear int) []*FilterOption {
+	recentStart := currentYear - filterRecentYears + 1
+
+	var buckets []*FilterOption
+	var decade *FilterOption
+	decadeStart := 0
+	for _, yc := range counts {
+		if yc.Year <= 0 {
+			continue
+		}
+		if yc.Year >= recentStart {
+			year := strconv.Itoa(yc.Year)
+			buckets = append(buckets, &FilterOption{Name: year, Value: year, Count: yc.Count})
+			continue
+		}
+
+		start := yc.Year / 10 * 10
+		if decade == nil || start != decadeStart {
+			end := start + 9
+			if end >= recentStart {
+				end = recentStart - 1
+			}
+			decadeStart = start
+			decade = &FilterOption{
+				Name:  fmt.Sprintf("%d年代", start),
+				Value: fmt.Sprintf("%d-%d", start, end),
+			}
+			buckets = append(buckets, decade)
+		}
+		decade.Count += yc.Count
+	}
+	return buckets
+}
+
+// refreshFilters 同步结束后重新统计筛选项（预演模式下不处理），失败只记录日志
+func (s *DoubanSyncService) refreshFilters(ctx context.Context) {
+	if s.filters == nil {
+		return
+	}
+	if err := s.filters.Refresh(ctx); err != nil {
+		logger.FromContext(ctx).Error("统计筛选项失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,228 @@
+// service 包提供业务逻辑层
+// home_service.go 提供首页推荐位：按配置的规则（类型、排序、时间范围等）查询已发布的视频，每个推荐位单独缓存在Redis中，同步发布变更后清除
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/cache"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// homeRailKeyPrefix 推荐位缓存的Redis键前缀，完整的键为 home:rail:{key}
+const homeRailKeyPrefix = "home:rail:"
+
+// 首页推荐位参数
+const (
+	defaultHomeRailLimit = 20
+	maxHomeRailLimit     = 50
+	defaultHomeCacheTTL  = 10 * time.Minute
+)
+
+// HomeRailConfig 推荐位配置（home.rails）
+type HomeRailConfig struct {
+	Key            string        `mapstructure:"key"`             // 推荐位标识（唯一，同时用作缓存键）
+	Title          string        `mapstructure:"title"`           // 显示名称
+	Type           string        `mapstructure:"type"`            // 视频类型，为空表示全部类型
+	Sort           string        `mapstructure:"sort"`            // 排序方式：release_date（默认）/ score / updated
+	IsUpdate       *bool         `mapstructure:"is_update"`       // 是否有更新
+	ScoreMin       *float64      `mapstructure:"score_min"`       // 评分下限（含）
+	ReleasedWithin time.Duration `mapstructure:"released_within"` // 只包含最近这段时间内上映的视频
+	AddedWithin    time.Duration `mapstructure:"added_within"`    // 只包含最近这段时间内入库的视频
+	Limit          int           `mapstructure:"limit"`           // 视频数，默认20，最大50
+	TTL            time.Duration `mapstructure:"ttl"`             // 缓存时间，默认使用 home.cache_ttl
+}
+
+// HomeRail 首页推荐位
+type HomeRail struct {
+	Key   string          `json:"key"`
+	Title string          `json:"title"`
+	Type  string          `json:"type"`
+	List  []*VideoSummary `json:"list"`
+}
+
+// defaultHomeRails 未配置 home.rails 时使用的推荐位
+var defaultHomeRails = []*HomeRailConfig{
+	{Key: "recently_updated", Title: "最近更新", Sort: repository.VideoSortUpdated, IsUpdate: boolPtr(true)},
+	{Key: "hot_movies", Title: "热门电影", Type: "movie", Sort: repository.VideoSortScore, ReleasedWithin: 90 * 24 * time.Hour},
+	{Key: "top_rated_movie", Title: "高分电影", Type: "movie", Sort: repository.VideoSortScore},
+	{Key: "top_rated_tv", Title: "高分剧集", Type: "tv", Sort: repository.VideoSortScore},
+	{Key: "top_rated_anime", Title: "高分动漫", Type: "anime", Sort: repository.VideoSortScore},
+	{Key: "top_rated_tvshow", Title: "高分综艺", Type: "tvshow", Sort: repository.VideoSortScore},
+	{Key: "top_rated_doc", Title: "高分纪录片", Type: "doc", Sort: repository.VideoSortScore},
+	{Key: "new_this_week", Title: "本周新增", Sort: repository.VideoSortReleaseDate, AddedWithin: 7 * 24 * time.Hour},
+}
+
+// HomeService 首页推荐位服务
+type HomeService struct {
+	repo  repository.VideoListRepository
+	rdb   *redis.Client // 未配置Redis时为nil，每次请求都查询MySQL
+	rails []*HomeRailConfig
+}
+
+// NewHomeService 创建首页推荐位服务实例
+// 配置项：
+//   - home.rails: 推荐位列表（key/title/type/sort/is_update/score_min/released_within/added_within/limit/ttl），未配置时使用默认推荐位
+//   - home.cache_ttl: 推荐位默认缓存时间（默认10m）
+func NewHomeService() *HomeService {
+	var rails []*HomeRailConfig
+	if err := config.Cfg.UnmarshalKey("home.rails", &rails); err != nil {
+		zap.L().Error("解析home.rails配置失败", zap.Error(err))
+		rails = nil
+	}
+	if len(rails) == 0 {
+		rails = defaultHomeRails
+	}
+
+	ttl := config.Cfg.GetDuration("home.cache_ttl")
+	if ttl <= 0 {
+		ttl = defaultHomeCacheTTL
+	}
+
+	normalized := make([]*HomeRailConfig, 0, len(rails))
+	seen := make(map[string]bool)
+	for _, rail := range rails {
+		if rail == nil || rail.Key == "" || seen[rail.Key] {
+			zap.L().Warn("忽略无效或重复的首页推荐位配置", zap.Any("rail", rail))
+			continue
+		}
+		r := *rail
+		if r.Sort == "" {
+			r.Sort = repository.VideoSortReleaseDate
+		}
+		if !repository.IsValidVideoSort(r.Sort) {
+			zap.L().Warn("忽略排序方式无效的首页推荐位", zap.String("key", r.Key), zap.String("sort", r.Sort))
+			continue
+		}
+		if r.Limit <= 0 {
+			r.Limit = defaultHomeRailLimit
+		}
+		if r.Limit > maxHomeRailLimit {
+			r.Limit = maxHomeRailLimit
+		}
+		if r.TTL <= 0 {
+			r.TTL = ttl
+		}
+		seen[r.Key] = true
+		normalized = append(normalized, &r)
+	}
+
+	return &HomeService{
+		repo:  repository.NewVideoListRepository(),
+		rdb:   cache.Rdb,
+		rails: normalized,
+	}
+}
+
+// Rails 按配置顺序返回全部推荐位，优先读取缓存
+func (s *HomeService) Rails(ctx context.Context) ([]*HomeRail, error) {
+	result := make([]*HomeRail, 0, len(s.rails))
+	for _, rail := range s.rails {
+		list, err := s.railVideos(ctx, rail)
+		if err != nil {
+			logger.FromContext(ctx).Error("查询首页推荐位失败", zap.String("key", rail.Key), zap.Error(err))
+			return nil, errors.ErrVideoQueryFailed
+		}
+		result = append(result, &HomeRail{
+			Key:   rail.Key,
+			Title: rail.Title,
+			Type:  rail.Type,
+			List:  list,
+		})
+	}
+	return result, nil
+}
+
+// Invalidate 清除全部推荐位的缓存（未配置Redis时不处理）
+func (s *HomeService) Invalidate(ctx context.Context) error {
+	if s.rdb == nil || len(s.rails) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(s.rails))
+	for _, rail := range s.rails {
+		keys = append(keys, homeRailKeyPrefix+rail.Key)
+	}
+	return s.rdb.Del(ctx, keys...).Err()
+}
+
+// railVideos 查询推荐位的视频：缓存命中时直接返回，否则查询MySQL并写入缓存
+// 读写缓存失败只记录日志，不影响返回结果
+func (s *HomeService) railVideos(ctx context.Context, rail *HomeRailConfig) ([]*VideoSummary, error) {
+	key := homeRailKeyPrefix + rail.Key
+	if s.rdb != nil {
+		data, err := s.rdb.Get(ctx, key).Bytes()
+		if err == nil {
+			var list []*VideoSummary
+			if err := json.Unmarshal(data, &list); err == nil {
+				return list, nil
+			}
+			logger.FromContext(ctx).Warn("首页推荐位缓存格式错误", zap.String("key", key), zap.Error(err))
+		} else if err != redis.Nil {
+			logger.FromContext(ctx).Warn("读取首页推荐位缓存失败", zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	list, err := s.queryRail(rail)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.rdb != nil {
+		data, _ := json.Marshal(list)
+		if err := s.rdb.Set(ctx, key, data, rail.TTL).Err(); err != nil {
+			logger.FromContext(ctx).Warn("写入首页推荐位缓存失败", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return list, nil
+}
+
+// queryRail 按推荐位配置查询已发布的视频
+func (s *HomeService) queryRail(rail *HomeRailConfig) ([]*VideoSummary, error) {
+	filter := &repository.VideoListFilter{
+		Type:     rail.Type,
+		IsUpdate: rail.IsUpdate,
+		ScoreMin: rail.ScoreMin,
+		Sort:     rail.Sort,
+		Limit:    rail.Limit,
+	}
+	now := time.Now()
+	if rail.ReleasedWithin > 0 {
+		since := now.Add(-rail.ReleasedWithin)
+		filter.ReleasedSince = &since
+	}
+	if rail.AddedWithin > 0 {
+		since := now.Add(-rail.AddedWithin)
+		filter.CreatedSince = &since
+	}
+
+	videos, err := s.repo.ListPublished(filter)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*VideoSummary, 0, len(videos))
+	for _, video := range videos {
+		list = append(list, newVideoSummary(video))
+	}
+	return list, nil
+}
+
+// boolPtr 返回布尔值的指针
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+// invalidateHome 本次同步有变更时清除首页推荐位缓存（预演模式下跳过）
+func (s *DoubanSyncService) invalidateHome(ctx context.Context) error {
+	if s.home == nil || !s.stats.snapshot().hasChanges() {
+		return nil
+	}
+	return s.home.Invalidate(ctx)
+}
@@ -0,0 +1,173 @@
+// service 包提供业务逻辑层
+// page_archive_service.go 提供原始页面归档：同步请求到的详情页、列表条目和播放地址搜索响应gzip压缩后保存到归档存储，供离线重新解析使用
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/blobstore"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// 归档存储类型（配置项 archive.storage）
+const (
+	archiveStorageLocal = "local" // 本地目录（archive.dir）
+	archiveStorageBlob  = "blob"  // 对象存储（storage 配置）
+)
+
+// defaultArchiveDir 本地归档目录默认值
+const defaultArchiveDir = "./data/archive"
+
+// PageArchive 原始页面归档
+type PageArchive struct {
+	store blobstore.Store
+	repo  repository.PageArchiveRepository
+}
+
+// NewPageArchive 创建页面归档实例，未配置 archive.storage 时返回nil（不归档）
+// archive.storage 为 local 时保存到 archive.dir（默认 ./data/archive），为 blob 时保存到对象存储
+func NewPageArchive() *PageArchive {
+	var store blobstore.Store
+	switch storage := config.Cfg.GetString("archive.storage"); storage {
+	case "":
+		return nil
+	case archiveStorageLocal:
+		dir := config.Cfg.GetString("archive.dir")
+		if dir == "" {
+			dir = defaultArchiveDir
+		}
+		local, err := blobstore.NewLocalStore(dir, "", "")
+		if err != nil {
+			zap.L().Error("初始化页面归档目录失败，不归档页面", zap.String("dir", dir), zap.Error(err))
+			return nil
+		}
+		store = local
+	case archiveStorageBlob:
+		if blobstore.Blob == nil {
+			zap.L().Warn("archive.storage 为 blob 但未配置对象存储（storage.driver），不归档页面")
+			return nil
+		}
+		store = blobstore.Blob
+	default:
+		zap.L().Warn("未知的 archive.storage，不归档页面", zap.String("storage", storage))
+		return nil
+	}
+
+	return &PageArchive{
+		store: store,
+		repo:  repository.NewPageArchiveRepository(),
+	}
+}
+
+// archiveKey 返回归档对象键，如 pages/douban/detail/1292052.gz、pages/playurl/search/{视频ID}.gz
+func archiveKey(source, kind string, sourceID int64) string {
+	return fmt.Sprintf("pages/%s/%s/%d.gz", source, kind, sourceID)
+}
+
+// Save 压缩并保存页面，同一来源ID只保留最近一次请求的页面
+func (a *PageArchive) Save(source string, sourceID int64, kind, contentType string, body []byte) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return fmt.Errorf("压缩页面失败: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("压缩页面失败: %w", err)
+	}
+
+	key := archiveKey(source, kind, sourceID)
+	if err := a.store.Put(context.Background(), key, buf.Bytes(), "application/gzip"); err != nil {
+		return fmt.Errorf("保存归档页面失败: %w", err)
+	}
+
+	return a.repo.Save(&model.PageArchive{
+		Source:         source,
+		SourceID:       sourceID,
+		Kind:           kind,
+		StorageKey:     key,
+		ContentType:    contentType,
+		Size:           int64(len(body)),
+		CompressedSize: int64(buf.Len()),
+		FetchedAt:      time.Now(),
+	})
+}
+
+// Load 读取并解压归档的页面
+func (a *PageArchive) Load(archive *model.PageArchive) ([]byte, error) {
+	data, err := a.store.Get(context.Background(), archive.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档页面失败: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解压归档页面失败: %w", err)
+	}
+	defer zr.Close()
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("解压归档页面失败: %w", err)
+	}
+	return body, nil
+}
+
+// archiveDetailPage 归档豆瓣详情页（未启用归档或预演模式下不处理），失败只记录日志
+// 只归档正常的详情页，避免错误页或反爬验证页覆盖已归档的页面
+func (s *DoubanSyncService) archiveDetailPage(sourceID int64, statusCode int, body []byte) {
+	if s.archive == nil || statusCode != http.StatusOK || !doubanItemReviewedPattern.Match(body) {
+		return
+	}
+	if err := s.archive.Save("douban", sourceID, model.PageArchiveKindDetail, "text/html", body); err != nil {
+		s.log.Warn("归档详情页失败", zap.Int64("source_id", sourceID), zap.Error(err))
+	}
+}
+
+// archiveListItems 归档列表接口响应中的各条目（按豆瓣ID分别保存原始JSON），失败只记录日志
+func (s *DoubanSyncService) archiveListItems(ctx context.Context, body []byte) {
+	if s.archive == nil {
+		return
+	}
+	var list struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return
+	}
+	for _, raw := range list.Items {
+		var item struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			continue
+		}
+		sourceID, err := strconv.ParseInt(item.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := s.archive.Save("douban", sourceID, model.PageArchiveKindList, "application/json", raw); err != nil {
+			logger.FromContext(ctx).Warn("归档列表条目失败", zap.Int64("source_id", sourceID), zap.Error(err))
+		}
+	}
+}
+
+// archiveSearchResponse 归档匹配到播放地址的搜索响应（来源为 playurl，来源ID为视频ID），失败只记录日志
+func (s *DoubanSyncService) archiveSearchResponse(ctx context.Context, videoID int64, body []byte) {
+	if s.archive == nil {
+		return
+	}
+	if err := s.archive.Save("playurl", videoID, model.PageArchiveKindSearch, "application/json", body); err != nil {
+		logger.FromContext(ctx).Warn("归档播放地址搜索响应失败", zap.Int64("video_id", videoID), zap.Error(err))
+	}
+}
@@ -0,0 +1,308 @@
+// service 包提供业务逻辑层
+// people_service.go 提供演职人员：解析豆瓣详情页中的导演/编剧/主演链接，保存为 people 和 video_credits
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/utils"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultCreditsRetryInterval 详情页没有演职员的视频再次请求详情页的默认间隔
+const defaultCreditsRetryInterval = 30 * 24 * time.Hour
+
+// doubanPersonLinkPattern 匹配详情页中的人物链接，如：
+// <a href="/celebrity/1274297/" rel="v:directedBy">张艺谋</a>
+// <a href="https://www.douban.com/personage/27221234/" rel="v:starring">某演员</a>
+var doubanPersonLinkPattern = regexp.MustCompile(`<a[^>]*href="[^"]*/(celebrity|personage)/(\d+)/?[^"]*"[^>]*>([^<]+)</a>`)
+
+// doubanCreditLabels 详情页中的演职员标签及对应角色
+var doubanCreditLabels = []struct {
+	label string
+	role  string
+}{
+	{"导演", model.CreditRoleDirector},
+	{"编剧", model.CreditRoleWriter},
+	{"主演", model.CreditRoleActor},
+}
+
+// parsedCredit 从详情页解析出的一条演职员信息
+type parsedCredit struct {
+	Name        string
+	CelebrityID *int64
+	PersonageID *int64
+	Role        string
+	Order       int
+}
+
+// extractCredits 从详情页HTML中解析导演、编剧、主演（含豆瓣人物ID）
+// 按标签截取到行尾（<br）为止，避免主演列表中嵌套的 <span> 导致截断
+func extractCredits(html string) []parsedCredit {
+	var credits []parsedCredit
+	for _, item := range doubanCreditLabels {
+		segment := extractCreditSegment(html, item.label)
+		if segment == "" {
+			continue
+		}
+
+		matches := doubanPersonLinkPattern.FindAllStringSubmatch(segment, -1)
+		if len(matches) > 0 {
+			for i, match := range matches {
+				name := strings.TrimSpace(match[3])
+				if name == "" {
+					continue
+				}
+				id, err := strconv.ParseInt(match[2], 10, 64)
+				if err != nil {
+					continue
+				}
+				credit := parsedCredit{Name: name, Role: item.role, Order: i}
+				if match[1] == "celebrity" {
+					credit.CelebrityID = &id
+				} else {
+					credit.PersonageID = &id
+				}
+				credits = append(credits, credit)
+			}
+			continue
+		}
+
+		// 没有链接时只保存姓名
+		text := strings.TrimPrefix(strings.TrimSpace(removeHTMLTags(segment)), ":")
+		for i, name := range strings.Split(text, "/") {
+			name = strings.TrimSpace(name)
+			if name == "" || name == "更多..." {
+				continue
+			}
+			credits = append(credits, parsedCredit{Name: name, Role: item.role, Order: i})
+		}
+	}
+	return credits
+}
+
+// extractCreditSegment 截取标签之后、行尾之前的HTML片段
+func extractCreditSegment(html, label string) string {
+	start := strings.Index(html, "<span class='pl'>"+label+"</span>")
+	if start == -1 {
+		return ""
+	}
+	segment := html[start:]
+	if end := strings.Index(segment, "<br"); end != -1 {
+		segment = segment[:end]
+	}
+	// 去掉标签本身
+	if idx := strings.Index(segment, "</span>"); idx != -1 {
+		segment = segment[idx+len("</span>"):]
+	}
+	return segment
+}
+
+// PersonVideo 人物参与的一部作品
+type PersonVideo struct {
+	VideoID     int64      `json:"video_id"`
+	Title       string     `json:"title"`
+	Type        string     `json:"type"`
+	CoverURL    string     `json:"cover_url"`
+	ReleaseDate *time.Time `json:"release_date"`
+	Score       *float64   `json:"score"`
+	Roles       []string   `json:"roles"` // 担任的角色(director/writer/actor)
+}
+
+// PersonDetail 人物详情（含作品列表）
+type PersonDetail struct {
+	*model.Person
+	Filmography []*PersonVideo `json:"filmography"`
+}
+
+// PeopleService 人物服务
+type PeopleService struct {
+	repo repository.PersonRepository
+}
+
+// NewPeopleService 创建人物服务实例
+func NewPeopleService() *PeopleService {
+	return &PeopleService{
+		repo: repository.NewPersonRepository(),
+	}
+}
+
+// GetPersonDetail 查询人物详情及作品列表（按上映日期降序，只包含已发布的视频）
+func (s *PeopleService) GetPersonDetail(personID int64) (*PersonDetail, error) {
+	person, err := s.repo.FindByID(personID)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrPersonNotFound
+		}
+		zap.L().Error("查询人物失败", zap.Int64("person_id", personID), zap.Error(err))
+		return nil, errors.ErrPersonQueryFailed
+	}
+
+	credits, err := s.repo.FindFilmography(personID)
+	if err != nil {
+		zap.L().Error("查询人物作品失败", zap.Int64("person_id", personID), zap.Error(err))
+		return nil, errors.ErrPersonQueryFailed
+	}
+
+	// 同一部作品担任多个角色时合并为一条
+	filmography := make([]*PersonVideo, 0, len(credits))
+	byVideo := make(map[int64]*PersonVideo, len(credits))
+	for _, credit := range credits {
+		if item, ok := byVideo[credit.VideoID]; ok {
+			item.Roles = append(item.Roles, credit.Role)
+			continue
+		}
+		item := &PersonVideo{
+			VideoID:     credit.VideoID,
+			Title:       credit.Title,
+			Type:        credit.Type,
+			CoverURL:    credit.CoverURL,
+			ReleaseDate: credit.ReleaseDate,
+			Score:       credit.Score,
+			Roles:       []string{credit.Role},
+		}
+		byVideo[credit.VideoID] = item
+		filmography = append(filmography, item)
+	}
+
+	return &PersonDetail{Person: person, Filmography: filmography}, nil
+}
+
+// SaveCredits 保存视频的演职员（替换原有记录），人物按豆瓣ID（没有ID时按姓名）查找或创建
+func (s *PeopleService) SaveCredits(videoID int64, credits []parsedCredit) error {
+	records := make([]*model.VideoCredit, 0, len(credits))
+	seen := make(map[string]bool, len(credits))
+	for _, credit := range credits {
+		person, err := s.findOrCreatePerson(credit)
+		if err != nil {
+			return err
+		}
+
+		// 同一人物同一角色只保留第一次出现
+		key := strconv.FormatInt(person.ID, 10) + "|" + credit.Role
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		records = append(records, &model.VideoCredit{
+			VideoID:      videoID,
+			PersonID:     person.ID,
+			Role:         credit.Role,
+			BillingOrder: credit.Order,
+		})
+	}
+
+	return s.repo.ReplaceCredits(videoID, records)
+}
+
+// findOrCreatePerson 按豆瓣ID（没有ID时按姓名）查找人物，不存在则创建；豆瓣上的姓名变化时同步更新
+func (s *PeopleService) findOrCreatePerson(credit parsedCredit) (*model.Person, error) {
+	var (
+		person *model.Person
+		err    error
+	)
+	switch {
+	case credit.CelebrityID != nil:
+		person, err = s.repo.FindByDoubanID(*credit.CelebrityID)
+	case credit.PersonageID != nil:
+		person, err = s.repo.FindByDoubanPersonageID(*credit.PersonageID)
+	default:
+		person, err = s.repo.FindByNameWithoutSourceID(credit.Name)
+	}
+
+	if err == nil {
+		if person.Name != credit.Name {
+			if err := s.repo.UpdateName(person.ID, credit.Name); err != nil {
+				return nil, err
+			}
+			person.Name = credit.Name
+		}
+		return person, nil
+	}
+	if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	person = &model.Person{
+		ID:                utils.GenerateUserID(), // 使用雪花算法生成ID
+		Name:              credit.Name,
+		DoubanID:          credit.CelebrityID,
+		DoubanPersonageID: credit.PersonageID,
+	}
+	if err := s.repo.Create(person); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+// saveCredits 解析详情页中的演职员并保存（预演模式下不处理），失败只记录日志
+// 没有解析到任何演职员时保留原有记录，避免页面异常时清空
+func (s *DoubanSyncService) saveCredits(video *model.Video, html string) {
+	if s.people == nil {
+		return
+	}
+	credits := extractCredits(html)
+	if len(credits) == 0 {
+		return
+	}
+	if err := s.people.SaveCredits(video.ID, credits); err != nil {
+		s.log.Warn("保存演职员失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+	}
+}
+
+// backfillCredits 为已有详情但还没有演职员记录的视频补充演职员、别名和各地区上映日期（重新请求详情页，每次最多limit个）
+// 请求成功后记录请求时间，详情页没有演职员的视频在 sync.credits_retry_interval（默认30天）内不再重复请求，不占用后续同步的名额
+func (s *DoubanSyncService) backfillCredits(ctx context.Context, limit int) error {
+	if s.people == nil {
+		return nil
+	}
+
+	retryInterval := config.Cfg.GetDuration("sync.credits_retry_interval")
+	if retryInterval <= 0 {
+		retryInterval = defaultCreditsRetryInterval
+	}
+	videos, err := s.people.repo.FindVideosWithoutCredits(limit, time.Now().Add(-retryInterval))
+	if err != nil {
+		return err
+	}
+	if len(videos) == 0 {
+		return nil
+	}
+
+	logger.FromContext(ctx).Info("找到需要补充演职员的视频", zap.Int("count", len(videos)))
+	for _, video := range videos {
+		html, err := fetchDoubanDetailHTML(ctx, *video.SourceID, "https://movie.douban.com/")
+		if err != nil {
+			logger.FromContext(ctx).Warn("请求详情页失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+			s.observeItem(syncStageCredits, video.Type, syncItemFailed)
+			continue
+		}
+		s.archiveDetailPage(*video.SourceID, http.StatusOK, []byte(html))
+		if err := s.people.repo.MarkCreditsChecked(video.ID); err != nil {
+			logger.FromContext(ctx).Warn("记录演职员补充时间失败", zap.Int64("video_id", video.ID), zap.Error(err))
+		}
+		s.saveCredits(video, html)
+		s.observeItem(syncStageCredits, video.Type, syncItemUpdated)
+		// 同一页面中的别名和上映日期一并补充
+		s.saveTitles(video, html)
+		s.saveReleaseDates(video, html)
+
+		// 避免请求过快，休眠4秒
+		syncThrottle(4 * time.Second)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// service 包提供业务逻辑层
+// release_date_service.go 提供各地区上映日期：解析详情页中"上映日期"/"首播"的全部记录，并按地区偏好选出 videos.release_date
+package service
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/dictionary"
+	"video-service/pkg/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+// defaultReleaseRegionPreference 默认地区偏好：优先中国大陆，没有时取最早的日期
+var defaultReleaseRegionPreference = []string{"CN"}
+
+// releaseDateRegionPattern 匹配日期后的地区，如 "2025-01-07(中国大陆)"
+var releaseDateRegionPattern = regexp.MustCompile(`^(.*?)\s*[(（]([^)）]*)[)）]\s*$`)
+
+// releaseDateLabels 详情页中的日期标签及对应的日期类型
+var releaseDateLabels = []struct {
+	label string
+	kind  string
+}{
+	{`<span class="pl">上映日期:</span>`, model.ReleaseDateKindRelease},
+	{`<span class="pl">首播:</span>`, model.ReleaseDateKindPremiere},
+}
+
+// parsedReleaseDate 从详情页解析出的一条上映日期
+type parsedReleaseDate struct {
+	Date    time.Time
+	Region  string // 地区原文
+	Country string // 国家/地区规范代码（地区不是国家/地区时为空）
+}
+
+// parseReleaseDates 解析全部日期，如 "1994-09-10(多伦多电影节) / 1994-10-14(美国)"
+// 同一日期和地区重复出现时只保留一条，无法解析的日期跳过
+func parseReleaseDates(dateStr string) []parsedReleaseDate {
+	var dates []parsedReleaseDate
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(removeHTMLTags(dateStr), "/") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		region := ""
+		if m := releaseDateRegionPattern.FindStringSubmatch(part); len(m) > 2 {
+			part = m[1]
+			region = truncateRunes(strings.TrimSpace(m[2]), 64)
+		}
+
+		date := parseDateString(part)
+		if date == nil {
+			continue
+		}
+		key := date.Format("2006-01-02") + "|" + region
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		parsed := parsedReleaseDate{Date: *date, Region: region}
+		if entry, ok := dictionary.Countries.Lookup(region); ok {
+			parsed.Country = entry.Code
+		}
+		dates = append(dates, parsed)
+	}
+	return dates
+}
+
+// releaseRegionPreference 读取地区偏好配置（sync.release_region_preference），
+// 配置值可以是规范代码或任意别名（如 "中国大陆"），统一转换为规范代码
+func releaseRegionPreference() []string {
+	regions := config.Cfg.GetStringSlice("sync.release_region_preference")
+	if len(regions) == 0 {
+		return defaultReleaseRegionPreference
+	}
+	codes, unknown := dictionary.Countries.Canonicalize(regions)
+	if len(unknown) > 0 {
+		zap.L().Warn("sync.release_region_preference 中有词典中没有的地区", zap.Strings("regions", unknown))
+	}
+	return codes
+}
+
+// preferredReleaseDate 按地区偏好选出视频的上映日期：依次查找偏好地区的最早日期，都没有时取全部日期中最早的
+func preferredReleaseDate(dates []parsedReleaseDate, preference []string) *time.Time {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	for _, country := range preference {
+		var earliest *time.Time
+		for i := range dates {
+			if dates[i].Country == country && (earliest == nil || dates[i].Date.Before(*earliest)) {
+				earliest = &dates[i].Date
+			}
+		}
+		if earliest != nil {
+			date := *earliest
+			return &date
+		}
+	}
+
+	earliest := dates[0].Date
+	for _, d := range dates[1:] {
+		if d.Date.Before(earliest) {
+			earliest = d.Date
+		}
+	}
+	return &earliest
+}
+
+// deriveReleaseDate 根据"上映日期"/"首播"字段的内容选出视频的上映日期
+func (s *DoubanSyncService) deriveReleaseDate(dateStr string) *time.Time {
+	return preferredReleaseDate(parseReleaseDates(dateStr), s.releaseRegions)
+}
+
+// saveReleaseDates 保存详情页中的全部上映日期（预演模式下不处理），失败只记录日志
+// 页面中没有日期字段时保留原有记录
+func (s *DoubanSyncService) saveReleaseDates(video *model.Video, page string) {
+	if s.IsDryRun() {
+		return
+	}
+
+	var records []*model.VideoReleaseDate
+	found := false
+	for _, item := range releaseDateLabels {
+		if !strings.Contains(page, item.label) {
+			continue
+		}
+		found = true
+		for _, d := range parseReleaseDates(extractField(page, item.label, `<br`)) {
+			records = append(records, &model.VideoReleaseDate{
+				VideoID:     video.ID,
+				ReleaseDate: d.Date,
+				Region:      d.Region,
+				Country:     d.Country,
+				Kind:        item.kind,
+			})
+		}
+	}
+	if !found {
+		return
+	}
+
+	if err := s.releaseDates.ReplaceReleaseDates(video.ID, records); err != nil {
+		s.log.Warn("保存上映日期失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+	}
+}
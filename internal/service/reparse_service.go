@@ -0,0 +1,138 @@
+// service 包提供业务逻辑层
+// reparse_service.go 提供离线重新解析：使用归档的详情页重新执行当前的解析逻辑并更新视频，不发起任何网络请求
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"video-service/internal/model"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// reparseBatchSize 每批读取的归档记录数
+const reparseBatchSize = 200
+
+// ReparseReport 离线重新解析报告
+type ReparseReport struct {
+	RunID   string `json:"run_id"`  // 本次运行ID（变更记录的 sync_run_id）
+	Scanned int    `json:"scanned"` // 扫描的归档页面数
+	Updated int    `json:"updated"` // 重新解析并更新的视频数
+	Skipped int    `json:"skipped"` // 没有对应视频或类型不匹配的页面数
+	Failed  int    `json:"failed"`  // 读取归档或更新失败的页面数
+}
+
+// ReparseService 离线重新解析服务
+type ReparseService struct {
+	sync    *DoubanSyncService
+	archive *PageArchive
+}
+
+// NewReparseService 创建离线重新解析服务实例，未配置页面归档（archive.storage）时返回错误
+// 解析逻辑与同步共用，写入的字段变更记录的操作者为 reparse
+func NewReparseService() (*ReparseService, error) {
+	archive := NewPageArchive()
+	if archive == nil {
+		return nil, fmt.Errorf("未配置页面归档（archive.storage）")
+	}
+
+	runID := uuid.New().String()
+	audit := newCatalogAuditor(model.CatalogActorReparse, runID)
+	return &ReparseService{
+		sync: &DoubanSyncService{
+			runID:          runID,
+			log:            zap.L().With(zap.String("run_id", runID)),
+			videoRepo:      &auditedVideoRepository{VideoRepository: repository.NewInvalidatingVideoRepository(repository.NewVideoRepository()), audit: audit},
+			episodeRepo:    &auditedEpisodeRepository{EpisodeRepository: repository.NewInvalidatingEpisodeRepository(repository.NewEpisodeRepository()), audit: audit},
+			redirects:      repository.NewVideoRedirectRepository(),
+			titleRepo:      repository.NewVideoTitleRepository(),
+			releaseDates:   repository.NewVideoReleaseDateRepository(),
+			releaseRegions: releaseRegionPreference(),
+			people:         NewPeopleService(),
+		},
+		archive: archive,
+	}, nil
+}
+
+// Run 按归档顺序重新解析所有豆瓣详情页，videoType不为空时只处理该类型的视频
+// 只重新解析详情页：列表条目的字段（标题、封面、评分）会被详情页覆盖，播放地址搜索结果用于插入剧集，
+// 重新执行会重复插入，这两类归档只保存原始数据，供排查问题和后续手动修复使用
+func (s *ReparseService) Run(videoType string) (*ReparseReport, error) {
+	report := &ReparseReport{RunID: s.sync.runID}
+
+	var afterID int64
+	for {
+		archives, err := s.archive.repo.FindBatch("douban", model.PageArchiveKindDetail, afterID, reparseBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("查询归档记录失败: %w", err)
+		}
+		if len(archives) == 0 {
+			break
+		}
+		afterID = archives[len(archives)-1].ID
+
+		for _, archive := range archives {
+			report.Scanned++
+
+			video, err := s.sync.videoRepo.FindBySourceID(archive.SourceID)
+			if err != nil {
+				if stderrors.Is(err, gorm.ErrRecordNotFound) {
+					report.Skipped++
+					continue
+				}
+				report.Failed++
+				s.sync.log.Error("查询视频失败", zap.Int64("source_id", archive.SourceID), zap.Error(err))
+				continue
+			}
+			if videoType != "" && video.Type != videoType {
+				report.Skipped++
+				continue
+			}
+
+			body, err := s.archive.Load(archive)
+			if err != nil {
+				report.Failed++
+				s.sync.log.Error("读取归档页面失败", zap.Int64("source_id", archive.SourceID), zap.Error(err))
+				continue
+			}
+
+			ctx := logger.WithContext(context.Background(), s.sync.log.With(zap.Int64("video_id", video.ID)))
+			if err := s.sync.applyDetail(ctx, video, string(body)); err != nil {
+				report.Failed++
+				s.sync.log.Error("重新解析详情页失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+				continue
+			}
+			report.Updated++
+		}
+	}
+
+	s.sync.log.Info("离线重新解析完成",
+		zap.String("run_id", report.RunID),
+		zap.Int("scanned", report.Scanned),
+		zap.Int("updated", report.Updated),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("failed", report.Failed))
+	return report, nil
+}
+
+// applyDetail 按视频类型选择详情页解析逻辑（与同步各阶段使用的解析逻辑一致，动漫与电视相同）
+func (s *DoubanSyncService) applyDetail(ctx context.Context, video *model.Video, html string) error {
+	switch video.Type {
+	case "movie":
+		return s.applyMovieDetail(ctx, video, html)
+	case "tv", "anime":
+		return s.applyTVDetail(ctx, video, html)
+	case "tvshow":
+		return s.applyShowDetail(ctx, video, html)
+	case "doc":
+		return s.applyDocDetail(ctx, video, html)
+	default:
+		return fmt.Errorf("不支持的视频类型: %s", video.Type)
+	}
+}
@@ -0,0 +1,174 @@
+// service 包提供业务逻辑层
+// search_service.go 提供站内搜索：使用ngram全文索引按标题、别名（原名、又名）、简介、导演和演员搜索已发布的视频，并生成高亮片段
+package service
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// searchKeywordMaxLength 搜索关键词最大长度（字符数）
+const searchKeywordMaxLength = 100
+
+// searchNgramTokenSize 全文索引的ngram分词长度（MySQL ngram_token_size，默认2），更短的关键词使用模糊匹配
+const searchNgramTokenSize = 2
+
+// 高亮片段：命中位置之前和之后保留的字符数
+const (
+	snippetBefore = 20
+	snippetAfter  = 60
+)
+
+// 高亮标记
+const (
+	highlightPre  = "<em>"
+	highlightPost = "</em>"
+)
+
+// VideoSearchResult 站内搜索结果：视频摘要、相关度和高亮
+type VideoSearchResult struct {
+	*VideoSummary
+	Relevance float64          `json:"relevance"`
+	Highlight *SearchHighlight `json:"highlight"`
+}
+
+// SearchHighlight 搜索结果的高亮（已进行HTML转义，命中部分用 <em></em> 包裹）
+type SearchHighlight struct {
+	Title   string `json:"title"`   // 标题
+	Snippet string `json:"snippet"` // 简介、导演或演员中命中位置附近的片段，都没有命中时为空（如只命中别名）
+}
+
+// SearchService 站内搜索服务
+type SearchService struct {
+	repo repository.SearchRepository
+}
+
+// NewSearchService 创建站内搜索服务实例
+func NewSearchService() *SearchService {
+	return &SearchService{
+		repo: repository.NewSearchRepository(),
+	}
+}
+
+// Search 搜索已发布的视频，videoType不为空时只搜索该类型，返回当前页数据和总数
+func (s *SearchService) Search(ctx context.Context, keyword, videoType string, page, pageSize int) ([]*VideoSearchResult, int64, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil, 0, errors.ErrSearchKeywordRequired
+	}
+	keyword = truncateRunes(keyword, searchKeywordMaxLength)
+
+	search := s.repo.SearchPublished
+	if utf8.RuneCountInString(keyword) < searchNgramTokenSize {
+		search = s.repo.SearchPublishedByLike
+	}
+	hits, total, err := search(keyword, videoType, page, pageSize)
+	if err != nil {
+		logger.FromContext(ctx).Error("搜索视频失败", zap.String("keyword", keyword), zap.String("type", videoType), zap.Error(err))
+		return nil, 0, errors.ErrSearchFailed
+	}
+
+	results := make([]*VideoSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, &VideoSearchResult{
+			VideoSummary: newVideoSummary(&hit.Video),
+			Relevance:    hit.Relevance,
+			Highlight:    newSearchHighlight(hit, keyword),
+		})
+	}
+	return results, total, nil
+}
+
+// newSearchHighlight 生成标题高亮和片段，片段依次取简介、导演、演员中第一个命中的位置
+func newSearchHighlight(hit *repository.SearchHit, keyword string) *SearchHighlight {
+	highlight := &SearchHighlight{Title: highlightText(hit.Title, keyword)}
+
+	candidates := []string{hit.Description}
+	if directors := decodeStringArray(hit.DirectorJSON); len(directors) > 0 {
+		candidates = append(candidates, "导演: "+strings.Join(directors, " / "))
+	}
+	if actors := decodeStringArray(hit.ActorsJSON); len(actors) > 0 {
+		candidates = append(candidates, "主演: "+strings.Join(actors, " / "))
+	}
+	for _, text := range candidates {
+		if snippet, ok := snippetText(text, keyword); ok {
+			highlight.Snippet = snippet
+			break
+		}
+	}
+	return highlight
+}
+
+// snippetText 截取文本中第一个命中位置附近的片段并高亮，没有命中时返回false
+func snippetText(text, keyword string) (string, bool) {
+	runes := []rune(text)
+	index := indexFold(runes, []rune(keyword), 0)
+	if index < 0 {
+		return "", false
+	}
+
+	start := index - snippetBefore
+	if start < 0 {
+		start = 0
+	}
+	end := index + utf8.RuneCountInString(keyword) + snippetAfter
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	snippet := highlightText(string(runes[start:end]), keyword)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet, true
+}
+
+// highlightText 对文本进行HTML转义，并用高亮标记包裹所有命中的关键词（不区分大小写）
+func highlightText(text, keyword string) string {
+	runes := []rune(text)
+	pattern := []rune(keyword)
+
+	var b strings.Builder
+	last := 0
+	for index := indexFold(runes, pattern, 0); index >= 0; index = indexFold(runes, pattern, last) {
+		b.WriteString(html.EscapeString(string(runes[last:index])))
+		b.WriteString(highlightPre)
+		b.WriteString(html.EscapeString(string(runes[index : index+len(pattern)])))
+		b.WriteString(highlightPost)
+		last = index + len(pattern)
+	}
+	b.WriteString(html.EscapeString(string(runes[last:])))
+	return b.String()
+}
+
+// indexFold 从from开始查找pattern在runes中第一次出现的位置（不区分大小写），没有时返回-1
+func indexFold(runes, pattern []rune, from int) int {
+	if len(pattern) == 0 {
+		return -1
+	}
+	for i := from; i+len(pattern) <= len(runes); i++ {
+		matched := true
+		for j, r := range pattern {
+			if unicode.ToLower(runes[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,402 @@
+// service 包提供业务逻辑层
+// series_service.go 提供多季剧集的系列关联：同步时根据标题中的季数标记自动关联，管理员可手动覆盖
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/pkg/utils"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 标题中的季数标记，如 "XX 第二季"、"XX 第2季：副标题"、"XX Season 3"
+var (
+	chineseSeasonPattern = regexp.MustCompile(`第([0-9零〇一二两三四五六七八九十]+)季`)
+	englishSeasonPattern = regexp.MustCompile(`(?i)\bseason\s*([0-9]+)\b`)
+)
+
+// seriesTitleTrimChars 截取系列标题时需要去掉的首尾分隔符
+const seriesTitleTrimChars = " \t　:：·-—_（()）"
+
+// ParseSeasonTitle 解析标题中的季数标记
+// 返回去掉季数标记后的系列标题和季数，标题中没有季数标记时 ok 为 false
+func ParseSeasonTitle(title string) (seriesTitle string, seasonNumber int64, ok bool) {
+	for _, pattern := range []*regexp.Regexp{chineseSeasonPattern, englishSeasonPattern} {
+		loc := pattern.FindStringSubmatchIndex(title)
+		if loc == nil {
+			continue
+		}
+		number, valid := parseSeasonNumber(title[loc[2]:loc[3]])
+		if !valid {
+			continue
+		}
+		// 系列标题取季数标记之前的部分（标记之后通常是副标题）
+		base := strings.Trim(title[:loc[0]], seriesTitleTrimChars)
+		if base == "" {
+			continue
+		}
+		return base, number, true
+	}
+	return "", 0, false
+}
+
+// parseSeasonNumber 将阿拉伯数字或中文数字（一至九十九）转换为季数
+func parseSeasonNumber(s string) (int64, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, n > 0
+	}
+
+	digits := map[rune]int64{
+		'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4,
+		'五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+	}
+	var total, current int64
+	for _, r := range s {
+		if r == '十' {
+			if current == 0 {
+				current = 1
+			}
+			total += current * 10
+			current = 0
+			continue
+		}
+		d, ok := digits[r]
+		if !ok {
+			return 0, false
+		}
+		current = d
+	}
+	total += current
+	return total, total > 0
+}
+
+// SeriesSeason 系列中的一季
+type SeriesSeason struct {
+	VideoID      int64      `json:"video_id"`
+	SeasonNumber *int64     `json:"season_number"`
+	Title        string     `json:"title"`
+	CoverURL     string     `json:"cover_url"`
+	ReleaseDate  *time.Time `json:"release_date"`
+	Score        *float64   `json:"score"`
+	EpisodeCount *int64     `json:"episode_count"`
+	IsCompleted  bool       `json:"is_completed"`
+}
+
+// SeriesDetail 系列详情（含按季数排序的各季列表）
+type SeriesDetail struct {
+	*model.Series
+	Seasons []*SeriesSeason `json:"seasons"`
+}
+
+// SetVideoSeriesRequest 管理员手动设置视频系列关联的请求参数
+// SeriesID 和 SeriesTitle 必须指定其一；指定 SeriesTitle 时按标题和视频类型查找系列，不存在则创建
+type SetVideoSeriesRequest struct {
+	SeriesID     *int64 `json:"series_id"`
+	SeriesTitle  string `json:"series_title"`
+	SeasonNumber int64  `json:"season_number"`
+}
+
+// SeriesService 系列服务
+type SeriesService struct {
+	seriesRepo repository.SeriesRepository
+	videoRepo  repository.VideoRepository
+	audit      *catalogAuditor // 系列关联变更记录（同步中使用同步的记录器）
+}
+
+// NewSeriesService 创建系列服务实例
+func NewSeriesService() *SeriesService {
+	return &SeriesService{
+		seriesRepo: repository.NewSeriesRepository(),
+		videoRepo:  repository.NewVideoRepository(),
+		audit:      newCatalogAuditor(model.CatalogActorSystem, ""),
+	}
+}
+
+// WithActor 设置变更记录的操作者（管理员为 "admin:用户名"）并返回自身
+func (s *SeriesService) WithActor(actor string) *SeriesService {
+	s.audit = newCatalogAuditor(actor, "")
+	return s
+}
+
+// GetSeriesDetail 查询系列详情，各季按季数升序排列（只返回已发布的视频）
+func (s *SeriesService) GetSeriesDetail(ctx context.Context, seriesID int64) (*SeriesDetail, error) {
+	series, err := s.seriesRepo.FindByID(seriesID)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrSeriesNotFound
+		}
+		logger.FromContext(ctx).Error("查询系列失败", zap.Int64("series_id", seriesID), zap.Error(err))
+		return nil, errors.ErrSeriesQueryFailed
+	}
+
+	videos, err := s.seriesRepo.FindSeasons(seriesID)
+	if err != nil {
+		logger.FromContext(ctx).Error("查询系列各季失败", zap.Int64("series_id", seriesID), zap.Error(err))
+		return nil, errors.ErrSeriesQueryFailed
+	}
+
+	seasons := make([]*SeriesSeason, 0, len(videos))
+	for _, video := range videos {
+		if video.Status != "1" {
+			continue
+		}
+		seasons = append(seasons, &SeriesSeason{
+			VideoID:      video.ID,
+			SeasonNumber: video.SeasonNumber,
+			Title:        video.Title,
+			CoverURL:     video.CoverURL,
+			ReleaseDate:  video.ReleaseDate,
+			Score:        video.Score,
+			EpisodeCount: video.EpisodeCount,
+			IsCompleted:  video.IsCompleted,
+		})
+	}
+
+	return &SeriesDetail{Series: series, Seasons: seasons}, nil
+}
+
+// AutoLink 根据标题中的季数标记自动关联系列（同步新建视频后调用）
+// 规则：
+//  1. 已关联或已被管理员锁定的视频不做处理
+//  2. 标题带季数标记（如 "XX 第二季"）：按系列标题和类型查找或创建系列并关联，
+//     同时将标题恰好为系列标题的未关联视频（通常是没有标记的第一季）关联为第1季
+//  3. 标题不带季数标记：若已存在同名同类型的系列且第1季空缺，则关联为第1季
+//
+// 返回视频是否被关联
+func (s *SeriesService) AutoLink(ctx context.Context, video *model.Video) (bool, error) {
+	if video.SeriesLocked || video.SeriesID != nil {
+		return false, nil
+	}
+
+	seriesTitle, seasonNumber, ok := ParseSeasonTitle(video.Title)
+	if !ok {
+		return s.linkAsFirstSeason(ctx, video)
+	}
+
+	series, err := s.findOrCreateSeries(ctx, seriesTitle, video)
+	if err != nil {
+		return false, err
+	}
+	if err := s.linkVideo(video, &series.ID, &seasonNumber, false); err != nil {
+		return false, err
+	}
+	logger.FromContext(ctx).Info("视频已关联系列",
+		zap.Int64("video_id", video.ID),
+		zap.String("title", video.Title),
+		zap.Int64("series_id", series.ID),
+		zap.Int64("season_number", seasonNumber))
+
+	// 没有季数标记的第一季
+	if seasonNumber != 1 {
+		first, err := s.seriesRepo.FindUnlinkedVideoByTitle(seriesTitle, video.Type)
+		if err == nil {
+			if _, err := s.linkAsFirstSeason(ctx, first); err != nil {
+				logger.FromContext(ctx).Warn("关联第一季失败", zap.Int64("video_id", first.ID), zap.Error(err))
+			}
+		} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+			logger.FromContext(ctx).Warn("查询第一季失败", zap.String("series_title", seriesTitle), zap.Error(err))
+		}
+	}
+
+	return true, nil
+}
+
+// linkAsFirstSeason 将不带季数标记的视频关联为同名系列的第1季（系列不存在或第1季已存在时不处理）
+func (s *SeriesService) linkAsFirstSeason(ctx context.Context, video *model.Video) (bool, error) {
+	series, err := s.seriesRepo.FindByTitleAndType(video.Title, video.Type)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	exists, err := s.seriesRepo.ExistsSeason(series.ID, 1)
+	if err != nil || exists {
+		return false, err
+	}
+
+	seasonNumber := int64(1)
+	if err := s.linkVideo(video, &series.ID, &seasonNumber, false); err != nil {
+		return false, err
+	}
+	logger.FromContext(ctx).Info("视频已关联为系列第一季",
+		zap.Int64("video_id", video.ID),
+		zap.String("title", video.Title),
+		zap.Int64("series_id", series.ID))
+	return true, nil
+}
+
+// findOrCreateSeries 按标题和视频类型查找系列，不存在则以该视频的封面和简介创建
+func (s *SeriesService) findOrCreateSeries(ctx context.Context, title string, video *model.Video) (*model.Series, error) {
+	series, err := s.seriesRepo.FindByTitleAndType(title, video.Type)
+	if err == nil {
+		return series, nil
+	}
+	if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	series = &model.Series{
+		ID:          utils.GenerateUserID(), // 使用雪花算法生成ID
+		Title:       title,
+		Type:        video.Type,
+		CoverURL:    video.CoverURL,
+		Description: video.Description,
+	}
+	if err := s.seriesRepo.Create(series); err != nil {
+		// 并发同步时可能已被其他任务创建（唯一索引冲突），重新查询一次
+		if existing, findErr := s.seriesRepo.FindByTitleAndType(title, video.Type); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	logger.FromContext(ctx).Info("创建系列", zap.Int64("series_id", series.ID), zap.String("title", title), zap.String("type", video.Type))
+	return series, nil
+}
+
+// RelinkAll 对所有未关联且未锁定的视频执行自动关联（用于存量数据回填），返回新关联的视频数
+// 先处理带季数标记的视频以建立系列，再处理不带标记的视频（可能是第一季）
+func (s *SeriesService) RelinkAll(ctx context.Context) (int, error) {
+	videos, err := s.seriesRepo.FindUnlinkedVideos()
+	if err != nil {
+		return 0, err
+	}
+
+	var marked, unmarked []*model.Video
+	for _, video := range videos {
+		if _, _, ok := ParseSeasonTitle(video.Title); ok {
+			marked = append(marked, video)
+		} else {
+			unmarked = append(unmarked, video)
+		}
+	}
+
+	linked := 0
+	for _, video := range append(marked, unmarked...) {
+		// 处理带标记的视频时可能已顺带关联了第一季
+		if video.SeriesID != nil {
+			continue
+		}
+		ok, err := s.AutoLink(ctx, video)
+		if err != nil {
+			logger.FromContext(ctx).Warn("自动关联系列失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+			continue
+		}
+		if ok {
+			linked++
+		}
+	}
+
+	logger.FromContext(ctx).Info("系列关联回填完成", zap.Int("candidates", len(videos)), zap.Int("linked", linked))
+	return linked, nil
+}
+
+// SetVideoSeries 管理员手动设置视频的系列和季数，设置后锁定，同步不再自动修改
+func (s *SeriesService) SetVideoSeries(ctx context.Context, videoID int64, req *SetVideoSeriesRequest) (*model.Video, error) {
+	if req.SeasonNumber <= 0 {
+		return nil, errors.ErrSeasonNumberInvalid
+	}
+	if req.SeriesID == nil && strings.TrimSpace(req.SeriesTitle) == "" {
+		return nil, errors.ErrSeriesTargetRequired
+	}
+
+	video, err := s.findVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var series *model.Series
+	if req.SeriesID != nil {
+		series, err = s.seriesRepo.FindByID(*req.SeriesID)
+		if err != nil {
+			if stderrors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.ErrSeriesNotFound
+			}
+			logger.FromContext(ctx).Error("查询系列失败", zap.Int64("series_id", *req.SeriesID), zap.Error(err))
+			return nil, errors.ErrSeriesQueryFailed
+		}
+	} else {
+		series, err = s.findOrCreateSeries(ctx, strings.TrimSpace(req.SeriesTitle), video)
+		if err != nil {
+			logger.FromContext(ctx).Error("查找或创建系列失败", zap.String("series_title", req.SeriesTitle), zap.Error(err))
+			return nil, errors.ErrSeriesUpdateFailed
+		}
+	}
+
+	seasonNumber := req.SeasonNumber
+	if err := s.linkVideo(video, &series.ID, &seasonNumber, true); err != nil {
+		logger.FromContext(ctx).Error("更新系列关联失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return nil, errors.ErrSeriesUpdateFailed
+	}
+	return video, nil
+}
+
+// UnlinkVideoSeries 管理员取消视频的系列关联，并锁定以防同步重新关联
+func (s *SeriesService) UnlinkVideoSeries(ctx context.Context, videoID int64) (*model.Video, error) {
+	video, err := s.findVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.linkVideo(video, nil, nil, true); err != nil {
+		logger.FromContext(ctx).Error("取消系列关联失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return nil, errors.ErrSeriesUpdateFailed
+	}
+	return video, nil
+}
+
+// UnlockVideoSeries 管理员解除锁定，并立即按标题重新自动关联
+func (s *SeriesService) UnlockVideoSeries(ctx context.Context, videoID int64) (*model.Video, error) {
+	video, err := s.findVideo(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.linkVideo(video, nil, nil, false); err != nil {
+		logger.FromContext(ctx).Error("解除系列锁定失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return nil, errors.ErrSeriesUpdateFailed
+	}
+
+	if _, err := s.AutoLink(ctx, video); err != nil {
+		logger.FromContext(ctx).Warn("自动关联系列失败", zap.Int64("video_id", videoID), zap.Error(err))
+	}
+	return video, nil
+}
+
+// linkVideo 更新视频的系列关联并记录变更，成功后同步修改video的对应字段
+func (s *SeriesService) linkVideo(video *model.Video, seriesID, seasonNumber *int64, locked bool) error {
+	if err := s.seriesRepo.LinkVideo(video.ID, seriesID, seasonNumber, locked); err != nil {
+		return err
+	}
+	before := cloneVideo(video)
+	video.SeriesID = seriesID
+	video.SeasonNumber = seasonNumber
+	video.SeriesLocked = locked
+	s.audit.recordVideoDiff(before, video)
+	return nil
+}
+
+// findVideo 查询视频，不存在时返回 ErrVideoNotFound
+func (s *SeriesService) findVideo(ctx context.Context, videoID int64) (*model.Video, error) {
+	video, err := s.videoRepo.FindByID(videoID)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrVideoNotFound
+		}
+		logger.FromContext(ctx).Error("查询视频失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return nil, errors.ErrInternalError
+	}
+	return video, nil
+}
@@ -0,0 +1,433 @@
+// service 包提供业务逻辑层
+// suggest_service.go 提供搜索建议：为已发布视频的标题和别名建立前缀索引（原文、全拼、首字母），保存在Redis中，
+// 遥控器输入拼音首字母（如 "kb" 匹配 "狂飙"）即可得到候选视频
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/cache"
+	"video-service/pkg/infrastructure/logger"
+
+	"github.com/mozillazg/go-pinyin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Redis键
+// suggest:terms 为有序集合，所有成员分数为0，成员为 "词条\x00视频ID"，通过 ZRANGEBYLEX 做前缀查询；
+// suggest:video:{id} 为哈希，保存候选项展示的字段和该视频的全部词条（用于更新和删除）
+const (
+	suggestTermsKey       = "suggest:terms"
+	suggestVideoKeyPrefix = "suggest:video:"
+	suggestMemberSep      = "\x00"
+	suggestTermsSep       = "\x00"
+)
+
+// 搜索建议参数
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 20
+	suggestCandidates   = 200 // 前缀匹配最多读取的词条数（再按视频去重、按评分排序）
+	suggestTermMaxRunes = 64  // 词条最大长度（字符数）
+	suggestBatchSize    = 500 // 重建索引时每批读取的视频数
+)
+
+// pinyinArgs 拼音转换参数（不带声调，多音字取第一个读音）
+var pinyinArgs = pinyin.NewArgs()
+
+// SuggestItem 搜索建议候选项
+type SuggestItem struct {
+	ID       int64    `json:"id"`
+	Title    string   `json:"title"`
+	Type     string   `json:"type"`
+	CoverURL string   `json:"cover_url"`
+	Score    *float64 `json:"score"`
+}
+
+// SuggestService 搜索建议服务
+type SuggestService struct {
+	rdb  *redis.Client
+	repo repository.SuggestRepository
+}
+
+// NewSuggestService 创建搜索建议服务实例，未配置Redis（redis.addr）时返回nil
+func NewSuggestService() *SuggestService {
+	if cache.Rdb == nil {
+		return nil
+	}
+	return &SuggestService{
+		rdb:  cache.Rdb,
+		repo: repository.NewSuggestRepository(),
+	}
+}
+
+// Suggest 按前缀返回候选视频（按评分降序），q可以是标题原文、全拼或拼音首字母，不区分大小写、忽略空格和标点
+func (s *SuggestService) Suggest(ctx context.Context, q string, limit int) ([]*SuggestItem, error) {
+	prefix := normalizeSuggestText(q)
+	if prefix == "" {
+		return nil, errors.ErrSearchKeywordRequired
+	}
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	members, err := s.rdb.ZRangeByLex(ctx, suggestTermsKey, &redis.ZRangeBy{
+		Min:   "[" + prefix,
+		Max:   "[" + prefix + "\xff",
+		Count: suggestCandidates,
+	}).Result()
+	if err != nil {
+		logger.FromContext(ctx).Error("查询搜索建议失败", zap.String("q", q), zap.Error(err))
+		return nil, errors.ErrSearchFailed
+	}
+
+	// 同一视频的多个词条可能同时命中，按视频去重
+	var videoIDs []string
+	seen := make(map[string]bool)
+	for _, member := range members {
+		i := strings.LastIndex(member, suggestMemberSep)
+		if i < 0 {
+			continue
+		}
+		id := member[i+len(suggestMemberSep):]
+		if !seen[id] {
+			seen[id] = true
+			videoIDs = append(videoIDs, id)
+		}
+	}
+	if len(videoIDs) == 0 {
+		return []*SuggestItem{}, nil
+	}
+
+	cmds := make([]*redis.SliceCmd, len(videoIDs))
+	_, err = s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range videoIDs {
+			cmds[i] = pipe.HMGet(ctx, suggestVideoKeyPrefix+id, "title", "type", "cover_url", "score")
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("查询搜索建议候选视频失败", zap.String("q", q), zap.Error(err))
+		return nil, errors.ErrSearchFailed
+	}
+
+	items := make([]*SuggestItem, 0, len(videoIDs))
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		title, _ := values[0].(string)
+		if title == "" {
+			continue // 词条已删除但视频信息已不存在
+		}
+		item := &SuggestItem{Title: title}
+		item.ID, _ = strconv.ParseInt(videoIDs[i], 10, 64)
+		item.Type, _ = values[1].(string)
+		item.CoverURL, _ = values[2].(string)
+		if score, ok := values[3].(string); ok && score != "" {
+			if v, err := strconv.ParseFloat(score, 64); err == nil {
+				item.Score = &v
+			}
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return scoreValue(items[i].Score) > scoreValue(items[j].Score)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// IndexUpdatedSince 更新 updated_at 不早于since的视频的索引：已发布的重新建立，其他的删除
+func (s *SuggestService) IndexUpdatedSince(since time.Time) error {
+	var afterID int64
+	for {
+		videos, err := s.repo.FindVideosBatch(&since, false, afterID, suggestBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(videos) == 0 {
+			return nil
+		}
+		afterID = videos[len(videos)-1].ID
+		if err := s.indexVideos(videos); err != nil {
+			return err
+		}
+	}
+}
+
+// IndexVideoIDs 更新指定视频的索引：已发布的重新建立，未发布或已删除的删除
+func (s *SuggestService) IndexVideoIDs(videoIDs []int64) error {
+	videos, err := s.repo.FindVideosByIDs(videoIDs)
+	if err != nil {
+		return err
+	}
+	found := make(map[int64]bool, len(videos))
+	for _, video := range videos {
+		found[video.ID] = true
+	}
+	var missing []int64
+	for _, id := range videoIDs {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if err := s.RemoveVideos(missing); err != nil {
+		return err
+	}
+	return s.indexVideos(videos)
+}
+
+// RemoveVideos 删除指定视频的索引
+func (s *SuggestService) RemoveVideos(videoIDs []int64) error {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	oldTerms, err := s.loadTerms(ctx, videoIDs)
+	if err != nil {
+		return err
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range videoIDs {
+			removeSuggestEntry(ctx, pipe, id, oldTerms[id])
+		}
+		return nil
+	})
+	return err
+}
+
+// Rebuild 清空索引后为全部已发布视频重新建立，返回建立索引的视频数
+// 重建期间搜索建议可能不完整
+func (s *SuggestService) Rebuild() (int, error) {
+	ctx := context.Background()
+	if err := s.clear(ctx); err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	var afterID int64
+	for {
+		videos, err := s.repo.FindVideosBatch(nil, true, afterID, suggestBatchSize)
+		if err != nil {
+			return indexed, err
+		}
+		if len(videos) == 0 {
+			break
+		}
+		afterID = videos[len(videos)-1].ID
+		if err := s.indexVideos(videos); err != nil {
+			return indexed, err
+		}
+		indexed += len(videos)
+	}
+	zap.L().Info("搜索建议索引已重建", zap.Int("videos", indexed))
+	return indexed, nil
+}
+
+// indexVideos 为一批视频更新索引：先删除旧词条，已发布的再写入新词条和展示字段
+func (s *SuggestService) indexVideos(videos []*model.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+
+	videoIDs := make([]int64, 0, len(videos))
+	for _, video := range videos {
+		videoIDs = append(videoIDs, video.ID)
+	}
+	titles, err := s.repo.FindTitlesByVideoIDs(videoIDs)
+	if err != nil {
+		return err
+	}
+	oldTerms, err := s.loadTerms(ctx, videoIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, video := range videos {
+			removeSuggestEntry(ctx, pipe, video.ID, oldTerms[video.ID])
+			if video.Status != "1" {
+				continue
+			}
+
+			terms := videoSuggestTerms(video.Title, titles[video.ID])
+			if len(terms) == 0 {
+				continue
+			}
+			id := strconv.FormatInt(video.ID, 10)
+			members := make([]redis.Z, 0, len(terms))
+			for _, term := range terms {
+				members = append(members, redis.Z{Member: term + suggestMemberSep + id})
+			}
+			pipe.ZAdd(ctx, suggestTermsKey, members...)
+
+			fields := map[string]interface{}{
+				"title":     video.Title,
+				"type":      video.Type,
+				"cover_url": video.CoverURL,
+				"score":     "",
+				"terms":     strings.Join(terms, suggestTermsSep),
+			}
+			if video.Score != nil {
+				fields["score"] = strconv.FormatFloat(*video.Score, 'f', -1, 64)
+			}
+			pipe.HSet(ctx, suggestVideoKeyPrefix+id, fields)
+		}
+		return nil
+	})
+	return err
+}
+
+// loadTerms 读取视频当前已建立索引的词条
+func (s *SuggestService) loadTerms(ctx context.Context, videoIDs []int64) (map[int64][]string, error) {
+	cmds := make([]*redis.StringCmd, len(videoIDs))
+	_, err := s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range videoIDs {
+			cmds[i] = pipe.HGet(ctx, suggestVideoKeyPrefix+strconv.FormatInt(id, 10), "terms")
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	terms := make(map[int64][]string, len(videoIDs))
+	for i, cmd := range cmds {
+		if value := cmd.Val(); value != "" {
+			terms[videoIDs[i]] = strings.Split(value, suggestTermsSep)
+		}
+	}
+	return terms, nil
+}
+
+// clear 删除全部词条和视频信息
+func (s *SuggestService) clear(ctx context.Context) error {
+	if err := s.rdb.Del(ctx, suggestTermsKey).Err(); err != nil {
+		return err
+	}
+	iter := s.rdb.Scan(ctx, 0, suggestVideoKeyPrefix+"*", 1000).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) >= 1000 {
+			if err := s.rdb.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return s.rdb.Del(ctx, keys...).Err()
+	}
+	return nil
+}
+
+// removeSuggestEntry 在管道中删除视频的词条和视频信息
+func removeSuggestEntry(ctx context.Context, pipe redis.Pipeliner, videoID int64, terms []string) {
+	id := strconv.FormatInt(videoID, 10)
+	if len(terms) > 0 {
+		members := make([]interface{}, 0, len(terms))
+		for _, term := range terms {
+			members = append(members, term+suggestMemberSep+id)
+		}
+		pipe.ZRem(ctx, suggestTermsKey, members...)
+	}
+	pipe.Del(ctx, suggestVideoKeyPrefix+id)
+}
+
+// videoSuggestTerms 生成视频标题和别名的全部词条（去重）
+func videoSuggestTerms(title string, alternates []string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, text := range append([]string{title}, alternates...) {
+		for _, term := range suggestTerms(text) {
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// suggestTerms 生成文本的词条：原文、全拼、首字母（均为小写，去掉空格和标点）
+// 例如 "狂飙2" 生成 "狂飙2"、"kuangbiao2"、"kb2"；"Breaking Bad" 生成 "breakingbad"、"bb"
+func suggestTerms(text string) []string {
+	var plain, full, initials strings.Builder
+	inWord := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			inWord = false
+			plain.WriteRune(r)
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 && py[0] != "" {
+				full.WriteString(py[0])
+				initials.WriteByte(py[0][0])
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			plain.WriteRune(r)
+			full.WriteRune(r)
+			// 字母取每个单词的首字母，数字全部保留
+			if unicode.IsDigit(r) || !inWord {
+				initials.WriteRune(r)
+			}
+			inWord = unicode.IsLetter(r)
+		default:
+			inWord = false
+		}
+	}
+
+	var terms []string
+	for _, term := range []string{plain.String(), full.String(), initials.String()} {
+		term = truncateRunes(term, suggestTermMaxRunes)
+		if term != "" && !containsString(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// normalizeSuggestText 规范化查询文本：小写，只保留字母、数字和汉字
+func normalizeSuggestText(q string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(q) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return truncateRunes(b.String(), suggestTermMaxRunes)
+}
+
+// scoreValue 解引用评分，nil视为-1（排在有评分的视频之后）
+func scoreValue(score *float64) float64 {
+	if score == nil {
+		return -1
+	}
+	return *score
+}
+
+// refreshSuggestions 更新本次同步中变化的视频的搜索建议索引（预演模式或未配置Redis时跳过）
+func (s *DoubanSyncService) refreshSuggestions(since time.Time) error {
+	if s.suggest == nil {
+		return nil
+	}
+	return s.suggest.IndexUpdatedSince(since)
+}
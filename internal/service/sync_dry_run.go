@@ -0,0 +1,699 @@
+// service 包提供业务逻辑层
+// sync_dry_run.go 提供同步预演（dry-run）模式：照常请求和解析数据，但将仓库写操作路由到记录器，生成变更预览报告
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// DryRunReport 预演变更预览报告
+type DryRunReport struct {
+	RunID            string               `json:"run_id"`                // 同步运行ID
+	GeneratedAt      time.Time            `json:"generated_at"`          // 报告生成时间
+	Summary          DryRunSummary        `json:"summary"`               // 变更数量汇总
+	CreatedVideos    []*DryRunVideo       `json:"would_create_videos"`   // 将要新建的视频
+	UpdatedVideos    []*DryRunVideoUpdate `json:"would_update_videos"`   // 将要更新的视频（字段前后值）
+	InsertedEpisodes []*DryRunEpisode     `json:"would_insert_episodes"` // 将要插入的剧集
+}
+
+// DryRunSummary 预演变更数量汇总
+type DryRunSummary struct {
+	CreatedVideos    int `json:"created_videos"`
+	UpdatedVideos    int `json:"updated_videos"`
+	InsertedEpisodes int `json:"inserted_episodes"`
+}
+
+// DryRunVideo 将要新建的视频（包含同步结束时的最终字段值）
+type DryRunVideo struct {
+	ID       int64                  `json:"id"`
+	SourceID *int64                 `json:"source_id"`
+	Source   string                 `json:"source"`
+	Title    string                 `json:"title"`
+	Type     string                 `json:"type"`
+	Fields   map[string]interface{} `json:"fields"`
+}
+
+// DryRunVideoUpdate 将要更新的视频
+type DryRunVideoUpdate struct {
+	VideoID int64                `json:"video_id"`
+	Title   string               `json:"title"`
+	Type    string               `json:"type"`
+	Changes []*DryRunFieldChange `json:"changes"`
+}
+
+// DryRunFieldChange 单个字段的变更（before/after）
+type DryRunFieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DryRunEpisode 将要插入的剧集
+type DryRunEpisode struct {
+	VideoID       int64  `json:"video_id"`
+	VideoTitle    string `json:"video_title"`
+	EpisodeNumber *int64 `json:"episode_number"`
+	Channel       string `json:"channel"`
+	Name          string `json:"name"`
+	PlayURL       string `json:"play_url"`
+	NewVideo      bool   `json:"new_video"` // 所属视频是否也是本次预演中新建的
+}
+
+// dryRunField 视频字段快照项
+type dryRunField struct {
+	name  string
+	value interface{}
+}
+
+// videoFieldValues 返回参与预演对比的视频字段（按固定顺序）
+// updated_at/created_at 由数据库自动维护，不参与对比
+func videoFieldValues(v *model.Video) []dryRunField {
+	return []dryRunField{
+		{"title", v.Title},
+		{"type", v.Type},
+		{"cover_url", v.CoverURL},
+		{"description", v.Description},
+		{"release_date", dateValue(v.ReleaseDate)},
+		{"score", float64Value(v.Score)},
+		{"country_json", jsonValue(v.CountryJSON)},
+		{"director_json", jsonValue(v.DirectorJSON)},
+		{"actors_json", jsonValue(v.ActorsJSON)},
+		{"tags_json", jsonValue(v.TagsJSON)},
+		{"status", v.Status},
+		{"imdb_id", v.IMDbID},
+		{"runtime", int64Value(v.Runtime)},
+		{"resolution", v.Resolution},
+		{"episode_count", int64Value(v.EpisodeCount)},
+		{"is_completed", v.IsCompleted},
+		{"is_update", v.IsUpdate},
+		{"series_id", int64Value(v.SeriesID)},
+		{"season_number", int64Value(v.SeasonNumber)},
+		{"series_locked", v.SeriesLocked},
+		{"cover_source_url", v.CoverSourceURL},
+		{"cover_thumbs_json", jsonValue(v.CoverThumbsJSON)},
+	}
+}
+
+// videoFieldMap 以字段名为键返回参与对比的视频字段
+func videoFieldMap(v *model.Video) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for _, f := range videoFieldValues(v) {
+		fields[f.name] = f.value
+	}
+	return fields
+}
+
+// applyVideoDetails 将src的详情字段复制到dst（字段范围与真实仓库的 UpdateDetails 一致）
+func applyVideoDetails(dst, src *model.Video) {
+	dst.Description = src.Description
+	dst.ReleaseDate = src.ReleaseDate
+	dst.CountryJSON = src.CountryJSON
+	dst.DirectorJSON = src.DirectorJSON
+	dst.ActorsJSON = src.ActorsJSON
+	dst.TagsJSON = src.TagsJSON
+	dst.IMDbID = src.IMDbID
+	dst.Runtime = src.Runtime
+	dst.Score = src.Score
+	dst.EpisodeCount = src.EpisodeCount
+	dst.UpdatedAt = src.UpdatedAt
+}
+
+// dateValue 将日期格式化为 YYYY-MM-DD，nil 返回 nil
+func dateValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+// float64Value 解引用浮点指针，nil 返回 nil
+func float64Value(f *float64) interface{} {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
+
+// int64Value 解引用整数指针，nil 返回 nil
+func int64Value(i *int64) interface{} {
+	if i == nil {
+		return nil
+	}
+	return *i
+}
+
+// jsonValue 将JSON列解码为通用结构，便于在报告中直接展示数组
+func jsonValue(data datatypes.JSON) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	return v
+}
+
+// isEmptyJSONArray 判断JSON列是否为空（NULL 或 []），与仓库层SQL条件保持一致
+func isEmptyJSONArray(data datatypes.JSON) bool {
+	return len(data) == 0 || string(data) == "[]" || string(data) == "null"
+}
+
+// cloneVideo 复制视频记录，避免调用方后续修改影响记录器中的快照
+func cloneVideo(v *model.Video) *model.Video {
+	c := *v
+	return &c
+}
+
+// dryRunVideoState 单个视频在预演中的状态
+// before 为 nil 表示该视频是本次预演中新建的
+type dryRunVideoState struct {
+	before  *model.Video
+	current *model.Video
+}
+
+// dryRunRecorder 预演记录器
+// 读操作先查询真实仓库，再叠加本次预演中已记录的写操作，保证后续阶段能“看到”前面阶段的变更
+type dryRunRecorder struct {
+	runID        string
+	log          *zap.Logger // 带 run_id 的日志记录器
+	baseVideos   repository.VideoRepository
+	baseEpisodes repository.EpisodeRepository
+
+	mu         sync.Mutex
+	videos     map[int64]*dryRunVideoState
+	videoOrder []int64
+	episodes   []*model.Episode
+}
+
+// newDryRunRecorder 创建预演记录器
+func newDryRunRecorder(runID string, videoRepo repository.VideoRepository, episodeRepo repository.EpisodeRepository) *dryRunRecorder {
+	return &dryRunRecorder{
+		runID:        runID,
+		log:          zap.L().With(zap.String("run_id", runID)),
+		baseVideos:   videoRepo,
+		baseEpisodes: episodeRepo,
+		videos:       make(map[int64]*dryRunVideoState),
+	}
+}
+
+// loadState 获取视频的预演状态，不存在时从真实仓库加载（调用方需持有锁）
+func (r *dryRunRecorder) loadState(videoID int64) (*dryRunVideoState, error) {
+	if st, ok := r.videos[videoID]; ok {
+		return st, nil
+	}
+	video, err := r.baseVideos.FindByID(videoID)
+	if err != nil {
+		return nil, err
+	}
+	st := &dryRunVideoState{before: cloneVideo(video), current: cloneVideo(video)}
+	r.videos[videoID] = st
+	r.videoOrder = append(r.videoOrder, videoID)
+	return st, nil
+}
+
+// overlay 用预演状态覆盖真实仓库返回的视频，并按条件过滤，再追加满足条件的新建视频（调用方需持有锁）
+func (r *dryRunRecorder) overlay(base []*model.Video, match func(v *model.Video) bool, limit int) []*model.Video {
+	result := make([]*model.Video, 0, len(base))
+	seen := make(map[int64]bool, len(base))
+	for _, v := range base {
+		if st, ok := r.videos[v.ID]; ok {
+			v = cloneVideo(st.current)
+		}
+		seen[v.ID] = true
+		if match(v) {
+			result = append(result, v)
+		}
+	}
+	for _, id := range r.videoOrder {
+		st := r.videos[id]
+		if st.before != nil || seen[id] {
+			continue
+		}
+		if match(st.current) {
+			result = append(result, cloneVideo(st.current))
+		}
+	}
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// pendingEpisodes 返回指定视频在预演中记录的剧集（调用方需持有锁）
+func (r *dryRunRecorder) pendingEpisodes(videoID int64) []*model.Episode {
+	var episodes []*model.Episode
+	for _, ep := range r.episodes {
+		if ep.VideoID == videoID {
+			episodes = append(episodes, ep)
+		}
+	}
+	return episodes
+}
+
+// Report 生成变更预览报告
+func (r *dryRunRecorder) Report() *DryRunReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &DryRunReport{
+		RunID:            r.runID,
+		GeneratedAt:      time.Now(),
+		CreatedVideos:    []*DryRunVideo{},
+		UpdatedVideos:    []*DryRunVideoUpdate{},
+		InsertedEpisodes: []*DryRunEpisode{},
+	}
+
+	for _, id := range r.videoOrder {
+		st := r.videos[id]
+		cur := st.current
+
+		// 新建的视频：输出最终字段值
+		if st.before == nil {
+			report.CreatedVideos = append(report.CreatedVideos, &DryRunVideo{
+				ID:       cur.ID,
+				SourceID: cur.SourceID,
+				Source:   cur.Source,
+				Title:    cur.Title,
+				Type:     cur.Type,
+				Fields:   videoFieldMap(cur),
+			})
+			continue
+		}
+
+		// 已存在的视频：逐字段对比前后值，只输出有变化的字段
+		beforeFields := videoFieldValues(st.before)
+		afterFields := videoFieldValues(cur)
+		var changes []*DryRunFieldChange
+		for i := range beforeFields {
+			if reflect.DeepEqual(beforeFields[i].value, afterFields[i].value) {
+				continue
+			}
+			changes = append(changes, &DryRunFieldChange{
+				Field:  beforeFields[i].name,
+				Before: beforeFields[i].value,
+				After:  afterFields[i].value,
+			})
+		}
+		if len(changes) > 0 {
+			report.UpdatedVideos = append(report.UpdatedVideos, &DryRunVideoUpdate{
+				VideoID: cur.ID,
+				Title:   cur.Title,
+				Type:    cur.Type,
+				Changes: changes,
+			})
+		}
+	}
+
+	for _, ep := range r.episodes {
+		item := &DryRunEpisode{
+			VideoID:       ep.VideoID,
+			VideoTitle:    ep.Name,
+			EpisodeNumber: ep.EpisodeNumber,
+			Channel:       ep.Channel,
+			Name:          ep.Name,
+			PlayURL:       ep.PlayURLs,
+		}
+		if st, ok := r.videos[ep.VideoID]; ok {
+			item.VideoTitle = st.current.Title
+			item.NewVideo = st.before == nil
+		}
+		report.InsertedEpisodes = append(report.InsertedEpisodes, item)
+	}
+
+	report.Summary = DryRunSummary{
+		CreatedVideos:    len(report.CreatedVideos),
+		UpdatedVideos:    len(report.UpdatedVideos),
+		InsertedEpisodes: len(report.InsertedEpisodes),
+	}
+	return report
+}
+
+// dryRunVideoRepository 预演模式下的视频仓库，实现 repository.VideoRepository
+type dryRunVideoRepository struct {
+	rec *dryRunRecorder
+}
+
+// FindBySourceID 根据来源ID查找视频（包含预演中新建的视频）
+func (d *dryRunVideoRepository) FindBySourceID(sourceID int64) (*model.Video, error) {
+	d.rec.mu.Lock()
+	for _, id := range d.rec.videoOrder {
+		cur := d.rec.videos[id].current
+		if cur.SourceID != nil && *cur.SourceID == sourceID {
+			d.rec.mu.Unlock()
+			return cloneVideo(cur), nil
+		}
+	}
+	d.rec.mu.Unlock()
+	return d.rec.baseVideos.FindBySourceID(sourceID)
+}
+
+// Create 记录将要新建的视频
+func (d *dryRunVideoRepository) Create(video *model.Video) error {
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	d.rec.videos[video.ID] = &dryRunVideoState{current: cloneVideo(video)}
+	d.rec.videoOrder = append(d.rec.videoOrder, video.ID)
+	d.rec.log.Info("[dry-run] 将新建视频", zap.String("title", video.Title), zap.String("type", video.Type))
+	return nil
+}
+
+// Update 记录视频的全字段更新
+func (d *dryRunVideoRepository) Update(video *model.Video) error {
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	st, err := d.rec.loadState(video.ID)
+	if err != nil {
+		return err
+	}
+	st.current = cloneVideo(video)
+	return nil
+}
+
+// UpdateDetails 记录视频详情字段的更新（字段范围与真实仓库的 UpdateDetails 一致）
+func (d *dryRunVideoRepository) UpdateDetails(video *model.Video) error {
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	st, err := d.rec.loadState(video.ID)
+	if err != nil {
+		return err
+	}
+	applyVideoDetails(st.current, video)
+	return nil
+}
+
+// FindNeedDetailVideos 查找需要补充详情的视频（叠加预演状态）
+func (d *dryRunVideoRepository) FindNeedDetailVideos(limit int) ([]*model.Video, error) {
+	base, err := d.rec.baseVideos.FindNeedDetailVideos(limit)
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return d.rec.overlay(base, needDetail, limit), nil
+}
+
+// FindNeedDetailVideosByType 根据类型查找需要补充详情的视频（叠加预演状态）
+func (d *dryRunVideoRepository) FindNeedDetailVideosByType(videoType string, limit int) ([]*model.Video, error) {
+	base, err := d.rec.baseVideos.FindNeedDetailVideosByType(videoType, limit)
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return d.rec.overlay(base, func(v *model.Video) bool {
+		return needDetail(v) && v.Type == videoType && v.ReleaseDate == nil
+	}, limit), nil
+}
+
+// FindAllVideos 查找所有视频（叠加预演状态）
+func (d *dryRunVideoRepository) FindAllVideos() ([]*model.Video, error) {
+	base, err := d.rec.baseVideos.FindAllVideos()
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return d.rec.overlay(base, func(*model.Video) bool { return true }, 0), nil
+}
+
+// FindVideosByStatusNotEqual 查找 status 不等于指定值的视频（叠加预演状态）
+func (d *dryRunVideoRepository) FindVideosByStatusNotEqual(status string) ([]*model.Video, error) {
+	base, err := d.rec.baseVideos.FindVideosByStatusNotEqual(status)
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return d.rec.overlay(base, func(v *model.Video) bool { return v.Status != status }, 0), nil
+}
+
+// FindVideosNeedUpdateEpisodes 查找需要更新episodes的视频（叠加预演状态）
+func (d *dryRunVideoRepository) FindVideosNeedUpdateEpisodes() ([]*model.Video, error) {
+	base, err := d.rec.baseVideos.FindVideosNeedUpdateEpisodes()
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return d.rec.overlay(base, func(v *model.Video) bool {
+		return v.Status != "0" && !v.IsCompleted
+	}, 0), nil
+}
+
+// FindVideosWithEpisodesByStatusNotEqual 查找存在 episodes 记录且 status 不等于指定值的视频
+// 除真实仓库的结果外，还包含只在预演中插入了剧集的视频
+func (d *dryRunVideoRepository) FindVideosWithEpisodesByStatusNotEqual(status string) ([]*model.Video, error) {
+	base, err := d.rec.baseVideos.FindVideosWithEpisodesByStatusNotEqual(status)
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+
+	result := d.rec.overlay(base, func(v *model.Video) bool { return v.Status != status }, 0)
+	seen := make(map[int64]bool, len(result))
+	for _, v := range result {
+		seen[v.ID] = true
+	}
+	for _, ep := range d.rec.episodes {
+		if seen[ep.VideoID] {
+			continue
+		}
+		seen[ep.VideoID] = true
+		st, err := d.rec.loadState(ep.VideoID)
+		if err != nil {
+			continue
+		}
+		if st.current.Status != status {
+			result = append(result, cloneVideo(st.current))
+		}
+	}
+	return result, nil
+}
+
+// UpdateVideosStatusByEpisodes 记录存在 episodes 记录的 videos 的 status 更新
+func (d *dryRunVideoRepository) UpdateVideosStatusByEpisodes(status string) error {
+	videos, err := d.FindVideosWithEpisodesByStatusNotEqual(status)
+	if err != nil {
+		return err
+	}
+	for _, v := range videos {
+		if err := d.UpdateVideoStatus(v.ID, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateVideoStatus 记录视频status的更新
+func (d *dryRunVideoRepository) UpdateVideoStatus(videoID int64, status string) error {
+	return d.update(videoID, func(v *model.Video) { v.Status = status })
+}
+
+// UpdateVideoIsUpdate 记录视频is_update的更新
+func (d *dryRunVideoRepository) UpdateVideoIsUpdate(videoID int64, isUpdate bool) error {
+	return d.update(videoID, func(v *model.Video) { v.IsUpdate = isUpdate })
+}
+
+// UpdateVideoIsCompleted 记录视频is_completed的更新
+func (d *dryRunVideoRepository) UpdateVideoIsCompleted(videoID int64, isCompleted bool) error {
+	return d.update(videoID, func(v *model.Video) { v.IsCompleted = isCompleted })
+}
+
+// TouchUpdatedAt updated_at 不参与预演对比，只校验视频存在
+func (d *dryRunVideoRepository) TouchUpdatedAt(videoID int64) error {
+	return d.update(videoID, func(*model.Video) {})
+}
+
+// FindByID 根据ID查找视频（优先返回预演状态）
+func (d *dryRunVideoRepository) FindByID(videoID int64) (*model.Video, error) {
+	d.rec.mu.Lock()
+	if st, ok := d.rec.videos[videoID]; ok {
+		d.rec.mu.Unlock()
+		return cloneVideo(st.current), nil
+	}
+	d.rec.mu.Unlock()
+	return d.rec.baseVideos.FindByID(videoID)
+}
+
+// update 对视频的预演状态应用修改
+func (d *dryRunVideoRepository) update(videoID int64, apply func(v *model.Video)) error {
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	st, err := d.rec.loadState(videoID)
+	if err != nil {
+		return err
+	}
+	apply(st.current)
+	return nil
+}
+
+// needDetail 与 FindNeedDetailVideos 的SQL条件一致：source_id不为空且country_json为空
+func needDetail(v *model.Video) bool {
+	return v.SourceID != nil && *v.SourceID != 0 && isEmptyJSONArray(v.CountryJSON)
+}
+
+// dryRunEpisodeRepository 预演模式下的剧集仓库，实现 repository.EpisodeRepository
+type dryRunEpisodeRepository struct {
+	rec *dryRunRecorder
+}
+
+// Create 记录将要插入的剧集
+func (d *dryRunEpisodeRepository) Create(episode *model.Episode) error {
+	c := *episode
+	if c.CreatedAt == nil {
+		now := time.Now()
+		c.CreatedAt = &now
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	d.rec.episodes = append(d.rec.episodes, &c)
+	return nil
+}
+
+// FindByVideoID 根据视频ID查找所有剧集（真实剧集 + 预演中插入的剧集）
+func (d *dryRunEpisodeRepository) FindByVideoID(videoID int64) ([]*model.Episode, error) {
+	episodes, err := d.rec.baseEpisodes.FindByVideoID(videoID)
+	if err != nil {
+		return nil, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return append(episodes, d.rec.pendingEpisodes(videoID)...), nil
+}
+
+// CountByVideoID 根据视频ID统计episode数量（真实剧集 + 预演中插入的剧集）
+func (d *dryRunEpisodeRepository) CountByVideoID(videoID int64) (int64, error) {
+	count, err := d.rec.baseEpisodes.CountByVideoID(videoID)
+	if err != nil {
+		return 0, err
+	}
+	d.rec.mu.Lock()
+	defer d.rec.mu.Unlock()
+	return count + int64(len(d.rec.pendingEpisodes(videoID))), nil
+}
+
+// FindLastByVideoID 根据视频ID查找最后一条episode记录（优先返回预演中最后插入的剧集）
+func (d *dryRunEpisodeRepository) FindLastByVideoID(videoID int64) (*model.Episode, error) {
+	d.rec.mu.Lock()
+	pending := d.rec.pendingEpisodes(videoID)
+	d.rec.mu.Unlock()
+	if len(pending) > 0 {
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].CreatedAt.After(*pending[j].CreatedAt)
+		})
+		c := *pending[0]
+		return &c, nil
+	}
+	episode, err := d.rec.baseEpisodes.FindLastByVideoID(videoID)
+	if err != nil {
+		return nil, err
+	}
+	return episode, nil
+}
+
+// ExistsByVideoID 检查视频ID是否存在episode记录（包含预演中插入的剧集）
+func (d *dryRunEpisodeRepository) ExistsByVideoID(videoID int64) (bool, error) {
+	count, err := d.CountByVideoID(videoID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DryRunRun 一次预演任务的执行状态
+type DryRunRun struct {
+	RunID      string        `json:"run_id"`
+	Status     string        `json:"status"` // running/finished/failed
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Report     *DryRunReport `json:"report,omitempty"`
+}
+
+// 预演任务状态
+const (
+	DryRunStatusRunning  = "running"
+	DryRunStatusFinished = "finished"
+	DryRunStatusFailed   = "failed"
+)
+
+// maxKeptDryRuns 内存中最多保留的预演任务数量，超过后淘汰最早的任务
+const maxKeptDryRuns = 20
+
+var (
+	dryRunsMu    sync.Mutex
+	dryRuns      = make(map[string]*DryRunRun)
+	dryRunsOrder []string
+)
+
+// StartDoubanDryRun 在后台启动一次豆瓣同步预演，立即返回任务状态
+// 执行结果可通过 GetDoubanDryRun 按 run_id 查询
+func StartDoubanDryRun() *DryRunRun {
+	svc := NewDoubanSyncDryRunService()
+	run := &DryRunRun{
+		RunID:     svc.RunID(),
+		Status:    DryRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+
+	dryRunsMu.Lock()
+	dryRuns[run.RunID] = run
+	dryRunsOrder = append(dryRunsOrder, run.RunID)
+	if len(dryRunsOrder) > maxKeptDryRuns {
+		delete(dryRuns, dryRunsOrder[0])
+		dryRunsOrder = dryRunsOrder[1:]
+	}
+	snapshot := *run
+	dryRunsMu.Unlock()
+
+	go func() {
+		err := svc.SyncAll()
+		report := svc.DryRunReport()
+		now := time.Now()
+
+		dryRunsMu.Lock()
+		defer dryRunsMu.Unlock()
+		run.FinishedAt = &now
+		run.Report = report
+		if err != nil {
+			run.Status = DryRunStatusFailed
+			run.Error = err.Error()
+			zap.L().Error("豆瓣同步预演失败", zap.String("run_id", run.RunID), zap.Error(err))
+			return
+		}
+		run.Status = DryRunStatusFinished
+		zap.L().Info("豆瓣同步预演完成", zap.String("run_id", run.RunID), zap.Any("summary", report.Summary))
+	}()
+
+	return &snapshot
+}
+
+// GetDoubanDryRun 按 run_id 查询预演任务
+func GetDoubanDryRun(runID string) (*DryRunRun, bool) {
+	dryRunsMu.Lock()
+	defer dryRunsMu.Unlock()
+	run, ok := dryRuns[runID]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *run
+	return &snapshot, true
+}
+
+// 确保预演仓库实现了仓库接口
+var (
+	_ repository.VideoRepository   = (*dryRunVideoRepository)(nil)
+	_ repository.EpisodeRepository = (*dryRunEpisodeRepository)(nil)
+)
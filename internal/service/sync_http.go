@@ -0,0 +1,96 @@
+// service 包提供业务逻辑层
+// sync_http.go 提供同步使用的HTTP客户端和请求间隔：记录上游请求指标和span，并按配置启用夹具录制/回放，使整个同步流程可以离线运行
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/httpfixture"
+	"video-service/pkg/infrastructure/metrics"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
+)
+
+// defaultSyncFixturesDir 夹具目录默认值
+const defaultSyncFixturesDir = "./testdata/fixtures/sync"
+
+var (
+	syncFixturesOnce     sync.Once
+	syncFixturesRecorder *httpfixture.Recorder // 未配置 sync.http_fixtures.mode 时为nil
+)
+
+// syncFixtures 返回同步使用的夹具录制/回放器（进程内共享，首次调用时按配置创建），未启用时返回nil
+// 配置项 sync.http_fixtures.mode：record 录制 / replay 回放，sync.http_fixtures.dir 为夹具目录
+func syncFixtures() *httpfixture.Recorder {
+	syncFixturesOnce.Do(func() {
+		mode := config.Cfg.GetString("sync.http_fixtures.mode")
+		if mode == "" {
+			return
+		}
+		dir := config.Cfg.GetString("sync.http_fixtures.dir")
+		if dir == "" {
+			dir = defaultSyncFixturesDir
+		}
+		rec, err := httpfixture.NewRecorder(mode, dir)
+		if err != nil {
+			// 配置了回放却无法使用夹具时不能退回到真实请求
+			zap.L().Fatal("初始化HTTP夹具失败", zap.String("mode", mode), zap.String("dir", dir), zap.Error(err))
+		}
+		zap.L().Info("同步HTTP请求使用夹具", zap.String("mode", mode), zap.String("dir", dir))
+		syncFixturesRecorder = rec
+	})
+	return syncFixturesRecorder
+}
+
+// newSyncHTTPClient 创建同步请求外部站点使用的HTTP客户端
+// source/endpoint 为上游请求指标的来源和接口标签，base 为实际发起请求的传输层（nil时使用默认传输层）；
+// 每次请求创建span并通过 traceparent 请求头传播（请求需使用 http.NewRequestWithContext 携带父span），
+// 启用夹具时请求经过录制/回放传输层
+func newSyncHTTPClient(source, endpoint string, timeout time.Duration, base http.RoundTripper) *http.Client {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if rec := syncFixtures(); rec != nil {
+		base = rec.Transport(base)
+	}
+	var transport http.RoundTripper = &upstreamMetricsTransport{source: source, endpoint: endpoint, base: base}
+	transport = otelhttp.NewTransport(transport,
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return "HTTP " + req.Method + " " + source + "/" + endpoint
+		}))
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// upstreamMetricsTransport 记录上游请求数和耗时指标的传输层
+type upstreamMetricsTransport struct {
+	source   string
+	endpoint string
+	base     http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *upstreamMetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	metrics.SyncUpstreamRequestDuration.WithLabelValues(t.source, t.endpoint).Observe(time.Since(start).Seconds())
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.SyncUpstreamRequestsTotal.WithLabelValues(t.source, t.endpoint, status).Inc()
+	return resp, err
+}
+
+// syncThrottle 两次请求之间休眠，避免请求过快；回放夹具时不发起真实请求，不休眠
+func syncThrottle(d time.Duration) {
+	if rec := syncFixtures(); rec != nil && rec.Replaying() {
+		return
+	}
+	time.Sleep(d)
+}
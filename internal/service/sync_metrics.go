@@ -0,0 +1,108 @@
+// service 包提供业务逻辑层
+// sync_metrics.go 提供同步流程的Prometheus业务指标（各阶段处理的条目数和耗时、最近一次成功同步时间、播放地址匹配情况）和追踪span
+// 预演模式不写入数据，除上游请求外不记录指标，避免干扰告警
+package service
+
+import (
+	"context"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/logger"
+	"video-service/pkg/infrastructure/metrics"
+	"video-service/pkg/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 同步阶段（指标 stage 标签）
+const (
+	syncStageList    = "list"    // 获取列表并保存新视频
+	syncStageDetail  = "detail"  // 补充详情
+	syncStageCredits = "credits" // 为旧数据补充演职员
+	syncStagePlayURL = "playurl" // 搜索播放地址并保存剧集
+	syncStagePublish = "publish" // 发布存在剧集的视频
+	syncStageCovers  = "covers"  // 镜像封面
+	syncStageFilters = "filters" // 重新统计筛选项
+	syncStageSuggest = "suggest" // 更新搜索建议索引
+	syncStageHome    = "home"    // 清除首页推荐位缓存
+)
+
+// 条目处理结果（指标 result 标签）
+const (
+	syncItemSaved   = "saved"
+	syncItemUpdated = "updated"
+	syncItemSkipped = "skipped"
+	syncItemFailed  = "failed"
+)
+
+// 播放地址搜索结果（指标 result 标签）
+const (
+	playURLSearchMatched   = "matched"   // 搜索结果中有与标题或别名相同的条目
+	playURLSearchUnmatched = "unmatched" // 搜索成功但没有匹配的条目
+	playURLSearchFailed    = "failed"    // 搜索请求失败
+)
+
+// observeItems 记录阶段处理的条目数
+func (s *DoubanSyncService) observeItems(stage, videoType, result string, n int) {
+	if s.dryRun != nil || n <= 0 {
+		return
+	}
+	metrics.SyncItemsTotal.WithLabelValues(stage, videoType, result).Add(float64(n))
+}
+
+// observeItem 记录阶段处理的一个条目
+func (s *DoubanSyncService) observeItem(stage, videoType, result string) {
+	s.observeItems(stage, videoType, result, 1)
+}
+
+// observePlayURLSearch 记录一次播放地址搜索的匹配结果
+func (s *DoubanSyncService) observePlayURLSearch(videoType, result string) {
+	if s.dryRun != nil {
+		return
+	}
+	metrics.SyncPlayURLSearchesTotal.WithLabelValues(videoType, result).Inc()
+}
+
+// runStage 执行同步阶段，记录耗时并创建该阶段的span，videoType 为空表示该阶段处理所有类型
+func (s *DoubanSyncService) runStage(ctx context.Context, stage, videoType string, fn func(ctx context.Context) error) error {
+	ctx, span := tracing.Tracer().Start(ctx, "sync."+stage, trace.WithAttributes(
+		attribute.String("sync.stage", stage),
+		attribute.String("video.type", videoType),
+	))
+	defer span.End()
+	ctx, _ = logger.With(ctx, zap.String("stage", stage))
+
+	start := time.Now()
+	err := fn(ctx)
+	if s.dryRun == nil {
+		metrics.SyncStageDuration.WithLabelValues(stage, videoType).Observe(time.Since(start).Seconds())
+	}
+	tracing.RecordError(span, err)
+	return err
+}
+
+// traceVideo 在单个视频的span中执行处理函数（详情、播放地址等按视频的任务）
+func (s *DoubanSyncService) traceVideo(ctx context.Context, name string, video *model.Video, fn func(ctx context.Context, video *model.Video) error) error {
+	ctx, span := tracing.Tracer().Start(ctx, name, trace.WithAttributes(
+		attribute.Int64("video.id", video.ID),
+		attribute.String("video.title", video.Title),
+		attribute.String("video.type", video.Type),
+	))
+	defer span.End()
+	ctx, _ = logger.With(ctx, zap.Int64("video_id", video.ID))
+
+	err := fn(ctx, video)
+	tracing.RecordError(span, err)
+	return err
+}
+
+// markSyncSucceeded 记录同步成功完成的时间
+func (s *DoubanSyncService) markSyncSucceeded() {
+	if s.dryRun != nil {
+		return
+	}
+	metrics.SyncLastSuccessTimestamp.SetToCurrentTime()
+}
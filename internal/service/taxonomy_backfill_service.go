@@ -0,0 +1,127 @@
+// service 包提供业务逻辑层
+// taxonomy_backfill_service.go 提供存量数据的国家/地区和类型标签规范化回填（按词典将已有的 country_json/tags_json 转换为规范代码）
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/dictionary"
+	"video-service/internal/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// taxonomyBackfillBatchSize 每批处理的视频数
+const taxonomyBackfillBatchSize = 500
+
+// TaxonomyBackfillReport 规范化回填报告
+type TaxonomyBackfillReport struct {
+	DryRun           bool           `json:"dry_run"`
+	Scanned          int            `json:"scanned"`           // 扫描的视频数
+	Updated          int            `json:"updated"`           // 需要（预演模式）或已经更新的视频数
+	Failed           int            `json:"failed"`            // 更新失败的视频数
+	UnknownCountries map[string]int `json:"unknown_countries"` // 词典中没有的国家/地区及出现次数，用于补充词典
+	UnknownGenres    map[string]int `json:"unknown_genres"`    // 词典中没有的类型及出现次数，用于补充词典
+}
+
+// TaxonomyBackfillService 国家/地区和类型标签规范化回填服务
+type TaxonomyBackfillService struct {
+	repo  repository.VideoTaxonomyRepository
+	audit *catalogAuditor
+}
+
+// NewTaxonomyBackfillService 创建规范化回填服务实例
+func NewTaxonomyBackfillService() *TaxonomyBackfillService {
+	return &TaxonomyBackfillService{
+		repo:  repository.NewVideoTaxonomyRepository(),
+		audit: newCatalogAuditor(model.CatalogActorSystem, ""),
+	}
+}
+
+// Run 按ID顺序分批扫描所有视频，将 country_json/tags_json 转换为规范代码
+// dryRun为true时只统计需要更新的视频，不修改数据
+func (s *TaxonomyBackfillService) Run(dryRun bool) (*TaxonomyBackfillReport, error) {
+	report := &TaxonomyBackfillReport{
+		DryRun:           dryRun,
+		UnknownCountries: make(map[string]int),
+		UnknownGenres:    make(map[string]int),
+	}
+
+	var afterID int64
+	for {
+		videos, err := s.repo.FindBatch(afterID, taxonomyBackfillBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("查询视频失败: %w", err)
+		}
+		if len(videos) == 0 {
+			break
+		}
+		afterID = videos[len(videos)-1].ID
+
+		for _, video := range videos {
+			report.Scanned++
+
+			countryJSON, countryChanged := canonicalizeJSONArray(video.CountryJSON, dictionary.Countries, report.UnknownCountries)
+			tagsJSON, tagsChanged := canonicalizeJSONArray(video.TagsJSON, dictionary.Genres, report.UnknownGenres)
+			if !countryChanged && !tagsChanged {
+				continue
+			}
+
+			if dryRun {
+				report.Updated++
+				continue
+			}
+			if err := s.repo.UpdateTaxonomy(video.ID, countryJSON, tagsJSON); err != nil {
+				report.Failed++
+				zap.L().Error("更新视频国家/地区和类型失败", zap.Int64("video_id", video.ID), zap.Error(err))
+				continue
+			}
+			s.audit.recordVideoField(video.ID, "country_json", jsonValue(video.CountryJSON), jsonValue(countryJSON))
+			s.audit.recordVideoField(video.ID, "tags_json", jsonValue(video.TagsJSON), jsonValue(tagsJSON))
+			report.Updated++
+		}
+	}
+
+	zap.L().Info("国家/地区和类型规范化完成",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("scanned", report.Scanned),
+		zap.Int("updated", report.Updated),
+		zap.Int("failed", report.Failed),
+		zap.Int("unknown_countries", len(report.UnknownCountries)),
+		zap.Int("unknown_genres", len(report.UnknownGenres)))
+	return report, nil
+}
+
+// canonicalizeJSONArray 按词典规范化JSON数组列，返回新值及是否有变化；无法解析的值保持不变
+// 词典中没有的取值计入unknown
+func canonicalizeJSONArray(data datatypes.JSON, dict *dictionary.Dictionary, unknown map[string]int) (datatypes.JSON, bool) {
+	if isEmptyJSONArray(data) {
+		return data, false
+	}
+
+	var items []string
+	if err := json.Unmarshal(data, &items); err != nil {
+		return data, false
+	}
+
+	codes, unknownValues := dict.Canonicalize(items)
+	for _, value := range unknownValues {
+		unknown[value]++
+	}
+
+	canonical, err := json.Marshal(codes)
+	if err != nil {
+		return data, false
+	}
+	canonical = truncateJSONArray(canonical)
+
+	// 与原值的规范序列化结果比较，避免仅因空白差异而更新
+	original, err := json.Marshal(items)
+	if err != nil || string(original) == string(canonical) {
+		return data, false
+	}
+	return canonical, true
+}
@@ -0,0 +1,469 @@
+// service 包提供业务逻辑层
+// video_dedup_service.go 提供跨来源视频去重：按IMDb ID（或标题+年份+导演）聚类，将重复视频合并到规范视频
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 去重合并依据
+const (
+	DedupReasonIMDb              = "imdb"                // 相同的IMDb ID（同一季）
+	DedupReasonTitleYearDirector = "title_year_director" // 没有IMDb ID时，标准化标题+上映年份+导演相同
+)
+
+// DedupCluster 一组重复视频
+type DedupCluster struct {
+	Reason       string   `json:"reason"`        // 合并依据
+	Key          string   `json:"key"`           // 聚类键（IMDb ID 或 标题|年份|导演）
+	CanonicalID  int64    `json:"canonical_id"`  // 保留的规范视频ID
+	DuplicateIDs []int64  `json:"duplicate_ids"` // 将被合并删除的视频ID
+	Titles       []string `json:"titles"`        // 组内视频标题（规范视频在前）
+	Error        string   `json:"error,omitempty"`
+}
+
+// DedupReport 去重运行报告
+type DedupReport struct {
+	DryRun       bool            `json:"dry_run"`
+	StartedAt    time.Time       `json:"started_at"`
+	FinishedAt   time.Time       `json:"finished_at"`
+	Candidates   int             `json:"candidates"`    // 参与去重的视频数
+	MergedVideos int             `json:"merged_videos"` // 被合并删除的视频数
+	FailedGroups int             `json:"failed_groups"` // 合并失败的组数
+	Clusters     []*DedupCluster `json:"clusters"`
+}
+
+// VideoDedupService 视频去重服务
+type VideoDedupService struct {
+	mergeRepo    repository.VideoMergeRepository
+	redirectRepo repository.VideoRedirectRepository
+	videoRepo    repository.VideoRepository
+	webhooks     *WebhookService
+	suggest      *SuggestService // 搜索建议索引，未配置Redis时为nil
+	home         *HomeService
+	audit        *catalogAuditor
+}
+
+// NewVideoDedupService 创建视频去重服务实例
+func NewVideoDedupService() *VideoDedupService {
+	return &VideoDedupService{
+		mergeRepo:    repository.NewVideoMergeRepository(),
+		redirectRepo: repository.NewVideoRedirectRepository(),
+		videoRepo:    repository.NewVideoRepository(),
+		webhooks:     NewWebhookService(),
+		suggest:      NewSuggestService(),
+		home:         NewHomeService(),
+		audit:        newCatalogAuditor(model.CatalogActorDedup, ""),
+	}
+}
+
+// Run 执行一次去重
+// dryRun 为 true 时只计算重复组并返回报告，不修改数据库
+func (s *VideoDedupService) Run(dryRun bool) (*DedupReport, error) {
+	report := &DedupReport{DryRun: dryRun, StartedAt: time.Now(), Clusters: []*DedupCluster{}}
+
+	videos, err := s.mergeRepo.FindDedupCandidates()
+	if err != nil {
+		return nil, fmt.Errorf("查询去重候选视频失败: %w", err)
+	}
+	report.Candidates = len(videos)
+
+	for _, group := range clusterDuplicateVideos(videos) {
+		canonical, duplicates := pickCanonicalVideo(group.videos)
+
+		cluster := &DedupCluster{
+			Reason:      group.reason,
+			Key:         group.key,
+			CanonicalID: canonical.ID,
+			Titles:      []string{canonical.Title},
+		}
+		for _, dup := range duplicates {
+			cluster.DuplicateIDs = append(cluster.DuplicateIDs, dup.ID)
+			cluster.Titles = append(cluster.Titles, dup.Title)
+		}
+		report.Clusters = append(report.Clusters, cluster)
+
+		if dryRun {
+			continue
+		}
+
+		before := cloneVideo(canonical)
+		for _, dup := range duplicates {
+			mergeVideoFields(canonical, dup)
+		}
+		result, err := s.mergeRepo.Merge(canonical, mergedVideoColumns(before, canonical), duplicates, group.reason)
+		if err != nil {
+			report.FailedGroups++
+			cluster.Error = err.Error()
+			zap.L().Error("合并重复视频失败",
+				zap.Int64("canonical_id", canonical.ID),
+				zap.Int64s("duplicate_ids", cluster.DuplicateIDs),
+				zap.Error(err))
+			continue
+		}
+		report.MergedVideos += len(duplicates)
+		s.recordMerge(before, canonical, duplicates, result)
+		zap.L().Info("合并重复视频",
+			zap.String("reason", group.reason),
+			zap.String("key", group.key),
+			zap.Int64("canonical_id", canonical.ID),
+			zap.Int64s("duplicate_ids", cluster.DuplicateIDs))
+
+		s.updateSuggestions(append([]int64{canonical.ID}, cluster.DuplicateIDs...))
+
+		s.webhooks.Publish(context.Background(), EventVideoMerged, map[string]interface{}{
+			"video_id":      canonical.ID,
+			"title":         canonical.Title,
+			"type":          canonical.Type,
+			"reason":        group.reason,
+			"duplicate_ids": cluster.DuplicateIDs,
+		})
+	}
+
+	// 被合并的视频可能出现在首页推荐位中
+	if report.MergedVideos > 0 {
+		if err := s.home.Invalidate(context.Background()); err != nil {
+			zap.L().Warn("清除首页推荐位缓存失败", zap.Error(err))
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	zap.L().Info("视频去重完成",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("candidates", report.Candidates),
+		zap.Int("clusters", len(report.Clusters)),
+		zap.Int("merged_videos", report.MergedVideos),
+		zap.Int("failed_groups", report.FailedGroups))
+	return report, nil
+}
+
+// updateSuggestions 合并后更新规范视频的搜索建议索引并删除重复视频的索引，失败只记录日志
+func (s *VideoDedupService) updateSuggestions(videoIDs []int64) {
+	if s.suggest == nil {
+		return
+	}
+	if err := s.suggest.IndexVideoIDs(videoIDs); err != nil {
+		zap.L().Warn("更新搜索建议索引失败", zap.Int64s("video_ids", videoIDs), zap.Error(err))
+	}
+}
+
+// recordMerge 记录一次合并的目录变更：规范视频补全的字段、改挂和删除的剧集、删除的重复视频
+// 所属视频均为规范视频，在规范视频的历史中可以看到完整的合并过程
+func (s *VideoDedupService) recordMerge(before, canonical *model.Video, duplicates []*model.Video, result *repository.VideoMergeResult) {
+	s.audit.recordVideoDiff(before, canonical)
+
+	var changes []*model.CatalogChange
+	for _, ep := range result.MovedEpisodes {
+		changes = append(changes, s.audit.newChange(model.CatalogEntityEpisode, ep.ID, canonical.ID, "video_id", ep.VideoID, canonical.ID))
+	}
+	for _, ep := range result.DeletedEpisodes {
+		snapshot := episodeFieldMap(ep)
+		snapshot["video_id"] = ep.VideoID
+		changes = append(changes, s.audit.newChange(model.CatalogEntityEpisode, ep.ID, canonical.ID, model.CatalogFieldDeleted, snapshot, nil))
+	}
+	for _, dup := range duplicates {
+		snapshot := videoFieldMap(dup)
+		snapshot["source"] = dup.Source
+		snapshot["source_id"] = int64Value(dup.SourceID)
+		changes = append(changes, s.audit.newChange(model.CatalogEntityVideo, dup.ID, canonical.ID, model.CatalogFieldDeleted, snapshot, nil))
+	}
+	s.audit.save(changes)
+}
+
+// ResolveVideoID 解析视频ID：已被合并的旧ID返回合并后的规范视频ID
+// 返回规范视频ID，以及是否发生了重定向；视频不存在时返回 ErrVideoNotFound
+func (s *VideoDedupService) ResolveVideoID(videoID int64) (int64, bool, error) {
+	redirected := false
+	redirect, err := s.redirectRepo.FindByFromID(videoID)
+	if err == nil {
+		videoID = redirect.ToVideoID
+		redirected = true
+	} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		zap.L().Error("查询视频重定向失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return 0, false, errors.ErrInternalError
+	}
+
+	if _, err := s.videoRepo.FindByID(videoID); err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, false, errors.ErrVideoNotFound
+		}
+		zap.L().Error("查询视频失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return 0, false, errors.ErrInternalError
+	}
+	return videoID, redirected, nil
+}
+
+// duplicateGroup 聚类得到的一组重复视频
+type duplicateGroup struct {
+	reason string
+	key    string
+	videos []*model.Video
+}
+
+// clusterDuplicateVideos 对视频进行聚类，返回包含2个及以上视频的重复组
+//  1. IMDb ID + 季数相同的视频归为一组（豆瓣的各季条目常使用同一个剧集IMDb ID，需按季区分）
+//  2. 类型 + 标准化标题 + 上映年份 + 第一导演相同的视频归为一组；
+//     若会把两个不同IMDb ID的组合并在一起，则视为冲突，不合并
+func clusterDuplicateVideos(videos []*model.Video) []*duplicateGroup {
+	parent := make([]int, len(videos))
+	imdbOf := make([]string, len(videos)) // 每个根节点所在组的IMDb键（空表示组内没有IMDb ID）
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) bool {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return true
+		}
+		if imdbOf[ra] != "" && imdbOf[rb] != "" && imdbOf[ra] != imdbOf[rb] {
+			return false
+		}
+		parent[rb] = ra
+		if imdbOf[ra] == "" {
+			imdbOf[ra] = imdbOf[rb]
+		}
+		return true
+	}
+
+	// 第一轮：按IMDb ID + 季数聚类
+	firstByIMDb := make(map[string]int)
+	for i, video := range videos {
+		key := imdbDedupKey(video)
+		if key == "" {
+			continue
+		}
+		imdbOf[i] = key
+		if first, ok := firstByIMDb[key]; ok {
+			union(first, i)
+		} else {
+			firstByIMDb[key] = i
+		}
+	}
+
+	// 第二轮：按标题+年份+导演聚类
+	fallbackKeys := make([]string, len(videos))
+	firstByFallback := make(map[string]int)
+	for i, video := range videos {
+		key := fallbackDedupKey(video)
+		if key == "" {
+			continue
+		}
+		fallbackKeys[i] = key
+		first, ok := firstByFallback[key]
+		if !ok {
+			firstByFallback[key] = i
+			continue
+		}
+		if !union(first, i) {
+			zap.L().Warn("标题、年份和导演相同但IMDb ID不同，跳过合并",
+				zap.Int64("video_id", videos[first].ID),
+				zap.Int64("other_video_id", video.ID),
+				zap.String("key", key))
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range videos {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	var groups []*duplicateGroup
+	for root, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		group := &duplicateGroup{reason: DedupReasonIMDb, key: imdbOf[root]}
+		// 组内有视频没有IMDb键时，说明它是通过标题+年份+导演并入的
+		for _, i := range idx {
+			group.videos = append(group.videos, videos[i])
+			if group.reason == DedupReasonIMDb && imdbDedupKey(videos[i]) == "" {
+				group.reason = DedupReasonTitleYearDirector
+				group.key = fallbackKeys[i]
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	// 保证输出顺序稳定
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].videos[0].ID < groups[j].videos[0].ID
+	})
+	return groups
+}
+
+// imdbDedupKey 返回IMDb聚类键（IMDb ID + 季数），没有有效IMDb ID时返回空字符串
+func imdbDedupKey(video *model.Video) string {
+	imdbID := strings.ToLower(strings.TrimSpace(video.IMDbID))
+	if !strings.HasPrefix(imdbID, "tt") || len(imdbID) < 4 {
+		return ""
+	}
+	return fmt.Sprintf("%s|s%d", imdbID, videoSeasonNumber(video))
+}
+
+// fallbackDedupKey 返回标题+年份+导演聚类键，任一字段缺失时返回空字符串（避免误合并）
+func fallbackDedupKey(video *model.Video) string {
+	title := normalizeDedupText(video.Title)
+	if title == "" || video.ReleaseDate == nil {
+		return ""
+	}
+
+	var directors []string
+	if len(video.DirectorJSON) > 0 {
+		_ = json.Unmarshal(video.DirectorJSON, &directors)
+	}
+	if len(directors) == 0 {
+		return ""
+	}
+	director := normalizeDedupText(directors[0])
+	if director == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s|%s|%d|%s", video.Type, title, video.ReleaseDate.Year(), director)
+}
+
+// videoSeasonNumber 返回视频的季数：优先使用已关联的季数，其次解析标题中的季数标记，默认为1
+func videoSeasonNumber(video *model.Video) int64 {
+	if video.SeasonNumber != nil {
+		return *video.SeasonNumber
+	}
+	if _, season, ok := ParseSeasonTitle(video.Title); ok {
+		return season
+	}
+	return 1
+}
+
+// normalizeDedupText 标准化文本：转小写，只保留字母和数字（去掉空格、标点和符号）
+func normalizeDedupText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// pickCanonicalVideo 从重复组中选出规范视频
+// 优先级：已发布（status=1） > 有IMDb ID > ID最小（最早创建）
+func pickCanonicalVideo(videos []*model.Video) (*model.Video, []*model.Video) {
+	sorted := make([]*model.Video, len(videos))
+	copy(sorted, videos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.Status == "1") != (b.Status == "1") {
+			return a.Status == "1"
+		}
+		if (a.IMDbID != "") != (b.IMDbID != "") {
+			return a.IMDbID != ""
+		}
+		return a.ID < b.ID
+	})
+	return sorted[0], sorted[1:]
+}
+
+// mergeVideoFields 用重复视频的字段补全规范视频的空字段
+func mergeVideoFields(canonical, dup *model.Video) {
+	if canonical.IMDbID == "" {
+		canonical.IMDbID = dup.IMDbID
+	}
+	if canonical.CoverURL == "" {
+		canonical.CoverURL = dup.CoverURL
+	}
+	if canonical.Description == "" {
+		canonical.Description = dup.Description
+	}
+	if canonical.ReleaseDate == nil {
+		canonical.ReleaseDate = dup.ReleaseDate
+	}
+	if canonical.Score == nil {
+		canonical.Score = dup.Score
+	}
+	if canonical.Runtime == nil {
+		canonical.Runtime = dup.Runtime
+	}
+	if canonical.Resolution == "" {
+		canonical.Resolution = dup.Resolution
+	}
+	if canonical.EpisodeCount == nil {
+		canonical.EpisodeCount = dup.EpisodeCount
+	}
+	if isEmptyJSONArray(canonical.CountryJSON) {
+		canonical.CountryJSON = dup.CountryJSON
+	}
+	if isEmptyJSONArray(canonical.DirectorJSON) {
+		canonical.DirectorJSON = dup.DirectorJSON
+	}
+	if isEmptyJSONArray(canonical.ActorsJSON) {
+		canonical.ActorsJSON = dup.ActorsJSON
+	}
+	if isEmptyJSONArray(canonical.TagsJSON) {
+		canonical.TagsJSON = dup.TagsJSON
+	}
+	if canonical.SeriesID == nil && dup.SeriesID != nil {
+		canonical.SeriesID = dup.SeriesID
+		canonical.SeasonNumber = dup.SeasonNumber
+	}
+	if dup.Status == "1" {
+		canonical.Status = "1"
+	}
+	canonical.IsCompleted = canonical.IsCompleted || dup.IsCompleted
+	canonical.IsUpdate = canonical.IsUpdate || dup.IsUpdate
+}
+
+// mergedVideoColumns 返回补全后发生变化的列及新值（列范围与 mergeVideoFields 一致）
+// 合并时只更新这些列：规范视频是去重扫描开始时的快照，整行保存会覆盖期间同步或管理接口对其他字段的修改
+func mergedVideoColumns(before, after *model.Video) map[string]interface{} {
+	columns := []struct {
+		name          string
+		before, after interface{}
+	}{
+		{"imdb_id", before.IMDbID, after.IMDbID},
+		{"cover_url", before.CoverURL, after.CoverURL},
+		{"description", before.Description, after.Description},
+		{"release_date", before.ReleaseDate, after.ReleaseDate},
+		{"score", before.Score, after.Score},
+		{"runtime", before.Runtime, after.Runtime},
+		{"resolution", before.Resolution, after.Resolution},
+		{"episode_count", before.EpisodeCount, after.EpisodeCount},
+		{"country_json", before.CountryJSON, after.CountryJSON},
+		{"director_json", before.DirectorJSON, after.DirectorJSON},
+		{"actors_json", before.ActorsJSON, after.ActorsJSON},
+		{"tags_json", before.TagsJSON, after.TagsJSON},
+		{"series_id", before.SeriesID, after.SeriesID},
+		{"season_number", before.SeasonNumber, after.SeasonNumber},
+		{"status", before.Status, after.Status},
+		{"is_completed", before.IsCompleted, after.IsCompleted},
+		{"is_update", before.IsUpdate, after.IsUpdate},
+	}
+	changed := make(map[string]interface{})
+	for _, column := range columns {
+		if !reflect.DeepEqual(column.before, column.after) {
+			changed[column.name] = column.after
+		}
+	}
+	return changed
+}
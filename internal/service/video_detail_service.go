@@ -0,0 +1,235 @@
+// service 包提供业务逻辑层
+// video_detail_service.go 提供视频详情：完整的视频信息（JSON数组列已解码）及按集数排序的剧集、播放地址和字幕，支持按字段裁剪
+package service
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// videoDetailEpisodesField 剧集列表字段名，裁剪后不包含该字段时不查询剧集
+const videoDetailEpisodesField = "episodes"
+
+// VideoDetail 视频详情
+type VideoDetail struct {
+	ID           int64             `json:"id"`
+	Source       string            `json:"source"`
+	Title        string            `json:"title"`
+	Type         string            `json:"type"`
+	CoverURL     string            `json:"cover_url"`
+	CoverThumbs  map[string]string `json:"cover_thumbs"` // 封面缩略图，键为宽度如 w320
+	Description  string            `json:"description"`
+	ReleaseDate  *time.Time        `json:"release_date"`
+	Score        *float64          `json:"score"`
+	Countries    []string          `json:"countries"`
+	Directors    []string          `json:"directors"`
+	Actors       []string          `json:"actors"`
+	Tags         []string          `json:"tags"`
+	IMDbID       string            `json:"imdb_id"`
+	Runtime      *int64            `json:"runtime"`
+	Resolution   string            `json:"resolution"`
+	EpisodeCount *int64            `json:"episode_count"`
+	IsCompleted  bool              `json:"is_completed"`
+	IsUpdate     bool              `json:"is_update"`
+	SeriesID     *int64            `json:"series_id"`
+	SeasonNumber *int64            `json:"season_number"`
+	UpdatedAt    *time.Time        `json:"updated_at"`
+	Episodes     []*EpisodeDetail  `json:"episodes"`
+}
+
+// EpisodeDetail 视频详情中的剧集
+type EpisodeDetail struct {
+	ID              int64    `json:"id"`
+	EpisodeNumber   *int64   `json:"episode_number"`
+	Name            string   `json:"name"`
+	Channel         string   `json:"channel"`
+	PlayURL         string   `json:"play_url"`
+	DurationSeconds *int64   `json:"duration_seconds"`
+	Subtitles       []string `json:"subtitles"`
+}
+
+// VideoVersion 视频详情的版本（用于条件请求）：视频的更新时间和剧集数（未返回剧集时为0）
+type VideoVersion struct {
+	VideoID      int64
+	UpdatedAt    time.Time
+	EpisodeCount int
+}
+
+// VideoDetailService 视频详情服务
+type VideoDetailService struct {
+	videoRepo    repository.VideoRepository
+	episodeRepo  repository.EpisodeRepository
+	redirectRepo repository.VideoRedirectRepository
+}
+
+// NewVideoDetailService 创建视频详情服务实例
+func NewVideoDetailService() *VideoDetailService {
+	return &VideoDetailService{
+		videoRepo:    repository.NewCachedVideoRepository(repository.NewVideoRepository()),
+		episodeRepo:  repository.NewCachedEpisodeRepository(repository.NewEpisodeRepository()),
+		redirectRepo: repository.NewVideoRedirectRepository(),
+	}
+}
+
+// GetDetail 查询已发布视频的详情及其版本（视频和剧集只加载一次，版本用于生成ETag）
+// fields不为空时只返回指定的字段（id总是返回，未知字段忽略），不包含episodes时不查询剧集
+// 已被去重合并的旧ID返回规范视频的详情（id为规范视频ID）；视频不存在或未发布时返回 ErrVideoNotFound
+func (s *VideoDetailService) GetDetail(ctx context.Context, videoID int64, fields []string) (interface{}, *VideoVersion, error) {
+	video, err := s.findPublishedVideo(ctx, videoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	detail := newVideoDetail(video)
+	version := &VideoVersion{VideoID: video.ID}
+	if video.UpdatedAt != nil {
+		version.UpdatedAt = *video.UpdatedAt
+	}
+	if len(fields) == 0 || containsString(fields, videoDetailEpisodesField) {
+		episodes, err := s.episodeRepo.FindByVideoID(video.ID)
+		if err != nil {
+			logger.FromContext(ctx).Error("查询剧集失败", zap.Int64("video_id", video.ID), zap.Error(err))
+			return nil, nil, errors.ErrVideoQueryFailed
+		}
+		detail.Episodes = make([]*EpisodeDetail, 0, len(episodes))
+		for _, episode := range episodes {
+			detail.Episodes = append(detail.Episodes, newEpisodeDetail(episode))
+		}
+		version.EpisodeCount = len(episodes)
+	}
+
+	if len(fields) == 0 {
+		return detail, version, nil
+	}
+	selected, err := selectFields(detail, append(fields, "id"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return selected, version, nil
+}
+
+// findPublishedVideo 查询已发布的视频；视频不存在时查询去重合并留下的重定向，旧ID解析为规范视频
+// 视频不存在或未发布时返回 ErrVideoNotFound
+func (s *VideoDetailService) findPublishedVideo(ctx context.Context, videoID int64) (*model.Video, error) {
+	video, err := s.videoRepo.FindByID(videoID)
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		redirect, redirectErr := s.redirectRepo.FindByFromID(videoID)
+		if redirectErr != nil {
+			if stderrors.Is(redirectErr, gorm.ErrRecordNotFound) {
+				return nil, errors.ErrVideoNotFound
+			}
+			logger.FromContext(ctx).Error("查询视频重定向失败", zap.Int64("video_id", videoID), zap.Error(redirectErr))
+			return nil, errors.ErrVideoQueryFailed
+		}
+		video, err = s.videoRepo.FindByID(redirect.ToVideoID)
+	}
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrVideoNotFound
+		}
+		logger.FromContext(ctx).Error("查询视频失败", zap.Int64("video_id", videoID), zap.Error(err))
+		return nil, errors.ErrVideoQueryFailed
+	}
+	if video.Status != "1" {
+		return nil, errors.ErrVideoNotFound
+	}
+	return video, nil
+}
+
+// ParseFields 解析逗号分隔的字段列表，忽略空白项
+func ParseFields(s string) []string {
+	var fields []string
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// newVideoDetail 将视频记录转换为详情（不含剧集）
+func newVideoDetail(video *model.Video) *VideoDetail {
+	thumbs := map[string]string{}
+	if len(video.CoverThumbsJSON) > 0 {
+		if err := json.Unmarshal(video.CoverThumbsJSON, &thumbs); err != nil || thumbs == nil {
+			thumbs = map[string]string{}
+		}
+	}
+	return &VideoDetail{
+		ID:           video.ID,
+		Source:       video.Source,
+		Title:        video.Title,
+		Type:         video.Type,
+		CoverURL:     video.CoverURL,
+		CoverThumbs:  thumbs,
+		Description:  video.Description,
+		ReleaseDate:  video.ReleaseDate,
+		Score:        video.Score,
+		Countries:    decodeStringArray(video.CountryJSON),
+		Directors:    decodeStringArray(video.DirectorJSON),
+		Actors:       decodeStringArray(video.ActorsJSON),
+		Tags:         decodeStringArray(video.TagsJSON),
+		IMDbID:       video.IMDbID,
+		Runtime:      video.Runtime,
+		Resolution:   video.Resolution,
+		EpisodeCount: video.EpisodeCount,
+		IsCompleted:  video.IsCompleted,
+		IsUpdate:     video.IsUpdate,
+		SeriesID:     video.SeriesID,
+		SeasonNumber: video.SeasonNumber,
+		UpdatedAt:    video.UpdatedAt,
+	}
+}
+
+// newEpisodeDetail 将剧集记录转换为详情
+func newEpisodeDetail(episode *model.Episode) *EpisodeDetail {
+	return &EpisodeDetail{
+		ID:              episode.ID,
+		EpisodeNumber:   episode.EpisodeNumber,
+		Name:            episode.Name,
+		Channel:         episode.Channel,
+		PlayURL:         episode.PlayURLs,
+		DurationSeconds: episode.DurationSeconds,
+		Subtitles:       decodeStringArray(episode.SubtitleURLs),
+	}
+}
+
+// selectFields 只保留指定的JSON字段（使用原始JSON值，避免int64的ID丢失精度）
+func selectFields(v interface{}, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected, nil
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
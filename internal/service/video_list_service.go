@@ -0,0 +1,204 @@
+// service 包提供业务逻辑层
+// video_list_service.go 提供视频列表：按类型、国家/地区、标签、导演、演员、年份、评分等条件筛选已发布的视频，键集游标分页
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// 视频列表每页条数
+const (
+	defaultVideoListLimit = 20
+	maxVideoListLimit     = 100
+)
+
+// VideoListRequest 视频列表查询参数（绑定URL查询参数）
+type VideoListRequest struct {
+	Type        string   `form:"type"`
+	Country     string   `form:"country"`  // 国家/地区代码，如 CN
+	Tag         string   `form:"tag"`      // 类型代码，如 drama
+	Director    string   `form:"director"` // 导演姓名
+	Actor       string   `form:"actor"`    // 演员姓名
+	YearFrom    int      `form:"year_from"`
+	YearTo      int      `form:"year_to"`
+	ScoreMin    *float64 `form:"score_min"`
+	ScoreMax    *float64 `form:"score_max"`
+	IsCompleted *bool    `form:"is_completed"`
+	IsUpdate    *bool    `form:"is_update"`
+	Sort        string   `form:"sort"`   // release_date（默认）/ score / updated
+	Cursor      string   `form:"cursor"` // 上一页返回的 next_cursor
+	Limit       int      `form:"limit"`  // 每页条数，默认20，最大100
+}
+
+// VideoSummary 视频列表中的视频摘要（JSON数组列已解码）
+type VideoSummary struct {
+	ID           int64      `json:"id"`
+	Title        string     `json:"title"`
+	Type         string     `json:"type"`
+	CoverURL     string     `json:"cover_url"`
+	ReleaseDate  *time.Time `json:"release_date"`
+	Score        *float64   `json:"score"`
+	Countries    []string   `json:"countries"`
+	Tags         []string   `json:"tags"`
+	EpisodeCount *int64     `json:"episode_count"`
+	IsCompleted  bool       `json:"is_completed"`
+	IsUpdate     bool       `json:"is_update"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+// videoListCursor 游标内容（base64url编码的JSON），只保存当前排序方式对应的排序列取值
+type videoListCursor struct {
+	Sort        string     `json:"s"`
+	ReleaseDate *time.Time `json:"d,omitempty"`
+	Score       *float64   `json:"sc,omitempty"`
+	UpdatedAt   *time.Time `json:"u,omitempty"`
+	ID          int64      `json:"id"`
+}
+
+// VideoListService 视频列表服务
+type VideoListService struct {
+	repo repository.VideoListRepository
+}
+
+// NewVideoListService 创建视频列表服务实例
+func NewVideoListService() *VideoListService {
+	return &VideoListService{
+		repo: repository.NewCachedVideoListRepository(repository.NewVideoListRepository()),
+	}
+}
+
+// List 按条件查询已发布的视频，返回当前页数据和下一页游标（没有更多数据时为空）
+func (s *VideoListService) List(ctx context.Context, req *VideoListRequest) ([]*VideoSummary, string, error) {
+	filter := &repository.VideoListFilter{
+		Type:        req.Type,
+		Country:     req.Country,
+		Tag:         req.Tag,
+		Director:    req.Director,
+		Actor:       req.Actor,
+		YearFrom:    req.YearFrom,
+		YearTo:      req.YearTo,
+		ScoreMin:    req.ScoreMin,
+		ScoreMax:    req.ScoreMax,
+		IsCompleted: req.IsCompleted,
+		IsUpdate:    req.IsUpdate,
+		Sort:        req.Sort,
+		Limit:       req.Limit,
+	}
+	if filter.Sort == "" {
+		filter.Sort = repository.VideoSortReleaseDate
+	}
+	if !repository.IsValidVideoSort(filter.Sort) {
+		return nil, "", errors.ErrVideoSortInvalid
+	}
+	if filter.YearFrom < 0 || filter.YearTo < 0 {
+		return nil, "", errors.ErrBadRequest
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultVideoListLimit
+	}
+	if filter.Limit > maxVideoListLimit {
+		filter.Limit = maxVideoListLimit
+	}
+	if req.Cursor != "" {
+		cursor, err := decodeVideoListCursor(req.Cursor, filter.Sort)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.Cursor = cursor
+	}
+
+	// 多查询一条用于判断是否还有下一页
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	videos, err := s.repo.ListPublished(filter)
+	if err != nil {
+		logger.FromContext(ctx).Error("查询视频列表失败", zap.Any("filter", req), zap.Error(err))
+		return nil, "", errors.ErrVideoQueryFailed
+	}
+
+	var nextCursor string
+	if len(videos) > limit {
+		videos = videos[:limit]
+		nextCursor = encodeVideoListCursor(filter.Sort, videos[len(videos)-1])
+	}
+
+	list := make([]*VideoSummary, 0, len(videos))
+	for _, video := range videos {
+		list = append(list, newVideoSummary(video))
+	}
+	return list, nextCursor, nil
+}
+
+// newVideoSummary 将视频记录转换为列表摘要
+func newVideoSummary(video *model.Video) *VideoSummary {
+	return &VideoSummary{
+		ID:           video.ID,
+		Title:        video.Title,
+		Type:         video.Type,
+		CoverURL:     video.CoverURL,
+		ReleaseDate:  video.ReleaseDate,
+		Score:        video.Score,
+		Countries:    decodeStringArray(video.CountryJSON),
+		Tags:         decodeStringArray(video.TagsJSON),
+		EpisodeCount: video.EpisodeCount,
+		IsCompleted:  video.IsCompleted,
+		IsUpdate:     video.IsUpdate,
+		UpdatedAt:    video.UpdatedAt,
+	}
+}
+
+// decodeStringArray 将JSON数组列解码为字符串切片，为空或无法解析时返回空切片（序列化为 []）
+func decodeStringArray(data datatypes.JSON) []string {
+	items := []string{}
+	if isEmptyJSONArray(data) {
+		return items
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return []string{}
+	}
+	return items
+}
+
+// encodeVideoListCursor 根据当前页最后一条视频生成下一页游标
+func encodeVideoListCursor(sort string, last *model.Video) string {
+	cursor := videoListCursor{Sort: sort, ID: last.ID}
+	switch sort {
+	case repository.VideoSortScore:
+		cursor.Score = last.Score
+	case repository.VideoSortUpdated:
+		cursor.UpdatedAt = last.UpdatedAt
+	default:
+		cursor.ReleaseDate = last.ReleaseDate
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeVideoListCursor 解析游标，游标格式错误或与当前排序方式不一致时返回错误
+func decodeVideoListCursor(s, sort string) (*repository.VideoListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.ErrVideoCursorInvalid
+	}
+	var cursor videoListCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || cursor.ID <= 0 {
+		return nil, errors.ErrVideoCursorInvalid
+	}
+	return &repository.VideoListCursor{
+		ReleaseDate: cursor.ReleaseDate,
+		Score:       cursor.Score,
+		UpdatedAt:   cursor.UpdatedAt,
+		ID:          cursor.ID,
+	}, nil
+}
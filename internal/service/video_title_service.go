@@ -0,0 +1,224 @@
+// service 包提供业务逻辑层
+// video_title_service.go 提供视频别名：解析豆瓣详情页中的原名和又名，用于播放地址搜索匹配
+package service
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"video-service/internal/model"
+	"video-service/pkg/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxTitleLength 别名最大长度（字符数，与 video_titles.title 列一致）
+	maxTitleLength = 255
+	// maxAlternateSearches 搜索播放地址时最多额外使用的别名数
+	maxAlternateSearches = 3
+)
+
+// doubanItemReviewedPattern 匹配详情页标题，如：
+// <span property="v:itemreviewed">肖申克的救赎 The Shawshank Redemption</span>
+var doubanItemReviewedPattern = regexp.MustCompile(`<span property="v:itemreviewed">([^<]+)</span>`)
+
+// doubanAliasRegionPattern 匹配又名末尾的地区标记，如 "刺激1995(台)"
+var doubanAliasRegionPattern = regexp.MustCompile(`\s*[(（](台|港|大陆|澳)[)）]$`)
+
+// aliasRegionLanguages 地区标记对应的语言
+var aliasRegionLanguages = map[string]string{
+	"台":  "zh-TW",
+	"港":  "zh-HK",
+	"澳":  "zh-HK",
+	"大陆": "zh",
+}
+
+// extractAlternateTitles 从详情页中解析原名和又名（不包含与title相同的标题）
+// 第二个返回值表示页面中是否找到了标题，未找到时说明页面异常，不应覆盖已有别名
+func extractAlternateTitles(page, title string) ([]*model.VideoTitle, bool) {
+	match := doubanItemReviewedPattern.FindStringSubmatch(page)
+	if len(match) < 2 {
+		return nil, false
+	}
+
+	var titles []*model.VideoTitle
+	// 按小写去重（title列的排序规则不区分大小写，唯一索引同样如此）
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(title)): true}
+	add := func(value, language, kind string) {
+		value = truncateRunes(strings.TrimSpace(value), maxTitleLength)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		if language == "" {
+			language = detectTitleLanguage(value)
+		}
+		titles = append(titles, &model.VideoTitle{Title: value, Language: language, Kind: kind})
+	}
+
+	// 标题格式为"中文名 原名"，原名与中文名相同时只有中文名
+	full := strings.TrimSpace(html.UnescapeString(match[1]))
+	if strings.HasPrefix(full, title+" ") {
+		add(full[len(title):], "", model.VideoTitleKindOriginal)
+	}
+
+	// 又名：刺激1995(台) / 地狱诺言 / 铁窗岁月
+	aliases := html.UnescapeString(extractField(page, `<span class="pl">又名:</span>`, `<br`))
+	for _, alias := range strings.Split(aliases, " / ") {
+		alias = strings.TrimSpace(alias)
+		language := ""
+		if m := doubanAliasRegionPattern.FindStringSubmatch(alias); len(m) > 1 {
+			language = aliasRegionLanguages[m[1]]
+			alias = alias[:len(alias)-len(m[0])]
+		}
+		add(alias, language, model.VideoTitleKindAlias)
+	}
+
+	return titles, true
+}
+
+// detectTitleLanguage 根据文字推断标题语言：含假名为ja，含谚文为ko，含汉字为zh，只有ASCII字符为en，否则为空
+func detectTitleLanguage(title string) string {
+	hasHan, hasLetter, asciiOnly := false, false, true
+	for _, r := range title {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return "ja"
+		case unicode.Is(unicode.Hangul, r):
+			return "ko"
+		case unicode.Is(unicode.Han, r):
+			hasHan = true
+		}
+		if r > unicode.MaxASCII {
+			asciiOnly = false
+		} else if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	if hasHan {
+		return "zh"
+	}
+	if asciiOnly && hasLetter {
+		return "en"
+	}
+	return ""
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// saveTitles 解析详情页中的原名和又名并保存（预演模式下不处理），失败只记录日志
+func (s *DoubanSyncService) saveTitles(video *model.Video, page string) {
+	if s.IsDryRun() {
+		return
+	}
+	titles, ok := extractAlternateTitles(page, video.Title)
+	if !ok {
+		return
+	}
+	for _, title := range titles {
+		title.VideoID = video.ID
+	}
+	existing, err := s.titleRepo.FindByVideoID(video.ID)
+	if err == nil && sameTitles(existing, titles) {
+		return
+	}
+	if err := s.titleRepo.ReplaceTitles(video.ID, titles); err != nil {
+		s.log.Warn("保存视频别名失败", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.Error(err))
+		return
+	}
+	// 别名参与搜索建议索引，更新 updated_at 使同步结束时的增量索引能覆盖到该视频
+	if err := s.videoRepo.TouchUpdatedAt(video.ID); err != nil {
+		s.log.Warn("更新视频更新时间失败", zap.Int64("video_id", video.ID), zap.Error(err))
+	}
+}
+
+// sameTitles 判断已保存的别名与新解析的别名是否一致（比较标题、语言和类型，忽略顺序）
+func sameTitles(existing, titles []*model.VideoTitle) bool {
+	if len(existing) != len(titles) {
+		return false
+	}
+	seen := make(map[model.VideoTitle]int, len(existing))
+	for _, t := range existing {
+		seen[model.VideoTitle{Title: t.Title, Language: t.Language, Kind: t.Kind}]++
+	}
+	for _, t := range titles {
+		key := model.VideoTitle{Title: t.Title, Language: t.Language, Kind: t.Kind}
+		if seen[key] == 0 {
+			return false
+		}
+		seen[key]--
+	}
+	return true
+}
+
+// searchTitles 返回搜索播放地址时使用的标题：视频标题在前，其后是别名（原名优先，最多 maxAlternateSearches 个）
+func (s *DoubanSyncService) searchTitles(video *model.Video) []string {
+	titles := []string{video.Title}
+	alternates, err := s.titleRepo.FindByVideoID(video.ID)
+	if err != nil {
+		s.log.Warn("查询视频别名失败", zap.Int64("video_id", video.ID), zap.Error(err))
+		return titles
+	}
+	for _, alt := range alternates {
+		if len(titles) > maxAlternateSearches {
+			break
+		}
+		titles = append(titles, alt.Title)
+	}
+	return titles
+}
+
+// searchPlayURLResults 依次使用各个标题搜索播放地址，返回第一个包含匹配标题的搜索结果
+// 使用视频标题搜索失败时返回错误，别名搜索失败只记录日志
+func (s *DoubanSyncService) searchPlayURLResults(ctx context.Context, video *model.Video, titles []string) ([]SearchResult, error) {
+	for i, query := range titles {
+		if i > 0 {
+			// 避免请求过快
+			syncThrottle(500 * time.Millisecond)
+		}
+
+		results, body, err := fetchPlayURLSearchResults(ctx, query)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			logger.FromContext(ctx).Warn("使用别名搜索播放地址失败", zap.Int64("video_id", video.ID), zap.String("query", query), zap.Error(err))
+			continue
+		}
+
+		for _, result := range results {
+			if matchesAnyTitle(result.Title, titles) {
+				if i > 0 {
+					logger.FromContext(ctx).Info("使用别名匹配到播放地址", zap.Int64("video_id", video.ID), zap.String("title", video.Title), zap.String("query", query))
+				}
+				s.archiveSearchResponse(ctx, video.ID, body)
+				return results, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// matchesAnyTitle 判断搜索结果标题是否与视频标题或任一别名相同（忽略首尾空白和英文大小写）
+func matchesAnyTitle(resultTitle string, titles []string) bool {
+	resultTitle = strings.TrimSpace(resultTitle)
+	for _, title := range titles {
+		if strings.EqualFold(resultTitle, strings.TrimSpace(title)) {
+			return true
+		}
+	}
+	return false
+}
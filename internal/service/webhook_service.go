@@ -0,0 +1,392 @@
+// service 包提供业务逻辑层
+// webhook_service.go 提供出站Webhook：将同步和视频目录事件以HMAC签名的HTTP请求推送给下游服务，失败时按退避策略重试
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"video-service/internal/model"
+	"video-service/internal/pkg/errors"
+	"video-service/internal/repository"
+	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Webhook事件类型
+const (
+	EventSyncFinished   = "sync.finished"   // 同步运行结束
+	EventVideoCreated   = "video.created"   // 新建视频
+	EventEpisodesAdded  = "episodes.added"  // 视频新增剧集
+	EventVideoPublished = "video.published" // 视频发布（status变为1）
+	EventVideoMerged    = "video.merged"    // 重复视频被合并到规范视频
+)
+
+// Webhook请求头
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+// WebhookEndpoint Webhook端点配置
+type WebhookEndpoint struct {
+	Name   string   `mapstructure:"name"`   // 端点名称（记录在投递日志中）
+	URL    string   `mapstructure:"url"`    // 投递地址
+	Secret string   `mapstructure:"secret"` // HMAC签名密钥
+	Events []string `mapstructure:"events"` // 订阅的事件类型，为空或包含"*"表示订阅全部
+}
+
+// subscribes 判断端点是否订阅了指定事件
+func (e *WebhookEndpoint) subscribes(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, ev := range e.Events {
+		if ev == "*" || ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookLeaseMargin 投递占用时间在请求超时之外额外增加的余量
+// 投递前通过条件更新把 next_retry_at 设为占用截止时间，其他进程（服务和 cmd/sync）在此之前不会继续投递同一条记录
+const webhookLeaseMargin = 30 * time.Second
+
+// WebhookPayload Webhook请求体
+type WebhookPayload struct {
+	ID         string      `json:"id"`          // 投递唯一标识，与 X-Webhook-Delivery 一致
+	Event      string      `json:"event"`       // 事件类型
+	OccurredAt time.Time   `json:"occurred_at"` // 事件发生时间
+	Data       interface{} `json:"data"`        // 事件数据
+}
+
+// WebhookService Webhook服务
+type WebhookService struct {
+	repo        repository.WebhookDeliveryRepository
+	endpoints   []*WebhookEndpoint
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	lease       time.Duration // 单次投递占用记录的时间（请求超时+余量）
+}
+
+// NewWebhookService 创建Webhook服务实例
+// 配置项：
+//   - webhooks.endpoints: 端点列表（name/url/secret/events）
+//   - webhooks.max_attempts: 最大尝试次数（默认5）
+//   - webhooks.backoff: 首次重试间隔，之后指数增长（默认2s）
+//   - webhooks.timeout: 单次请求超时（默认10s）
+func NewWebhookService() *WebhookService {
+	var endpoints []*WebhookEndpoint
+	if err := config.Cfg.UnmarshalKey("webhooks.endpoints", &endpoints); err != nil {
+		zap.L().Error("解析webhooks.endpoints配置失败", zap.Error(err))
+	}
+
+	maxAttempts := config.Cfg.GetInt("webhooks.max_attempts")
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	backoff := config.Cfg.GetDuration("webhooks.backoff")
+	if backoff <= 0 {
+		backoff = 2 * time.Second
+	}
+	timeout := config.Cfg.GetDuration("webhooks.timeout")
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WebhookService{
+		repo:        repository.NewWebhookDeliveryRepository(),
+		endpoints:   endpoints,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		lease:       timeout + webhookLeaseMargin,
+	}
+}
+
+// Publish 发布事件：为每个订阅了该事件的端点创建投递记录（创建时即占用），并在后台异步投递
+// 投递日志使用ctx中的日志记录器（同步中带 run_id）
+func (s *WebhookService) Publish(ctx context.Context, event string, data interface{}) {
+	log := logger.FromContext(ctx)
+	occurredAt := time.Now()
+	for _, endpoint := range s.endpoints {
+		if !endpoint.subscribes(event) {
+			continue
+		}
+
+		deliveryID := uuid.New().String()
+		body, err := json.Marshal(WebhookPayload{
+			ID:         deliveryID,
+			Event:      event,
+			OccurredAt: occurredAt,
+			Data:       data,
+		})
+		if err != nil {
+			log.Error("序列化Webhook内容失败", zap.String("event", event), zap.String("endpoint", endpoint.Name), zap.Error(err))
+			continue
+		}
+
+		leaseUntil := s.leaseUntil(time.Now())
+		delivery := &model.WebhookDelivery{
+			DeliveryID:  deliveryID,
+			Event:       event,
+			Endpoint:    endpoint.Name,
+			URL:         endpoint.URL,
+			Payload:     body,
+			Status:      model.WebhookStatusPending,
+			NextRetryAt: &leaseUntil,
+		}
+		if err := s.repo.Create(delivery); err != nil {
+			log.Error("保存Webhook投递记录失败", zap.String("event", event), zap.String("endpoint", endpoint.Name), zap.Error(err))
+			continue
+		}
+
+		go s.deliver(log, endpoint, delivery)
+	}
+}
+
+// ResumePending 继续投递服务重启前未完成的投递记录
+// 每条记录等到 next_retry_at（退避的重试时间，或其他进程占用的截止时间）后占用并投递，占用失败说明已由其他进程投递
+func (s *WebhookService) ResumePending() {
+	deliveries, err := s.repo.FindPending(1000)
+	if err != nil {
+		zap.L().Error("查询未完成的Webhook投递记录失败", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		endpoint := s.findEndpoint(delivery.Endpoint)
+		if endpoint == nil {
+			// 端点已从配置中移除，占用后直接标记为失败
+			if !s.claim(zap.L(), delivery) {
+				continue
+			}
+			delivery.Status = model.WebhookStatusFailed
+			delivery.LastError = "endpoint not configured"
+			delivery.NextRetryAt = nil
+			s.save(zap.L(), delivery)
+			continue
+		}
+		go s.resume(endpoint, delivery)
+	}
+
+	if len(deliveries) > 0 {
+		zap.L().Info("继续投递未完成的Webhook", zap.Int("count", len(deliveries)))
+	}
+}
+
+// resume 等到投递记录的 next_retry_at 后占用记录，重新读取最新状态后继续投递
+func (s *WebhookService) resume(endpoint *WebhookEndpoint, delivery *model.WebhookDelivery) {
+	if delivery.NextRetryAt != nil {
+		if wait := time.Until(*delivery.NextRetryAt); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	if !s.claim(zap.L(), delivery) {
+		return
+	}
+
+	current, err := s.repo.FindByID(delivery.ID)
+	if err != nil {
+		zap.L().Error("查询Webhook投递记录失败", zap.Int64("id", delivery.ID), zap.Error(err))
+		return
+	}
+	if current.Status != model.WebhookStatusPending {
+		return
+	}
+	s.deliver(zap.L(), endpoint, current)
+}
+
+// Redeliver 重新投递指定的投递记录（重置尝试次数）
+// 只能重新投递已结束（success/failed）的记录：pending 状态的记录可能仍在后台投递或等待重试，
+// 通过条件更新把状态改回 pending，并发的重新投递请求只有一个能成功
+func (s *WebhookService) Redeliver(ctx context.Context, id int64) (*model.WebhookDelivery, error) {
+	delivery, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := s.findEndpoint(delivery.Endpoint)
+	if endpoint == nil {
+		return nil, fmt.Errorf("端点未配置: %s", delivery.Endpoint)
+	}
+
+	reset, err := s.repo.ResetFinished(id, s.leaseUntil(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	if !reset {
+		return nil, errors.ErrWebhookDeliveryInProgress
+	}
+
+	// 重新读取，使用条件更新之后的记录投递
+	delivery, err = s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.deliver(logger.FromContext(ctx), endpoint, delivery)
+	return delivery, nil
+}
+
+// findEndpoint 根据名称查找端点配置
+func (s *WebhookService) findEndpoint(name string) *WebhookEndpoint {
+	for _, endpoint := range s.endpoints {
+		if endpoint.Name == name {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+// deliver 投递已占用的记录，失败时按指数退避重试，直到成功或达到最大尝试次数
+// 等待重试期间 next_retry_at 为重试时间，到期后重新占用，占用失败（已由其他进程接管）时停止
+func (s *WebhookService) deliver(log *zap.Logger, endpoint *WebhookEndpoint, delivery *model.WebhookDelivery) {
+	for delivery.Attempts < s.maxAttempts {
+		code, err := s.send(endpoint, delivery)
+		delivery.Attempts++
+		delivery.ResponseCode = code
+
+		if err == nil {
+			now := time.Now()
+			delivery.Status = model.WebhookStatusSuccess
+			delivery.LastError = ""
+			delivery.NextRetryAt = nil
+			delivery.DeliveredAt = &now
+			s.save(log, delivery)
+			log.Info("Webhook投递成功",
+				zap.String("event", delivery.Event),
+				zap.String("endpoint", endpoint.Name),
+				zap.String("delivery_id", delivery.DeliveryID),
+				zap.Int("attempts", delivery.Attempts))
+			return
+		}
+
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= s.maxAttempts {
+			break
+		}
+
+		// 指数退避：backoff, 2*backoff, 4*backoff ...
+		wait := s.backoff << (delivery.Attempts - 1)
+		next := time.Now().Add(wait).Truncate(time.Millisecond)
+		delivery.NextRetryAt = &next
+		s.save(log, delivery)
+		log.Warn("Webhook投递失败，稍后重试",
+			zap.String("event", delivery.Event),
+			zap.String("endpoint", endpoint.Name),
+			zap.String("delivery_id", delivery.DeliveryID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Duration("retry_in", wait),
+			zap.Error(err))
+		time.Sleep(time.Until(next))
+
+		if !s.claim(log, delivery) {
+			log.Info("Webhook投递记录已由其他进程接管，停止重试",
+				zap.String("delivery_id", delivery.DeliveryID), zap.Int("attempts", delivery.Attempts))
+			return
+		}
+	}
+
+	delivery.Status = model.WebhookStatusFailed
+	delivery.NextRetryAt = nil
+	s.save(log, delivery)
+	log.Error("Webhook投递失败，已达到最大尝试次数",
+		zap.String("event", delivery.Event),
+		zap.String("endpoint", endpoint.Name),
+		zap.String("delivery_id", delivery.DeliveryID),
+		zap.Int("attempts", delivery.Attempts),
+		zap.String("last_error", delivery.LastError))
+}
+
+// send 发送一次Webhook请求，返回响应状态码；非2xx响应视为失败
+func (s *WebhookService) send(endpoint *WebhookEndpoint, delivery *model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest("POST", endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("user-agent", "video-service-webhook/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.Event)
+	req.Header.Set(WebhookHeaderDelivery, delivery.DeliveryID)
+	req.Header.Set(WebhookHeaderTimestamp, timestamp)
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	// 读取并丢弃响应体，便于连接复用
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("响应状态码异常: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// claim 占用到期的投递记录，成功时 delivery.NextRetryAt 更新为占用截止时间；查询失败只记录日志，视为未占用
+func (s *WebhookService) claim(log *zap.Logger, delivery *model.WebhookDelivery) bool {
+	now := time.Now()
+	leaseUntil := s.leaseUntil(now)
+	claimed, err := s.repo.Claim(delivery.ID, now, leaseUntil)
+	if err != nil {
+		log.Error("占用Webhook投递记录失败", zap.Int64("id", delivery.ID), zap.Error(err))
+		return false
+	}
+	if claimed {
+		delivery.NextRetryAt = &leaseUntil
+	}
+	return claimed
+}
+
+// leaseUntil 返回从now开始的占用截止时间（截断到毫秒，与 datetime(3) 列保存的值一致）
+func (s *WebhookService) leaseUntil(now time.Time) time.Time {
+	return now.Add(s.lease).Truncate(time.Millisecond)
+}
+
+// save 保存投递记录，失败时只记录日志
+func (s *WebhookService) save(log *zap.Logger, delivery *model.WebhookDelivery) {
+	if err := s.repo.Update(delivery); err != nil {
+		log.Error("更新Webhook投递记录失败", zap.Int64("id", delivery.ID), zap.Error(err))
+	}
+}
+
+// SignWebhookPayload 计算Webhook签名
+// 签名算法：HMAC-SHA256(secret, timestamp + "." + body)，结果为 "sha256=" + 十六进制摘要
+// 接收方应使用 X-Webhook-Timestamp 和原始请求体重新计算签名并与 X-Webhook-Signature 比较
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ListWebhookDeliveries 分页查询Webhook投递记录
+func ListWebhookDeliveries(filter repository.WebhookDeliveryFilter, page, pageSize int) ([]*model.WebhookDelivery, int64, error) {
+	return repository.NewWebhookDeliveryRepository().List(filter, page, pageSize)
+}
+
+// GetWebhookDelivery 根据ID查询Webhook投递记录
+func GetWebhookDelivery(id int64) (*model.WebhookDelivery, error) {
+	return repository.NewWebhookDeliveryRepository().FindByID(id)
+}
@@ -38,6 +38,28 @@ CREATE TABLE `app_versions` (
   KEY `idx_app_versions_platform` (`platform`) USING BTREE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='应用版本表';
 
+-- ----------------------------
+-- Table structure for catalog_changes
+-- ----------------------------
+DROP TABLE IF EXISTS `catalog_changes`;
+CREATE TABLE `catalog_changes` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '变更记录ID',
+  `entity_type` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '实体类型(video/episode)',
+  `entity_id` bigint NOT NULL COMMENT '实体ID(视频ID或剧集ID)',
+  `video_id` bigint NOT NULL COMMENT '所属视频ID(剧集的变更也记录所属视频，用于查询视频的完整历史)',
+  `field` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '字段名(*created表示新建)',
+  `old_value` json DEFAULT NULL COMMENT '旧值(JSON格式，NULL表示无值)',
+  `new_value` json DEFAULT NULL COMMENT '新值(JSON格式，NULL表示无值)',
+  `actor` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '操作者(sync/reparse/system/admin:用户名)',
+  `sync_run_id` varchar(36) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '同步运行ID(操作者为sync或reparse时)',
+  `revert_of` bigint DEFAULT NULL COMMENT '回滚的变更记录ID(此记录由回滚产生时)',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '变更时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `idx_catalog_changes_entity` (`entity_type`,`entity_id`) USING BTREE,
+  KEY `idx_catalog_changes_video_id` (`video_id`) USING BTREE,
+  KEY `idx_catalog_changes_sync_run_id` (`sync_run_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='目录变更记录表';
+
 -- ----------------------------
 -- Table structure for danmakus
 -- ----------------------------
@@ -87,8 +109,64 @@ CREATE TABLE `filter_info` (
   `country` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,
   `year` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,
   `tags` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL,
-  PRIMARY KEY (`id`) USING BTREE
-) ENGINE=InnoDB AUTO_INCREMENT=8 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='视频表';
+  `count` bigint DEFAULT '0' COMMENT '该筛选项下的已发布视频数',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  KEY `idx_filter_info_type` (`type`) USING BTREE
+) ENGINE=InnoDB AUTO_INCREMENT=8 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='筛选信息表';
+
+-- ----------------------------
+-- Table structure for page_archives
+-- ----------------------------
+DROP TABLE IF EXISTS `page_archives`;
+CREATE TABLE `page_archives` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '归档记录ID',
+  `source` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '来源站点(如:douban)',
+  `source_id` bigint NOT NULL COMMENT '来源站点的视频ID',
+  `kind` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '页面类型(detail:详情页,list:列表条目,search:播放地址搜索结果)',
+  `storage_key` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '归档存储中的对象键(gzip压缩)',
+  `content_type` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '原始内容类型',
+  `size` bigint DEFAULT NULL COMMENT '原始大小(字节)',
+  `compressed_size` bigint DEFAULT NULL COMMENT '压缩后大小(字节)',
+  `fetched_at` datetime(3) NOT NULL COMMENT '请求时间',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_page_archives_source_kind` (`source`,`source_id`,`kind`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='页面归档表';
+
+-- ----------------------------
+-- Table structure for people
+-- ----------------------------
+DROP TABLE IF EXISTS `people`;
+CREATE TABLE `people` (
+  `id` bigint NOT NULL COMMENT '人物ID，使用雪花算法生成（非自增主键）',
+  `name` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '姓名',
+  `douban_id` bigint DEFAULT NULL COMMENT '豆瓣影人ID（/celebrity/{id}/）',
+  `douban_personage_id` bigint DEFAULT NULL COMMENT '豆瓣人物ID（/personage/{id}/）',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_people_douban_id` (`douban_id`) USING BTREE,
+  UNIQUE KEY `idx_people_douban_personage_id` (`douban_personage_id`) USING BTREE,
+  KEY `idx_people_name` (`name`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='人物表';
+
+-- ----------------------------
+-- Table structure for series
+-- ----------------------------
+DROP TABLE IF EXISTS `series`;
+CREATE TABLE `series` (
+  `id` bigint NOT NULL COMMENT '系列ID，使用雪花算法生成（非自增主键）',
+  `title` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '系列标题（去掉季数标记后的标题）',
+  `type` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '视频类型(movie/tv/tvshow等)',
+  `cover_url` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '封面图片地址',
+  `description` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '系列简介',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_series_title_type` (`title`,`type`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='剧集系列表';
 
 -- ----------------------------
 -- Table structure for user_favorites
@@ -131,6 +209,7 @@ CREATE TABLE `users` (
   `password` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '密码(加密存储)',
   `nickname` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '昵称',
   `email` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '邮箱地址',
+  `role` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT 'user' COMMENT '角色：user普通用户，admin管理员',
   `avatar` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '头像URL',
   `acc_web` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT 'Web端访问码',
   `acc_web_create_at` datetime(3) DEFAULT NULL COMMENT 'Web端访问码创建时间',
@@ -143,6 +222,71 @@ CREATE TABLE `users` (
   UNIQUE KEY `username` (`username`) USING BTREE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='用户表';
 
+-- ----------------------------
+-- Table structure for video_credits
+-- ----------------------------
+DROP TABLE IF EXISTS `video_credits`;
+CREATE TABLE `video_credits` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '演职员记录ID',
+  `video_id` bigint NOT NULL COMMENT '视频ID',
+  `person_id` bigint NOT NULL COMMENT '人物ID',
+  `role` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '角色(director/writer/actor)',
+  `billing_order` bigint DEFAULT '0' COMMENT '署名顺序（同一角色内从0开始）',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_video_credits_video_person_role` (`video_id`,`person_id`,`role`) USING BTREE,
+  KEY `idx_video_credits_person_id` (`person_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='视频演职员表';
+
+-- ----------------------------
+-- Table structure for video_redirects
+-- ----------------------------
+DROP TABLE IF EXISTS `video_redirects`;
+CREATE TABLE `video_redirects` (
+  `from_video_id` bigint NOT NULL COMMENT '被合并（已删除）的视频ID',
+  `to_video_id` bigint NOT NULL COMMENT '合并后的规范视频ID',
+  `source` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '被合并视频的来源(如:douban、xiaoya)',
+  `source_id` bigint DEFAULT NULL COMMENT '被合并视频的来源站点ID（同步时据此跳过已合并的视频）',
+  `reason` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '合并依据(imdb/title_year_director)',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`from_video_id`) USING BTREE,
+  KEY `idx_video_redirects_to_video_id` (`to_video_id`) USING BTREE,
+  KEY `idx_video_redirects_source_id` (`source_id`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='视频重定向表';
+
+-- ----------------------------
+-- Table structure for video_release_dates
+-- ----------------------------
+DROP TABLE IF EXISTS `video_release_dates`;
+CREATE TABLE `video_release_dates` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '上映日期记录ID',
+  `video_id` bigint NOT NULL COMMENT '视频ID',
+  `release_date` date NOT NULL COMMENT '上映日期',
+  `region` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '地区或场合（原文，如中国大陆、戛纳电影节）',
+  `country` varchar(8) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '国家/地区规范代码（地区不是国家/地区时为空）',
+  `kind` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '日期类型(release:上映 premiere:首播)',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_video_release_dates_video_date_region` (`video_id`,`release_date`,`region`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='视频上映日期表';
+
+-- ----------------------------
+-- Table structure for video_titles
+-- ----------------------------
+DROP TABLE IF EXISTS `video_titles`;
+CREATE TABLE `video_titles` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '别名记录ID',
+  `video_id` bigint NOT NULL COMMENT '视频ID',
+  `title` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '别名',
+  `language` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '语言(zh/zh-TW/zh-HK/ja/ko/en，根据文字和地区标记推断，未知为空)',
+  `kind` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '别名类型(original:原名 alias:又名)',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_video_titles_video_title` (`video_id`,`title`) USING BTREE,
+  KEY `idx_video_titles_title` (`title`) USING BTREE,
+  FULLTEXT KEY `ft_video_titles_title` (`title`) WITH PARSER ngram
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='视频别名表';
+
 -- ----------------------------
 -- Table structure for videos
 -- ----------------------------
@@ -168,18 +312,57 @@ CREATE TABLE `videos` (
   `episode_count` bigint DEFAULT NULL COMMENT '集数',
   `is_completed` tinyint(1) DEFAULT '0' COMMENT '是否完结(0:未完结,1:已完结)',
   `is_update` tinyint(1) DEFAULT '0' COMMENT '是否有更新(0:无更新,1:有更新)',
+  `series_id` bigint DEFAULT NULL COMMENT '所属系列ID（多季剧集的同一系列）',
+  `season_number` bigint DEFAULT NULL COMMENT '季数（从1开始）',
+  `series_locked` tinyint(1) DEFAULT '0' COMMENT '系列关联是否由管理员锁定(0:否,1:是)，锁定后同步不再自动修改',
+  `cover_source_url` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '封面原始地址（镜像前的来源站点地址）',
+  `cover_thumbs_json` json DEFAULT NULL COMMENT '封面缩略图地址（JSON对象，键为宽度如w320）',
+  `cover_mirrored_at` datetime(3) DEFAULT NULL COMMENT '封面镜像时间',
+  `cover_mirror_failures` bigint DEFAULT '0' COMMENT '封面镜像连续失败次数',
+  `credits_checked_at` datetime(3) DEFAULT NULL COMMENT '最近一次为补充演职员请求详情页的时间',
+  `credits_text` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci GENERATED ALWAYS AS (concat_ws(' ', json_unquote(`director_json`), json_unquote(`actors_json`))) STORED COMMENT '导演和演员（用于全文搜索，由JSON列生成）',
   `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
   `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
   PRIMARY KEY (`id`),
   KEY `idx_type_date_score` (`type`,`release_date` DESC,`score` DESC),
   KEY `idx_status` (`status`),
+  KEY `idx_videos_series_id` (`series_id`),
   KEY `idx_release_date` (`release_date` DESC),
   KEY `idx_score` (`score` DESC),
   KEY `idx_type` (`type`),
   KEY `idx_country_mv` ((cast(`country_json` as char(32) array))),
   KEY `idx_tags_mv` ((cast(`tags_json` as char(32) array))),
   KEY `idx_director_mv` ((cast(`director_json` as char(32) array))),
-  KEY `idx_actors_mv` ((cast(`actors_json` as char(32) array)))
+  KEY `idx_actors_mv` ((cast(`actors_json` as char(32) array))),
+  FULLTEXT KEY `ft_videos_title` (`title`) WITH PARSER ngram,
+  FULLTEXT KEY `ft_videos_description` (`description`) WITH PARSER ngram,
+  FULLTEXT KEY `ft_videos_credits` (`credits_text`) WITH PARSER ngram
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='视频表';
 
+-- ----------------------------
+-- Table structure for webhook_deliveries
+-- ----------------------------
+DROP TABLE IF EXISTS `webhook_deliveries`;
+CREATE TABLE `webhook_deliveries` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT '投递记录ID',
+  `delivery_id` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '投递唯一标识(随请求头X-Webhook-Delivery发送)',
+  `event` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '事件类型',
+  `endpoint` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT '端点名称',
+  `url` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '投递地址',
+  `payload` json DEFAULT NULL COMMENT '投递内容(JSON格式)',
+  `status` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '投递状态(pending/success/failed)',
+  `attempts` bigint DEFAULT '0' COMMENT '已尝试次数',
+  `response_code` bigint DEFAULT NULL COMMENT '最后一次响应状态码',
+  `last_error` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '最后一次错误信息',
+  `next_retry_at` datetime(3) DEFAULT NULL COMMENT '下次重试时间',
+  `delivered_at` datetime(3) DEFAULT NULL COMMENT '投递成功时间',
+  `created_at` datetime(3) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` datetime(3) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE KEY `idx_webhook_deliveries_delivery_id` (`delivery_id`) USING BTREE,
+  KEY `idx_webhook_deliveries_event` (`event`) USING BTREE,
+  KEY `idx_webhook_deliveries_endpoint` (`endpoint`) USING BTREE,
+  KEY `idx_webhook_deliveries_status` (`status`) USING BTREE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci ROW_FORMAT=DYNAMIC COMMENT='Webhook投递记录表';
+
 SET FOREIGN_KEY_CHECKS = 1;
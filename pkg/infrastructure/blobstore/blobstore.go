@@ -0,0 +1,78 @@
+// blobstore 包提供对象存储功能
+// 支持本地文件系统和S3兼容的对象存储（如MinIO），用于保存镜像的封面图片等静态资源
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"video-service/pkg/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+// Store 对象存储接口
+type Store interface {
+	// Put 保存对象（已存在时覆盖）
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Get 读取对象，对象不存在时返回 ErrObjectNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// URL 返回对象的公开访问地址
+	URL(key string) string
+}
+
+// ErrObjectNotFound 对象不存在
+var ErrObjectNotFound = errors.New("对象不存在")
+
+// Blob 是全局对象存储实例，未配置 storage.driver 时为nil
+var Blob Store
+
+// InitBlobStore 初始化对象存储
+// 配置项 storage.driver：
+//   - local: 保存到本地目录，由本服务以静态文件方式提供访问
+//   - s3: 保存到S3兼容的对象存储（如MinIO）
+//
+// 未配置时不启用对象存储，依赖它的功能（如封面镜像）会自动跳过
+func InitBlobStore() {
+	driver := config.Cfg.GetString("storage.driver")
+	switch driver {
+	case "":
+		zap.L().Info("storage driver empty, blob store disabled")
+		return
+	case "local":
+		store, err := NewLocalStore(
+			config.Cfg.GetString("storage.local.dir"),
+			config.Cfg.GetString("storage.local.url_prefix"),
+			config.Cfg.GetString("storage.public_base_url"),
+		)
+		if err != nil {
+			zap.L().Fatal("init local blob store failed", zap.Error(err))
+		}
+		Blob = store
+	case "s3":
+		store, err := NewS3Store(S3Options{
+			Endpoint:  config.Cfg.GetString("storage.s3.endpoint"),
+			Region:    config.Cfg.GetString("storage.s3.region"),
+			Bucket:    config.Cfg.GetString("storage.s3.bucket"),
+			AccessKey: config.Cfg.GetString("storage.s3.access_key"),
+			SecretKey: config.Cfg.GetString("storage.s3.secret_key"),
+			PublicURL: config.Cfg.GetString("storage.s3.public_url"),
+		})
+		if err != nil {
+			zap.L().Fatal("init s3 blob store failed", zap.Error(err))
+		}
+		Blob = store
+	default:
+		zap.L().Fatal("unknown storage driver", zap.String("driver", driver))
+	}
+
+	zap.L().Info("blob store initialized", zap.String("driver", driver))
+}
+
+// joinURL 拼接URL前缀和对象键
+func joinURL(prefix, key string) string {
+	return strings.TrimRight(prefix, "/") + "/" + strings.TrimLeft(key, "/")
+}
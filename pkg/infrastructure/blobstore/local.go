@@ -0,0 +1,108 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore 本地文件系统对象存储
+// 对象保存在 dir 目录下，通过 urlPrefix 路由以静态文件方式提供访问
+type LocalStore struct {
+	dir           string
+	urlPrefix     string
+	publicBaseURL string
+}
+
+// NewLocalStore 创建本地文件系统对象存储
+//
+//	dir: 保存目录（默认 ./data/media）
+//	urlPrefix: 静态文件路由前缀（默认 /media）
+//	publicBaseURL: 对外访问的服务地址（如 http://192.168.1.10:6661），为空时返回以 urlPrefix 开头的相对地址
+func NewLocalStore(dir, urlPrefix, publicBaseURL string) (*LocalStore, error) {
+	if dir == "" {
+		dir = "./data/media"
+	}
+	if urlPrefix == "" {
+		urlPrefix = "/media"
+	}
+	if !strings.HasPrefix(urlPrefix, "/") {
+		urlPrefix = "/" + urlPrefix
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &LocalStore{
+		dir:           dir,
+		urlPrefix:     strings.TrimRight(urlPrefix, "/"),
+		publicBaseURL: strings.TrimRight(publicBaseURL, "/"),
+	}, nil
+}
+
+// Dir 返回存储目录
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
+
+// URLPrefix 返回静态文件路由前缀
+func (s *LocalStore) URLPrefix() string {
+	return s.urlPrefix
+}
+
+// Put 保存对象：先写入临时文件再重命名，避免读到写了一半的文件
+func (s *LocalStore) Put(_ context.Context, key string, data []byte, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get 读取对象
+func (s *LocalStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+// URL 返回对象的访问地址
+func (s *LocalStore) URL(key string) string {
+	return s.publicBaseURL + joinURL(s.urlPrefix, key)
+}
+
+// path 将对象键转换为本地文件路径，拒绝跳出存储目录的键
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", fmt.Errorf("无效的对象键: %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
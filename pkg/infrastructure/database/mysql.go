@@ -7,6 +7,7 @@ import (
 	"time"
 	"video-service/internal/model"
 	"video-service/pkg/infrastructure/config"
+	"video-service/pkg/infrastructure/tracing"
 
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
@@ -54,6 +55,11 @@ func InitMySQL() {
 	// 设置连接的最大生命周期（1小时）
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
+	// 为每条SQL语句创建追踪span
+	if err := dbConn.Use(tracing.GormPlugin{}); err != nil {
+		zap.L().Warn("register gorm tracing plugin failed", zap.Error(err))
+	}
+
 	// 保存全局数据库连接
 	DB = dbConn
 
@@ -70,6 +76,15 @@ func InitMySQL() {
 		&model.UserFavorite{},
 		&model.FilterInfo{},
 		&model.AppVersion{},
+		&model.WebhookDelivery{},
+		&model.Series{},
+		&model.VideoRedirect{},
+		&model.Person{},
+		&model.VideoCredit{},
+		&model.VideoTitle{},
+		&model.VideoReleaseDate{},
+		&model.CatalogChange{},
+		&model.PageArchive{},
 	); err != nil {
 		zap.L().Error("auto migrate failed", zap.Error(err))
 	} else {
@@ -78,20 +93,32 @@ func InitMySQL() {
 
 	// 添加表注释（GORM AutoMigrate 不会自动添加表注释）
 	addTableComments()
+
+	// 创建站内搜索使用的全文索引（GORM AutoMigrate 不支持生成列和 ngram 全文索引）
+	ensureSearchIndexes()
 }
 
 // addTableComments 添加表注释
 // GORM 的 AutoMigrate 不会自动添加表注释，需要手动执行 SQL 语句
 func addTableComments() {
 	tableComments := map[string]string{
-		"users":          "用户表",
-		"user_tokens":   "用户登录控制表",
-		"videos":        "视频表",
-		"episodes":      "剧集表",
-		"danmakus":      "弹幕表",
-		"user_favorites": "用户收藏表",
-		"filter_info":   "视频表",
-		"app_versions":  "应用版本表",
+		"users":               "用户表",
+		"user_tokens":         "用户登录控制表",
+		"videos":              "视频表",
+		"episodes":            "剧集表",
+		"danmakus":            "弹幕表",
+		"user_favorites":      "用户收藏表",
+		"filter_info":         "筛选信息表",
+		"app_versions":        "应用版本表",
+		"webhook_deliveries":  "Webhook投递记录表",
+		"series":              "剧集系列表",
+		"video_redirects":     "视频重定向表",
+		"people":              "人物表",
+		"video_credits":       "视频演职员表",
+		"video_titles":        "视频别名表",
+		"video_release_dates": "视频上映日期表",
+		"catalog_changes":     "目录变更记录表",
+		"page_archives":       "页面归档表",
 	}
 
 	for tableName, comment := range tableComments {
@@ -102,3 +129,41 @@ func addTableComments() {
 	}
 	zap.L().Info("table comments applied")
 }
+
+// searchIndexes 站内搜索使用的ngram全文索引（与 migrations/init.sql 保持一致）
+// 表以模型指定：GORM 的 HasColumn 传入表名字符串时没有模型结构，会空指针panic
+var searchIndexes = []struct {
+	model interface{}
+	name  string
+	sql   string
+}{
+	{&model.Video{}, "ft_videos_title", "ALTER TABLE `videos` ADD FULLTEXT INDEX `ft_videos_title` (`title`) WITH PARSER ngram"},
+	{&model.Video{}, "ft_videos_description", "ALTER TABLE `videos` ADD FULLTEXT INDEX `ft_videos_description` (`description`) WITH PARSER ngram"},
+	{&model.Video{}, "ft_videos_credits", "ALTER TABLE `videos` ADD FULLTEXT INDEX `ft_videos_credits` (`credits_text`) WITH PARSER ngram"},
+	{&model.VideoTitle{}, "ft_video_titles_title", "ALTER TABLE `video_titles` ADD FULLTEXT INDEX `ft_video_titles_title` (`title`) WITH PARSER ngram"},
+}
+
+// ensureSearchIndexes 为已有数据库补充全文搜索需要的生成列和全文索引，已存在的跳过
+// 存量数据较多时首次创建索引需要一些时间
+func ensureSearchIndexes() {
+	if !DB.Migrator().HasColumn(&model.Video{}, "credits_text") {
+		sql := "ALTER TABLE `videos` ADD COLUMN `credits_text` TEXT " +
+			"GENERATED ALWAYS AS (CONCAT_WS(' ', JSON_UNQUOTE(`director_json`), JSON_UNQUOTE(`actors_json`))) STORED " +
+			"COMMENT '导演和演员（用于全文搜索，由JSON列生成）'"
+		if err := DB.Exec(sql).Error; err != nil {
+			zap.L().Warn("failed to add column videos.credits_text", zap.Error(err))
+			return
+		}
+	}
+
+	for _, index := range searchIndexes {
+		if DB.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := DB.Exec(index.sql).Error; err != nil {
+			zap.L().Warn(fmt.Sprintf("failed to create fulltext index %s", index.name), zap.Error(err))
+			continue
+		}
+		zap.L().Info("fulltext index created", zap.String("index", index.name))
+	}
+}
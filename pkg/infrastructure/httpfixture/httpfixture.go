@@ -0,0 +1,282 @@
+// httpfixture 包提供HTTP请求的录制/回放功能
+// 录制模式照常发起请求，并将请求和响应保存为夹具文件；回放模式只读取夹具文件返回响应，不访问网络，
+// 用于在CI和本地离线、可重复地运行依赖外部站点的流程（如豆瓣同步）
+package httpfixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 夹具模式
+const (
+	ModeRecord = "record" // 录制：发起真实请求并保存夹具
+	ModeReplay = "replay" // 回放：只从夹具返回响应
+)
+
+// ErrFixtureNotFound 回放模式下没有与请求对应的夹具
+var ErrFixtureNotFound = errors.New("未找到请求对应的夹具")
+
+// Fixture 夹具文件内容（一次请求及其响应）
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest 录制的请求（只保存用于匹配的部分，不保存请求头，避免泄露Cookie等凭据）
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// FixtureResponse 录制的响应，响应体为合法UTF-8文本时保存在 body，否则（如图片）以base64保存在 body_base64
+// 响应头不保存 credentialHeaders 中的凭据类响应头
+type FixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// credentialHeaders 录制时从响应头中删除的凭据类响应头（夹具会提交到仓库）
+var credentialHeaders = []string{"Set-Cookie", "Set-Cookie2", "Authorization", "Proxy-Authorization"}
+
+// Recorder 夹具录制/回放器，多个Transport可以共享同一个Recorder（并发安全）
+//
+// 夹具按请求方法、URL和请求体的哈希命名：{dir}/{host}/{hash}-{seq}.json，
+// seq 为本进程中相同请求的序号（从0开始），因此同一请求多次发起时按录制顺序依次回放，
+// 回放次数超过录制次数时重复返回最后一次录制的响应
+type Recorder struct {
+	mode string
+	dir  string
+
+	mu   sync.Mutex
+	seqs map[string]int // 请求键 → 已发起的次数
+}
+
+// NewRecorder 创建夹具录制/回放器
+//
+//	mode: record 或 replay
+//	dir: 夹具目录，录制模式下不存在时自动创建
+func NewRecorder(mode, dir string) (*Recorder, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("夹具目录不能为空")
+	}
+	switch mode {
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("创建夹具目录失败: %w", err)
+		}
+	case ModeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("夹具目录不可用: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("未知的夹具模式: %q", mode)
+	}
+	return &Recorder{mode: mode, dir: dir, seqs: make(map[string]int)}, nil
+}
+
+// Mode 返回夹具模式
+func (r *Recorder) Mode() string {
+	return r.mode
+}
+
+// Replaying 是否为回放模式
+func (r *Recorder) Replaying() bool {
+	return r.mode == ModeReplay
+}
+
+// Transport 返回使用该Recorder的 http.RoundTripper，base 为录制模式下实际发起请求的传输层（nil时使用 http.DefaultTransport）
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{rec: r, base: base}
+}
+
+// transport 录制/回放传输层
+type transport struct {
+	rec  *Recorder
+	base http.RoundTripper
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取请求体失败: %w", err)
+		}
+		reqBody = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	key := requestKey(req, reqBody)
+	seq := t.rec.next(key)
+
+	if t.rec.Replaying() {
+		return t.rec.replay(req, key, seq)
+	}
+	return t.rec.record(t.base, req, reqBody, key, seq)
+}
+
+// next 返回请求键本次的序号
+func (r *Recorder) next(key string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seq := r.seqs[key]
+	r.seqs[key] = seq + 1
+	return seq
+}
+
+// record 发起真实请求并保存夹具
+func (r *Recorder) record(base http.RoundTripper, req *http.Request, reqBody []byte, key string, seq int) (*http.Response, error) {
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Body:   string(reqBody),
+		},
+		Response: FixtureResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+		},
+	}
+	// 响应体已被读取和解压，长度和编码以夹具中的内容为准
+	fixture.Response.Header.Del("Content-Length")
+	fixture.Response.Header.Del("Content-Encoding")
+	for _, name := range credentialHeaders {
+		fixture.Response.Header.Del(name)
+	}
+	if utf8.Valid(body) {
+		fixture.Response.Body = string(body)
+	} else {
+		fixture.Response.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	if err := r.save(r.path(req, key, seq), &fixture); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// replay 返回录制的响应，没有第seq次的夹具时使用序号最大的夹具
+func (r *Recorder) replay(req *http.Request, key string, seq int) (*http.Response, error) {
+	for n := seq; n >= 0; n-- {
+		fixture, err := r.load(r.path(req, key, n))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return fixture.response(req)
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrFixtureNotFound, req.Method, req.URL.String())
+}
+
+// path 返回夹具文件路径
+func (r *Recorder) path(req *http.Request, key string, seq int) string {
+	host := strings.ReplaceAll(req.URL.Host, ":", "_")
+	if host == "" {
+		host = "_"
+	}
+	return filepath.Join(r.dir, host, fmt.Sprintf("%s-%d.json", key, seq))
+}
+
+// save 写入夹具文件
+func (r *Recorder) save(path string, fixture *Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化夹具失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建夹具目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入夹具失败: %w", err)
+	}
+	return nil
+}
+
+// load 读取夹具文件，文件不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)
+func (r *Recorder) load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("解析夹具失败 %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// response 将夹具转换为HTTP响应
+func (f *Fixture) response(req *http.Request) (*http.Response, error) {
+	body := []byte(f.Response.Body)
+	if f.Response.BodyBase64 != "" {
+		data, err := base64.StdEncoding.DecodeString(f.Response.BodyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("解码夹具响应体失败: %w", err)
+		}
+		body = data
+	}
+
+	header := f.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.StatusCode, http.StatusText(f.Response.StatusCode)),
+		StatusCode:    f.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// requestKey 返回请求的匹配键：方法、URL和请求体的SHA-256前16位十六进制
+// 请求头（User-Agent、Cookie等）不参与匹配
+func requestKey(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
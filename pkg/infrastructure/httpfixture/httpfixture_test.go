@@ -0,0 +1,256 @@
+package httpfixture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// failingTransport 回放模式下不应被调用的传输层
+type failingTransport struct {
+	t *testing.T
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("回放模式发起了真实请求: %s %s", req.Method, req.URL)
+	return nil, errors.New("unexpected request")
+}
+
+// newUpstream 启动测试上游：/text 返回文本，/binary 返回非UTF-8内容，/echo 返回请求体，/counter 每次返回递增的序号
+func newUpstream(t *testing.T) (*httptest.Server, *int64) {
+	t.Helper()
+	var hits int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Upstream", "yes")
+		w.Header().Set("Set-Cookie", "sid=upstream-token; Path=/")
+		fmt.Fprint(w, "你好, fixture")
+	})
+	mux.HandleFunc("/binary", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte{0x89, 'P', 'N', 'G', 0xff, 0xfe, 0x00})
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(append([]byte("echo:"), body...))
+	})
+	mux.HandleFunc("/counter", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&hits, 1)
+		fmt.Fprintf(w, "%d", n)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// do 发起请求并返回状态码、响应头和响应体
+func do(t *testing.T, client *http.Client, method, url string, body []byte) (int, http.Header, []byte) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Cookie", "session=secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败 %s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("读取响应失败: %v", err)
+	}
+	return resp.StatusCode, resp.Header, data
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	server, hits := newUpstream(t)
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(ModeRecord, dir)
+	if err != nil {
+		t.Fatalf("创建录制器失败: %v", err)
+	}
+	client := &http.Client{Transport: rec.Transport(nil)}
+
+	type call struct {
+		method string
+		path   string
+		body   []byte
+	}
+	calls := []call{
+		{http.MethodGet, "/text", nil},
+		{http.MethodGet, "/binary", nil},
+		{http.MethodPost, "/echo", []byte(`{"q":"a"}`)},
+		{http.MethodPost, "/echo", []byte(`{"q":"b"}`)},
+	}
+
+	type result struct {
+		code   int
+		header http.Header
+		body   []byte
+	}
+	recorded := make([]result, 0, len(calls))
+	for _, c := range calls {
+		code, header, body := do(t, client, c.method, server.URL+c.path, c.body)
+		recorded = append(recorded, result{code, header, body})
+	}
+	if got := atomic.LoadInt64(hits); got != int64(len(calls)) {
+		t.Fatalf("录制模式应发起 %d 次真实请求，实际 %d 次", len(calls), got)
+	}
+
+	replayer, err := NewRecorder(ModeReplay, dir)
+	if err != nil {
+		t.Fatalf("创建回放器失败: %v", err)
+	}
+	client = &http.Client{Transport: replayer.Transport(failingTransport{t})}
+	for i, c := range calls {
+		code, header, body := do(t, client, c.method, server.URL+c.path, c.body)
+		want := recorded[i]
+		if code != want.code {
+			t.Errorf("%s %s 状态码 = %d, 期望 %d", c.method, c.path, code, want.code)
+		}
+		if !bytes.Equal(body, want.body) {
+			t.Errorf("%s %s 响应体 = %q, 期望 %q", c.method, c.path, body, want.body)
+		}
+		if ct := header.Get("Content-Type"); ct != want.header.Get("Content-Type") {
+			t.Errorf("%s %s Content-Type = %q, 期望 %q", c.method, c.path, ct, want.header.Get("Content-Type"))
+		}
+	}
+	if got := atomic.LoadInt64(hits); got != int64(len(calls)) {
+		t.Errorf("回放模式不应访问上游，上游请求数 = %d", got)
+	}
+}
+
+func TestReplaySequence(t *testing.T) {
+	server, _ := newUpstream(t)
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(ModeRecord, dir)
+	if err != nil {
+		t.Fatalf("创建录制器失败: %v", err)
+	}
+	client := &http.Client{Transport: rec.Transport(nil)}
+	for i := 0; i < 2; i++ {
+		do(t, client, http.MethodGet, server.URL+"/counter", nil)
+	}
+
+	replayer, err := NewRecorder(ModeReplay, dir)
+	if err != nil {
+		t.Fatalf("创建回放器失败: %v", err)
+	}
+	client = &http.Client{Transport: replayer.Transport(failingTransport{t})}
+	// 按录制顺序回放，超出录制次数时重复最后一次的响应
+	for i, want := range []string{"1", "2", "2"} {
+		_, _, body := do(t, client, http.MethodGet, server.URL+"/counter", nil)
+		if string(body) != want {
+			t.Errorf("第 %d 次回放 = %q, 期望 %q", i+1, body, want)
+		}
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	server, _ := newUpstream(t)
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(ModeRecord, dir)
+	if err != nil {
+		t.Fatalf("创建录制器失败: %v", err)
+	}
+	do(t, &http.Client{Transport: rec.Transport(nil)}, http.MethodGet, server.URL+"/text", nil)
+
+	replayer, err := NewRecorder(ModeReplay, dir)
+	if err != nil {
+		t.Fatalf("创建回放器失败: %v", err)
+	}
+	client := &http.Client{Transport: replayer.Transport(failingTransport{t})}
+
+	// 请求体不同视为不同的请求
+	cases := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/binary", ""},
+		{http.MethodGet, "/text?page=2", ""},
+		{http.MethodPost, "/text", "x"},
+	}
+	for _, c := range cases {
+		var body io.Reader
+		if c.body != "" {
+			body = strings.NewReader(c.body)
+		}
+		req, _ := http.NewRequest(c.method, server.URL+c.path, body)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			t.Errorf("%s %s 没有夹具时应返回错误", c.method, c.path)
+			continue
+		}
+		if !errors.Is(err, ErrFixtureNotFound) {
+			t.Errorf("%s %s 错误 = %v, 期望 ErrFixtureNotFound", c.method, c.path, err)
+		}
+	}
+}
+
+func TestRecordOmitsCredentials(t *testing.T) {
+	server, _ := newUpstream(t)
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(ModeRecord, dir)
+	if err != nil {
+		t.Fatalf("创建录制器失败: %v", err)
+	}
+	do(t, &http.Client{Transport: rec.Transport(nil)}, http.MethodGet, server.URL+"/text", nil)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("应生成1个夹具文件，实际 %v (%v)", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("读取夹具失败: %v", err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Errorf("夹具不应包含请求头中的Cookie: %s", data)
+	}
+	if bytes.Contains(data, []byte("upstream-token")) {
+		t.Errorf("夹具不应包含响应头中的Set-Cookie: %s", data)
+	}
+	if !bytes.Contains(data, []byte("X-Upstream")) {
+		t.Errorf("非凭据类响应头应保留: %s", data)
+	}
+	if !bytes.Contains(data, []byte("你好, fixture")) {
+		t.Errorf("文本响应应直接保存在 body 中: %s", data)
+	}
+}
+
+func TestNewRecorderErrors(t *testing.T) {
+	if _, err := NewRecorder(ModeRecord, ""); err == nil {
+		t.Error("夹具目录为空时应返回错误")
+	}
+	if _, err := NewRecorder("live", t.TempDir()); err == nil {
+		t.Error("未知模式应返回错误")
+	}
+	if _, err := NewRecorder(ModeReplay, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("回放模式的夹具目录不存在时应返回错误")
+	}
+}
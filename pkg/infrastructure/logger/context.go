@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// contextKey 上下文中保存日志记录器的键
+type contextKey struct{}
+
+// WithContext 返回携带日志记录器的上下文
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 返回上下文中的日志记录器（通常已带上 trace_id、run_id 等字段），没有时返回全局日志记录器 zap.L()
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(*zap.Logger); ok && l != nil {
+			return l
+		}
+	}
+	return zap.L()
+}
+
+// With 在上下文的日志记录器上附加字段，返回携带该子记录器的新上下文和子记录器
+// 用法：ctx, log := logger.With(ctx, zap.Int64("video_id", id))
+func With(ctx context.Context, fields ...zap.Field) (context.Context, *zap.Logger) {
+	l := FromContext(ctx).With(fields...)
+	return WithContext(ctx, l), l
+}
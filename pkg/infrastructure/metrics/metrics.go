@@ -27,6 +27,61 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	// 同步请求上游（豆瓣、播放地址搜索接口等）的总数，请求失败（无响应）时状态为error
+	SyncUpstreamRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sync_upstream_requests_total",
+			Help: "Total number of upstream requests made by the sync pipeline",
+		},
+		[]string{"source", "endpoint", "status"},
+	)
+
+	// 同步请求上游的耗时直方图
+	SyncUpstreamRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "sync_upstream_request_duration_seconds",
+			Help:    "Upstream request duration of the sync pipeline in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"source", "endpoint"},
+	)
+
+	// 同步各阶段处理的条目数（result: saved/updated/skipped/failed）
+	SyncItemsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sync_items_total",
+			Help: "Total number of items processed by each sync stage",
+		},
+		[]string{"stage", "type", "result"},
+	)
+
+	// 同步各阶段耗时直方图（1秒到约4.5小时）
+	SyncStageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "sync_stage_duration_seconds",
+			Help:    "Duration of each sync stage in seconds",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 15),
+		},
+		[]string{"stage", "type"},
+	)
+
+	// 最近一次同步成功完成的时间（Unix秒）
+	SyncLastSuccessTimestamp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "sync_last_success_timestamp_seconds",
+			Help: "Unix timestamp of the last successful sync run",
+		},
+	)
+
+	// 播放地址搜索次数（result: matched/unmatched/failed），用于计算匹配率
+	SyncPlayURLSearchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sync_playurl_searches_total",
+			Help: "Total number of play URL searches by match result",
+		},
+		[]string{"type", "result"},
+	)
 )
 
 // InitMetrics 初始化Prometheus指标
@@ -35,6 +90,12 @@ func InitMetrics() {
 	// 注册自定义指标
 	prometheus.MustRegister(HTTPRequestsTotal)
 	prometheus.MustRegister(HTTPRequestDuration)
+	prometheus.MustRegister(SyncUpstreamRequestsTotal)
+	prometheus.MustRegister(SyncUpstreamRequestDuration)
+	prometheus.MustRegister(SyncItemsTotal)
+	prometheus.MustRegister(SyncStageDuration)
+	prometheus.MustRegister(SyncLastSuccessTimestamp)
+	prometheus.MustRegister(SyncPlayURLSearchesTotal)
 }
 
 // Handler 返回Prometheus指标HTTP处理器
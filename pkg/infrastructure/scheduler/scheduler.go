@@ -20,9 +20,10 @@ func InitCron() {
 
 	// 添加豆瓣同步任务：每天05:30、14:30、20:30执行
 	// Cron表达式: 0 30 5,14,20 * * * (每天3次)
-	doubanSyncService := service.NewDoubanSyncService()
 	_, err := cronScheduler.AddFunc("0 30 5,14,20 * * *", func() {
-		zap.L().Info("开始执行豆瓣同步任务")
+		// 每次执行创建新的同步服务实例，对应一次独立的同步运行
+		doubanSyncService := service.NewDoubanSyncService()
+		zap.L().Info("开始执行豆瓣同步任务", zap.String("run_id", doubanSyncService.RunID()))
 		if err := doubanSyncService.SyncAll(); err != nil {
 			zap.L().Error("豆瓣同步任务执行失败", zap.Error(err))
 		} else {
@@ -35,6 +36,18 @@ func InitCron() {
 		zap.L().Info("豆瓣同步定时任务已添加", zap.String("schedule", "每天05:30、14:30、20:30执行"))
 	}
 
+	// 添加视频去重任务：每天03:30执行（避开同步时段）
+	_, err = cronScheduler.AddFunc("0 30 3 * * *", func() {
+		if _, err := service.NewVideoDedupService().Run(false); err != nil {
+			zap.L().Error("视频去重任务执行失败", zap.Error(err))
+		}
+	})
+	if err != nil {
+		zap.L().Error("添加视频去重定时任务失败", zap.Error(err))
+	} else {
+		zap.L().Info("视频去重定时任务已添加", zap.String("schedule", "每天03:30执行"))
+	}
+
 	// 启动调度器
 	cronScheduler.Start()
 	zap.L().Info("定时任务调度器已启动")
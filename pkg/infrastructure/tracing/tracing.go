@@ -0,0 +1,130 @@
+// tracing 包提供OpenTelemetry分布式追踪功能
+// 初始化全局 TracerProvider 和W3C Trace Context传播器（traceparent），支持导出到OTLP（如本地Collector/Jaeger）或标准输出
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"video-service/pkg/infrastructure/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 导出器类型（配置项 tracing.exporter）
+const (
+	ExporterOTLP   = "otlp"   // OTLP/HTTP，默认发送到 localhost:4318
+	ExporterStdout = "stdout" // 输出到标准输出，便于本地调试
+)
+
+// defaultServiceName 上报的服务名默认值
+const defaultServiceName = "video-service"
+
+// instrumentationName 本服务创建的span使用的Tracer名称
+const instrumentationName = "video-service"
+
+// ServiceName 上报的服务名（tracing.service_name），InitTracing 之后有效
+var ServiceName = defaultServiceName
+
+// provider 全局 TracerProvider，未启用导出时为nil
+var provider *sdktrace.TracerProvider
+
+// InitTracing 初始化分布式追踪
+// 无论是否启用导出，都会设置W3C Trace Context传播器，入站请求的 traceparent 会传递到出站请求；
+// 配置项：
+//   - tracing.exporter: otlp / stdout，为空时不导出span
+//   - tracing.endpoint: OTLP/HTTP地址（如 localhost:4318），为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT 环境变量或默认值
+//   - tracing.insecure: OTLP是否使用HTTP（不使用TLS）
+//   - tracing.sample_ratio: 采样率（0~1，默认1），有父span时跟随父span的采样决定
+//   - tracing.service_name: 服务名（默认 video-service）
+func InitTracing() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if name := config.Cfg.GetString("tracing.service_name"); name != "" {
+		ServiceName = name
+	}
+
+	exporterType := config.Cfg.GetString("tracing.exporter")
+	if exporterType == "" {
+		zap.L().Info("tracing exporter empty, spans are not exported")
+		return
+	}
+
+	exporter, err := newExporter(exporterType)
+	if err != nil {
+		zap.L().Error("init tracing exporter failed, spans are not exported", zap.String("exporter", exporterType), zap.Error(err))
+		return
+	}
+
+	ratio := 1.0
+	if config.Cfg.IsSet("tracing.sample_ratio") {
+		ratio = config.Cfg.GetFloat64("tracing.sample_ratio")
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	zap.L().Info("tracing enabled", zap.String("exporter", exporterType), zap.Float64("sample_ratio", ratio))
+}
+
+// newExporter 按类型创建span导出器
+func newExporter(exporterType string) (sdktrace.SpanExporter, error) {
+	switch exporterType {
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if endpoint := config.Cfg.GetString("tracing.endpoint"); endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if config.Cfg.GetBool("tracing.insecure") {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("未知的导出器类型: %q", exporterType)
+	}
+}
+
+// Shutdown 导出剩余的span并关闭 TracerProvider（未启用导出时不处理）
+func Shutdown(ctx context.Context) {
+	if provider == nil {
+		return
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		zap.L().Warn("shutdown tracing provider failed", zap.Error(err))
+	}
+}
+
+// Tracer 返回本服务使用的Tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Detach 返回只保留ctx中span的新上下文，不继承ctx的取消和超时
+// 用于在请求处理结束后仍继续执行的后台任务（如手动触发的同步），使其span挂在请求的span下
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// RecordError 在span上记录错误并将状态设为Error（err为nil时不处理）
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}